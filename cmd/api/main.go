@@ -9,6 +9,10 @@ import (
 	"github.com/yourusername/seller-assistant/internal/api"
 	"github.com/yourusername/seller-assistant/internal/api/middleware"
 	"github.com/yourusername/seller-assistant/internal/config"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/internal/marketplace/kaspi"
+	"github.com/yourusername/seller-assistant/internal/marketplace/ozon"
+	"github.com/yourusername/seller-assistant/internal/marketplace/wildberries"
 	"github.com/yourusername/seller-assistant/internal/repository/mongodb"
 	"github.com/yourusername/seller-assistant/internal/service"
 	"github.com/yourusername/seller-assistant/pkg/crypto"
@@ -41,6 +45,16 @@ func main() {
 		zap.String("port", cfg.Port),
 	)
 
+	if cfg.KaspiBaseURL != "" {
+		logger.Log.Warn("Using custom Kaspi API base URL", zap.String("kaspi_base_url", cfg.KaspiBaseURL))
+	}
+	if cfg.WildberriesBaseURL != "" {
+		logger.Log.Warn("Using custom Wildberries API base URL", zap.String("wildberries_base_url", cfg.WildberriesBaseURL))
+	}
+	if cfg.OzonBaseURL != "" {
+		logger.Log.Warn("Using custom Ozon API base URL", zap.String("ozon_base_url", cfg.OzonBaseURL))
+	}
+
 	// Initialize MongoDB
 	db, err := mongodb.NewDB(cfg.MongoDBURI, cfg.MongoDBDatabase)
 	if err != nil {
@@ -49,11 +63,20 @@ func main() {
 	defer db.Close()
 
 	userRepo := mongodb.NewUserRepository(db)
-	kaspiKeyRepo := mongodb.NewKaspiKeyRepository(db)
+	connectionRepo := mongodb.NewConnectionRepository(db)
 	productRepo := mongodb.NewProductRepository(db)
+	orderRepo := mongodb.NewOrderRepository(db)
 	reviewRepo := mongodb.NewReviewRepository(db)
 	salesHistoryRepo := mongodb.NewSalesHistoryRepository(db)
 	lowStockAlertRepo := mongodb.NewLowStockAlertRepository(db)
+	priceFeedRepo := mongodb.NewPriceFeedRepository(db)
+	tagStrategyRepo := mongodb.NewTagPricingStrategyRepository(db)
+	competitorFilterRepo := mongodb.NewCompetitorFilterRepository(db)
+	priceChangeRepo := mongodb.NewPriceChangeRepository(db)
+	offerSnapshotRepo := mongodb.NewOfferSnapshotRepository(db)
+	priceProposalRepo := mongodb.NewPriceProposalRepository(db)
+	priceGuardRepo := mongodb.NewPriceGuardRepository(db)
+	priceGuardAlertRepo := mongodb.NewPriceGuardAlertRepository(db)
 
 	// Ensure MongoDB indexes
 	if err := userRepo.EnsureIndexes(); err != nil {
@@ -71,25 +94,75 @@ func main() {
 
 	// Initialize services
 	aiResponder := service.NewAIResponderService(cfg.OpenAIAPIKey, reviewRepo)
-	inventoryService := service.NewInventoryService(productRepo, salesHistoryRepo, lowStockAlertRepo)
-	syncService := service.NewKaspiSyncService(
-		kaspiKeyRepo,
+	inventoryService := service.NewInventoryService(productRepo, salesHistoryRepo, orderRepo, lowStockAlertRepo)
+	// Marketplace clients are built through a registry keyed by marketplace type
+	registry := marketplace.NewRegistry()
+	registry.Register(marketplace.TypeKaspi, kaspi.NewFactory(
+		kaspi.WithBaseURL(cfg.KaspiBaseURL),
+		kaspi.WithPageSize(cfg.KaspiPageSize),
+		kaspi.WithMaxPages(cfg.KaspiMaxPages),
+		kaspi.WithRateLimit(float64(cfg.KaspiRateLimitRPS), cfg.KaspiRateBurst),
+		kaspi.WithRetryPolicy(kaspi.RetryPolicy{
+			MaxAttempts: cfg.KaspiMaxAttempts,
+			BaseDelay:   kaspi.DefaultRetryPolicy.BaseDelay,
+			MaxDelay:    kaspi.DefaultRetryPolicy.MaxDelay,
+		}),
+	))
+	registry.Register(marketplace.TypeWildberries, wildberries.NewFactory(
+		wildberries.WithBaseURL(cfg.WildberriesBaseURL),
+	))
+	registry.Register(marketplace.TypeOzon, ozon.NewFactory(
+		ozon.WithBaseURL(cfg.OzonBaseURL),
+	))
+	clients := service.NewMarketplaceClients(registry, encryptor)
+	feedService := service.NewPriceFeedService(productRepo, priceFeedRepo)
+
+	syncService := service.NewSyncService(
+		connectionRepo,
 		productRepo,
+		orderRepo,
 		salesHistoryRepo,
 		reviewRepo,
-		encryptor,
+		clients,
 		inventoryService,
+		feedService,
 	)
-	// priceDumpingService := service.NewPriceDumpingService(kaspiKeyRepo, productRepo, encryptor) // Temporarily disabled
+	orderService := service.NewOrderService(orderRepo, connectionRepo, clients)
+	stockService := service.NewStockService(connectionRepo, productRepo, clients, feedService)
+	priceHistoryService := service.NewPriceHistoryService(priceChangeRepo, productRepo, cfg.PriceHistoryDays)
+	competitorHistoryService := service.NewCompetitorHistoryService(offerSnapshotRepo, productRepo, cfg.OfferHistoryDays)
+	pricingService := service.NewPricingService(connectionRepo, productRepo, tagStrategyRepo, competitorFilterRepo, clients, feedService, priceHistoryService)
+	dumpingReportService := service.NewDumpingReportService(priceProposalRepo)
+	priceGuardService := service.NewPriceGuardService(priceGuardRepo, priceGuardAlertRepo, productRepo, priceHistoryService)
+	backtestService := service.NewBacktestService(productRepo, tagStrategyRepo, competitorFilterRepo, offerSnapshotRepo, salesHistoryRepo, priceGuardService)
+	// priceDumpingService := service.NewPriceDumpingService(userRepo, connectionRepo, productRepo, tagStrategyRepo, competitorFilterRepo, clients, feedService, priceHistoryService, competitorHistoryService, priceProposalRepo, priceGuardService) // Temporarily disabled
 
 	// Setup router
 	routerCfg := &api.RouterConfig{
 		UserRepo:           userRepo,
-		KaspiKeyRepo:       kaspiKeyRepo,
+		ConnectionRepo:     connectionRepo,
 		ProductRepo:        productRepo,
+		OrderRepo:          orderRepo,
 		ReviewRepo:         reviewRepo,
+		SalesHistoryRepo:   salesHistoryRepo,
 		AIResponder:        aiResponder,
 		SyncService:        syncService,
+		Clients:            clients,
+		OrderService:       orderService,
+		StockService:       stockService,
+		PricingService:     pricingService,
+		PriceHistory:       priceHistoryService,
+		CompetitorHistory:  competitorHistoryService,
+		DumpingReport:      dumpingReportService,
+		Backtests:          backtestService,
+		PriceGuards:        priceGuardService,
+		PriceFeedRepo:      priceFeedRepo,
+		PriceChangeRepo:    priceChangeRepo,
+		OfferSnapshotRepo:  offerSnapshotRepo,
+		PriceProposalRepo:  priceProposalRepo,
+		GuardAlertRepo:     priceGuardAlertRepo,
+		FeedService:        feedService,
+		PublicBaseURL:      cfg.PublicBaseURL,
 		Encryptor:          encryptor,
 		JWTSecret:          cfg.JWTSecret,
 		JWTExpirationHours: cfg.JWTExpirationHours,
@@ -116,6 +189,7 @@ func main() {
 		zap.String("me", "GET /api/v1/auth/me (auth)"),
 		zap.String("profile", "GET /api/v1/user/profile (auth)"),
 		zap.String("products", "GET /api/v1/products (auth)"),
+		zap.String("orders", "GET /api/v1/orders (auth)"),
 		zap.String("reviews", "GET /api/v1/reviews (auth)"),
 		zap.String("dashboard", "GET /api/v1/dashboard/stats (auth)"),
 	)
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/config"
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/repository/mongodb"
+	"github.com/yourusername/seller-assistant/internal/service"
+	"github.com/yourusername/seller-assistant/pkg/logger"
+)
+
+// backtest replays a product's recorded competitor offers through two pricing strategies
+// and prints them side by side with the prices the product actually had, e.g.
+//
+//	go run ./cmd/backtest -product <id> -days 14 -strategy '{"type":"target_rank","rank":2}'
+//
+// Without -compare the strategy is compared with the product's current one.
+func main() {
+	productID := flag.String("product", "", "product ID (required)")
+	days := flag.Int("days", service.DefaultBacktestDays, "days of history to replay")
+	city := flag.String("city", "", "city to replay, required for products priced by city")
+	strategyJSON := flag.String("strategy", "", `strategy to test as JSON, e.g. {"type":"undercut_amount","amount":1} (required)`)
+	compareJSON := flag.String("compare", "", "strategy to compare with as JSON (the product's current strategy if empty)")
+	asJSON := flag.Bool("json", false, "print the full report as JSON")
+	flag.Parse()
+
+	if *productID == "" || *strategyJSON == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	req := service.BacktestRequest{Days: *days, City: *city}
+	if err := json.Unmarshal([]byte(*strategyJSON), &req.Strategy); err != nil {
+		log.Fatalf("Invalid -strategy: %v", err)
+	}
+	if *compareJSON != "" {
+		var compareWith domain.PricingStrategyConfig
+		if err := json.Unmarshal([]byte(*compareJSON), &compareWith); err != nil {
+			log.Fatalf("Invalid -compare: %v", err)
+		}
+		req.CompareWith = &compareWith
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if err := logger.Init(cfg.LogLevel); err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Sync()
+
+	// Backtests only read, so the schema is left to the API server
+	db, err := mongodb.Connect(cfg.MongoDBURI, cfg.MongoDBDatabase)
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer db.Close()
+
+	productRepo := mongodb.NewProductRepository(db)
+	priceHistory := service.NewPriceHistoryService(mongodb.NewPriceChangeRepository(db), productRepo, cfg.PriceHistoryDays)
+	backtests := service.NewBacktestService(
+		productRepo,
+		mongodb.NewTagPricingStrategyRepository(db),
+		mongodb.NewCompetitorFilterRepository(db),
+		mongodb.NewOfferSnapshotRepository(db),
+		mongodb.NewSalesHistoryRepository(db),
+		service.NewPriceGuardService(mongodb.NewPriceGuardRepository(db), mongodb.NewPriceGuardAlertRepository(db), productRepo, priceHistory),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	product, err := productRepo.GetByID(ctx, *productID)
+	if err != nil {
+		log.Fatalf("Failed to get product: %v", err)
+	}
+	if product == nil {
+		log.Fatalf("Product %s not found", *productID)
+	}
+
+	report, err := backtests.Run(ctx, product, req)
+	if err != nil {
+		log.Fatalf("Backtest failed: %v", err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			log.Fatalf("Failed to write report: %v", err)
+		}
+		return
+	}
+
+	printReport(report)
+}
+
+// printReport writes the report as a table with a column per strategy
+func printReport(r *service.BacktestReport) {
+	fmt.Printf("%s (%s)", r.ProductName, r.ProductID)
+	if r.City != "" {
+		fmt.Printf(", %s", r.City)
+	}
+	fmt.Printf("\n%s - %s, %d observations, %d units sold\n", r.From.Format(time.DateTime), r.To.Format(time.DateTime), r.Observations, r.UnitsSold)
+	fmt.Printf("floor %.2f, ceiling %.2f\n\n", r.Floor, r.Ceiling)
+
+	results := []service.BacktestResult{r.Actual, r.Strategy, r.CompareWith}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	row := func(label string, value func(service.BacktestResult) string) {
+		fmt.Fprintf(w, "%s\t", label)
+		for _, res := range results {
+			fmt.Fprintf(w, "%s\t", value(res))
+		}
+		fmt.Fprintln(w)
+	}
+
+	row("", func(res service.BacktestResult) string {
+		if res.Strategy == nil {
+			return "actual"
+		}
+		return res.Strategy.Type
+	})
+	row("changes", func(res service.BacktestResult) string { return fmt.Sprint(res.Changes) })
+	row("floor hits", func(res service.BacktestResult) string { return fmt.Sprint(res.FloorHits) })
+	row("guard pauses", func(res service.BacktestResult) string { return fmt.Sprint(res.GuardPauses) })
+	row("final price", func(res service.BacktestResult) string { return fmt.Sprintf("%.2f", res.FinalPrice) })
+	row("min price", func(res service.BacktestResult) string { return fmt.Sprintf("%.2f", res.MinPrice) })
+	row("max price", func(res service.BacktestResult) string { return fmt.Sprintf("%.2f", res.MaxPrice) })
+	row("average price", func(res service.BacktestResult) string { return fmt.Sprintf("%.2f", res.AveragePrice) })
+	row("est. revenue", func(res service.BacktestResult) string { return fmt.Sprintf("%.2f", res.EstimatedRevenue) })
+	row("est. profit", func(res service.BacktestResult) string {
+		if res.EstimatedProfit == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%.2f", *res.EstimatedProfit)
+	})
+	row("margin %", func(res service.BacktestResult) string {
+		if res.MarginPercent == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%.2f", *res.MarginPercent)
+	})
+	w.Flush()
+
+	fmt.Printf("\nrevenue diff %.2f", r.Comparison.RevenueDiff)
+	if r.Comparison.ProfitDiff != nil {
+		fmt.Printf(", profit diff %.2f", *r.Comparison.ProfitDiff)
+	}
+	fmt.Printf(", better: %s\n", r.Comparison.Better)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace/kaspi/kaspitest"
+)
+
+// kaspi-fake serves the Kaspi merchant API from local fixtures so the API and
+// worker can be run against it by setting KASPI_BASE_URL=http://localhost:8090
+func main() {
+	addr := flag.String("addr", ":8090", "listen address")
+	fixturesPath := flag.String("fixtures", "", "path to a JSON fixtures file (built-in demo data if empty)")
+	merchantID := flag.String("merchant", "", "override merchant ID from fixtures")
+	flag.Parse()
+
+	fixtures := kaspitest.DefaultFixtures()
+	if *fixturesPath != "" {
+		f, err := kaspitest.LoadFixtures(*fixturesPath)
+		if err != nil {
+			log.Fatalf("Failed to load fixtures: %v", err)
+		}
+		fixtures = f
+	}
+
+	if *merchantID != "" {
+		fixtures.MerchantID = *merchantID
+	}
+
+	handler := kaspitest.NewHandler(fixtures)
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           logRequests(handler),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("Fake Kaspi API listening on %s (merchant: %s, products: %d)",
+		*addr, fixtures.MerchantID, len(fixtures.Products))
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatalf("Server failed: %v", err)
+	}
+}
+
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		log.Printf("%s %s (%s)", r.Method, r.URL.RequestURI(), time.Since(start))
+	})
+}
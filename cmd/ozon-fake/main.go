@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace/ozon/ozontest"
+)
+
+// ozon-fake serves the Ozon Seller API from local fixtures so the API and
+// worker can be run against it by setting OZON_BASE_URL=http://localhost:8092
+func main() {
+	addr := flag.String("addr", ":8092", "listen address")
+	fixturesPath := flag.String("fixtures", "", "path to a JSON fixtures file (built-in demo data if empty)")
+	clientID := flag.String("client-id", "", "override seller Client-Id from fixtures")
+	flag.Parse()
+
+	fixtures := ozontest.DefaultFixtures()
+	if *fixturesPath != "" {
+		f, err := ozontest.LoadFixtures(*fixturesPath)
+		if err != nil {
+			log.Fatalf("Failed to load fixtures: %v", err)
+		}
+		fixtures = f
+	}
+
+	if *clientID != "" {
+		fixtures.ClientID = *clientID
+	}
+
+	handler := ozontest.NewHandler(fixtures)
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           logRequests(handler),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("Stub Ozon API listening on %s (client: %s, products: %d)",
+		*addr, fixtures.ClientID, len(fixtures.Products))
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatalf("Server failed: %v", err)
+	}
+}
+
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		log.Printf("%s %s (%s)", r.Method, r.URL.RequestURI(), time.Since(start))
+	})
+}
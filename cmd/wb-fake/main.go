@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace/wildberries/wbtest"
+)
+
+// wb-fake serves the Wildberries seller APIs from local fixtures so the API and
+// worker can be run against it by setting WILDBERRIES_BASE_URL=http://localhost:8091
+func main() {
+	addr := flag.String("addr", ":8091", "listen address")
+	fixturesPath := flag.String("fixtures", "", "path to a JSON fixtures file (built-in demo data if empty)")
+	token := flag.String("token", "", "only accept this API token (any token if empty)")
+	flag.Parse()
+
+	fixtures := wbtest.DefaultFixtures()
+	if *fixturesPath != "" {
+		f, err := wbtest.LoadFixtures(*fixturesPath)
+		if err != nil {
+			log.Fatalf("Failed to load fixtures: %v", err)
+		}
+		fixtures = f
+	}
+
+	if *token != "" {
+		fixtures.Token = *token
+	}
+
+	handler := wbtest.NewHandler(fixtures)
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           logRequests(handler),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("Fake Wildberries API listening on %s (cards: %d)", *addr, len(fixtures.Cards))
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatalf("Server failed: %v", err)
+	}
+}
+
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		log.Printf("%s %s (%s)", r.Method, r.URL.RequestURI(), time.Since(start))
+	})
+}
@@ -1,18 +1,23 @@
 package main
 
 import (
+	"context"
+	"log"
+	"os/signal"
+	"syscall"
+
 	"github.com/yourusername/seller-assistant/internal/config"
 	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/internal/marketplace/kaspi"
+	"github.com/yourusername/seller-assistant/internal/marketplace/ozon"
+	"github.com/yourusername/seller-assistant/internal/marketplace/wildberries"
 	"github.com/yourusername/seller-assistant/internal/repository/mongodb"
 	"github.com/yourusername/seller-assistant/internal/service"
 	"github.com/yourusername/seller-assistant/pkg/crypto"
 	"github.com/yourusername/seller-assistant/pkg/logger"
 	"github.com/yourusername/seller-assistant/pkg/scheduler"
 	"go.uber.org/zap"
-	"log"
-	"os"
-	"os/signal"
-	"syscall"
 )
 
 func main() {
@@ -33,6 +38,16 @@ func main() {
 		zap.Int("sync_interval_hours", cfg.SyncIntervalHours),
 	)
 
+	if cfg.KaspiBaseURL != "" {
+		logger.Log.Warn("Using custom Kaspi API base URL", zap.String("kaspi_base_url", cfg.KaspiBaseURL))
+	}
+	if cfg.WildberriesBaseURL != "" {
+		logger.Log.Warn("Using custom Wildberries API base URL", zap.String("wildberries_base_url", cfg.WildberriesBaseURL))
+	}
+	if cfg.OzonBaseURL != "" {
+		logger.Log.Warn("Using custom Ozon API base URL", zap.String("ozon_base_url", cfg.OzonBaseURL))
+	}
+
 	// Initialize MongoDB
 	db, err := mongodb.NewDB(cfg.MongoDBURI, cfg.MongoDBDatabase)
 	if err != nil {
@@ -42,6 +57,11 @@ func main() {
 
 	logger.Log.Info("MongoDB connected successfully")
 
+	// Root context for all jobs; cancelled on SIGINT/SIGTERM so in-flight
+	// Kaspi and MongoDB calls are aborted during shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	// Initialize encryptor
 	encryptor, err := crypto.NewEncryptor(cfg.EncryptionKey)
 	if err != nil {
@@ -50,16 +70,21 @@ func main() {
 
 	// Initialize repositories
 	userRepo := mongodb.NewUserRepository(db)
-	kaspiKeyRepo := mongodb.NewKaspiKeyRepository(db)
+	connectionRepo := mongodb.NewConnectionRepository(db)
 	productRepo := mongodb.NewProductRepository(db)
+	orderRepo := mongodb.NewOrderRepository(db)
 	salesHistoryRepo := mongodb.NewSalesHistoryRepository(db)
 	reviewRepo := mongodb.NewReviewRepository(db)
 	lowStockAlertRepo := mongodb.NewLowStockAlertRepository(db)
+	priceFeedRepo := mongodb.NewPriceFeedRepository(db)
+	priceChangeRepo := mongodb.NewPriceChangeRepository(db)
+	offerSnapshotRepo := mongodb.NewOfferSnapshotRepository(db)
 
 	// Initialize services
 	inventoryService := service.NewInventoryService(
 		productRepo,
 		salesHistoryRepo,
+		orderRepo,
 		lowStockAlertRepo,
 	)
 
@@ -68,38 +93,80 @@ func main() {
 		reviewRepo,
 	)
 
-	syncService := service.NewKaspiSyncService(
-		kaspiKeyRepo,
+	// Marketplace clients are built through a registry keyed by marketplace type
+	registry := marketplace.NewRegistry()
+	registry.Register(marketplace.TypeKaspi, kaspi.NewFactory(
+		kaspi.WithBaseURL(cfg.KaspiBaseURL),
+		kaspi.WithPageSize(cfg.KaspiPageSize),
+		kaspi.WithMaxPages(cfg.KaspiMaxPages),
+		kaspi.WithRateLimit(float64(cfg.KaspiRateLimitRPS), cfg.KaspiRateBurst),
+		kaspi.WithRetryPolicy(kaspi.RetryPolicy{
+			MaxAttempts: cfg.KaspiMaxAttempts,
+			BaseDelay:   kaspi.DefaultRetryPolicy.BaseDelay,
+			MaxDelay:    kaspi.DefaultRetryPolicy.MaxDelay,
+		}),
+	))
+	registry.Register(marketplace.TypeWildberries, wildberries.NewFactory(
+		wildberries.WithBaseURL(cfg.WildberriesBaseURL),
+	))
+	registry.Register(marketplace.TypeOzon, ozon.NewFactory(
+		ozon.WithBaseURL(cfg.OzonBaseURL),
+	))
+	clients := service.NewMarketplaceClients(registry, encryptor)
+	feedService := service.NewPriceFeedService(productRepo, priceFeedRepo)
+
+	syncService := service.NewSyncService(
+		connectionRepo,
 		productRepo,
+		orderRepo,
 		salesHistoryRepo,
 		reviewRepo,
-		encryptor,
+		clients,
 		inventoryService,
+		feedService,
 	)
 
-	// TEMPORARILY DISABLED - Price Dumping
-	/*
-		priceDumpingService := service.NewPriceDumpingService(
-			kaspiKeyRepo,
+	priceHistoryService := service.NewPriceHistoryService(priceChangeRepo, productRepo, cfg.PriceHistoryDays)
+	competitorHistoryService := service.NewCompetitorHistoryService(offerSnapshotRepo, productRepo, cfg.OfferHistoryDays)
+
+	// Price dumping is off unless PRICE_DUMPING_MODE enables it; dry_run only proposes prices
+	var priceDumpingService *service.PriceDumpingService
+	if cfg.PriceDumpingMode != config.PriceDumpingOff {
+		priceDumpingService = service.NewPriceDumpingService(
+			userRepo,
+			connectionRepo,
 			productRepo,
-			encryptor,
+			mongodb.NewTagPricingStrategyRepository(db),
+			mongodb.NewCompetitorFilterRepository(db),
+			clients,
+			feedService,
+			priceHistoryService,
+			competitorHistoryService,
+			mongodb.NewPriceProposalRepository(db),
+			service.NewPriceGuardService(
+				mongodb.NewPriceGuardRepository(db),
+				mongodb.NewPriceGuardAlertRepository(db),
+				productRepo,
+				priceHistoryService,
+			),
 		)
-	*/
+		priceDumpingService.SetDryRunOnly(cfg.PriceDumpingMode == config.PriceDumpingDryRun)
+	}
 
 	// Initialize scheduler
 	sched := scheduler.New()
 
-	// Schedule Kaspi sync
+	// Schedule marketplace sync
 	err = sched.AddIntervalJob(cfg.SyncIntervalHours, func() {
-		logger.Log.Info("Starting scheduled Kaspi sync")
+		logger.Log.Info("Starting scheduled marketplace sync")
 
-		// Sync all Kaspi accounts
-		if err := syncService.SyncAll(); err != nil {
-			logger.Log.Error("Kaspi sync failed", zap.Error(err))
+		// Sync all marketplace connections
+		if err := syncService.SyncAll(ctx); err != nil {
+			logger.Log.Error("Marketplace sync failed", zap.Error(err))
 		}
 
 		// Get all users with auto-reply enabled
-		users, err := getUsersWithAutoReply(userRepo)
+		users, err := getUsersWithAutoReply(ctx, userRepo)
 		if err != nil {
 			logger.Log.Error("Failed to get users with auto-reply", zap.Error(err))
 			return
@@ -107,7 +174,7 @@ func main() {
 
 		for _, user := range users {
 			// Process AI responses for pending reviews
-			if err := aiResponder.ProcessPendingReviews(user.ID, true); err != nil {
+			if err := aiResponder.ProcessPendingReviews(ctx, user.ID, true); err != nil {
 				logger.Log.Error("Failed to process pending reviews",
 					zap.String("user_id", user.ID),
 					zap.Error(err),
@@ -115,7 +182,7 @@ func main() {
 			}
 
 			// Process low stock alerts
-			if err := inventoryService.ProcessLowStockAlerts(user.ID, 7); err != nil {
+			if err := inventoryService.ProcessLowStockAlerts(ctx, user.ID, 7); err != nil {
 				logger.Log.Error("Failed to process low stock alerts",
 					zap.String("user_id", user.ID),
 					zap.Error(err),
@@ -130,43 +197,57 @@ func main() {
 		logger.Log.Fatal("Failed to schedule sync job", zap.Error(err))
 	}
 
-	// TEMPORARILY DISABLED - Price Dumping
+	// Prune price and competitor history past their retention periods (daily at 03:00)
+	err = sched.AddJob("0 3 * * *", func() {
+		if err := priceHistoryService.Prune(ctx); err != nil {
+			logger.Log.Error("Price history pruning failed", zap.Error(err))
+		}
+		if err := competitorHistoryService.Prune(ctx); err != nil {
+			logger.Log.Error("Competitor history pruning failed", zap.Error(err))
+		}
+	})
+
+	if err != nil {
+		logger.Log.Fatal("Failed to schedule price history pruning job", zap.Error(err))
+	}
+
 	// Schedule price dumping (every 5 minutes)
-	// err = sched.AddJob("*/5 * * * *", func() {
-	// 	logger.Log.Info("Starting price dumping cycle")
-	//
-	// 	if err := priceDumpingService.ProcessAllUsers(); err != nil {
-	// 		logger.Log.Error("Price dumping failed", zap.Error(err))
-	// 	}
-	//
-	// 	logger.Log.Info("Price dumping cycle completed")
-	// })
-	//
-	// if err != nil {
-	// 	logger.Log.Fatal("Failed to schedule price dumping job", zap.Error(err))
-	// }
+	if priceDumpingService != nil {
+		err = sched.AddJob("*/5 * * * *", func() {
+			logger.Log.Info("Starting price dumping cycle", zap.String("mode", cfg.PriceDumpingMode))
+
+			if err := priceDumpingService.ProcessAllUsers(ctx); err != nil {
+				logger.Log.Error("Price dumping failed", zap.Error(err))
+			}
+
+			logger.Log.Info("Price dumping cycle completed")
+		})
+
+		if err != nil {
+			logger.Log.Fatal("Failed to schedule price dumping job", zap.Error(err))
+		}
+	}
 
 	// Run initial sync immediately
 	logger.Log.Info("Running initial sync...")
-	if err := syncService.SyncAll(); err != nil {
+	if err := syncService.SyncAll(ctx); err != nil {
 		logger.Log.Error("Initial sync failed", zap.Error(err))
 	}
 
-	// TEMPORARILY DISABLED - Price Dumping
 	// Run initial price dumping
-	// logger.Log.Info("Running initial price dumping...")
-	// if err := priceDumpingService.ProcessAllUsers(); err != nil {
-	// 	logger.Log.Error("Initial price dumping failed", zap.Error(err))
-	// }
+	if priceDumpingService != nil {
+		logger.Log.Info("Running initial price dumping...", zap.String("mode", cfg.PriceDumpingMode))
+		if err := priceDumpingService.ProcessAllUsers(ctx); err != nil {
+			logger.Log.Error("Initial price dumping failed", zap.Error(err))
+		}
+	}
 
 	// Start scheduler
 	sched.Start()
 	logger.Log.Info("Worker started, scheduler is running")
 
 	// Wait for interrupt signal
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	<-ctx.Done()
 
 	logger.Log.Info("Shutting down worker...")
 	sched.Stop()
@@ -174,7 +255,7 @@ func main() {
 
 }
 
-func getUsersWithAutoReply(userRepo domain.UserRepository) ([]domain.User, error) {
+func getUsersWithAutoReply(ctx context.Context, userRepo domain.UserRepository) ([]domain.User, error) {
 	// This would need a new method in the repository
 	// For now, we'll return an empty slice
 	// You can implement GetUsersWithAutoReply() in the user repository
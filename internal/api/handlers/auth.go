@@ -62,7 +62,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
 
 	// Check if user with this email already exists
-	existingUser, err := h.userRepo.GetByEmail(req.Email)
+	existingUser, err := h.userRepo.GetByEmail(c.Request.Context(), req.Email)
 	if err != nil {
 		logger.Log.Error("Failed to check existing user", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Registration failed"})
@@ -98,7 +98,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		AutoDumpingEnabled: false,
 	}
 
-	if err := h.userRepo.Create(user); err != nil {
+	if err := h.userRepo.Create(c.Request.Context(), user); err != nil {
 		logger.Log.Error("Failed to create user", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Registration failed"})
 		return
@@ -136,7 +136,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
 
 	// Find user by email
-	user, err := h.userRepo.GetByEmail(req.Email)
+	user, err := h.userRepo.GetByEmail(c.Request.Context(), req.Email)
 	if err != nil {
 		logger.Log.Error("Failed to get user", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Login failed"})
@@ -177,7 +177,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 func (h *AuthHandler) GetMe(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 
-	user, err := h.userRepo.GetByID(userID)
+	user, err := h.userRepo.GetByID(c.Request.Context(), userID)
 	if err != nil {
 		logger.Log.Error("Failed to get user", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
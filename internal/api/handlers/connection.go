@@ -0,0 +1,402 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/seller-assistant/internal/api/middleware"
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/internal/service"
+	"github.com/yourusername/seller-assistant/pkg/crypto"
+	"github.com/yourusername/seller-assistant/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type ConnectionHandler struct {
+	connectionRepo   domain.MarketplaceConnectionRepository
+	productRepo      domain.ProductRepository
+	orderRepo        domain.OrderRepository
+	reviewRepo       domain.ReviewRepository
+	salesHistoryRepo domain.SalesHistoryRepository
+	priceFeedRepo    domain.PriceFeedRepository
+	priceChangeRepo  domain.PriceChangeRepository
+	offerRepo        domain.OfferSnapshotRepository
+	proposalRepo     domain.PriceProposalRepository
+	guardAlertRepo   domain.PriceGuardAlertRepository
+	encryptor        *crypto.Encryptor
+	syncService      *service.SyncService
+	clients          *service.MarketplaceClients
+}
+
+func NewConnectionHandler(
+	connectionRepo domain.MarketplaceConnectionRepository,
+	productRepo domain.ProductRepository,
+	orderRepo domain.OrderRepository,
+	reviewRepo domain.ReviewRepository,
+	salesHistoryRepo domain.SalesHistoryRepository,
+	priceFeedRepo domain.PriceFeedRepository,
+	priceChangeRepo domain.PriceChangeRepository,
+	offerRepo domain.OfferSnapshotRepository,
+	proposalRepo domain.PriceProposalRepository,
+	guardAlertRepo domain.PriceGuardAlertRepository,
+	encryptor *crypto.Encryptor,
+	syncService *service.SyncService,
+	clients *service.MarketplaceClients,
+) *ConnectionHandler {
+	return &ConnectionHandler{
+		connectionRepo:   connectionRepo,
+		productRepo:      productRepo,
+		orderRepo:        orderRepo,
+		reviewRepo:       reviewRepo,
+		salesHistoryRepo: salesHistoryRepo,
+		priceFeedRepo:    priceFeedRepo,
+		priceChangeRepo:  priceChangeRepo,
+		offerRepo:        offerRepo,
+		proposalRepo:     proposalRepo,
+		guardAlertRepo:   guardAlertRepo,
+		encryptor:        encryptor,
+		syncService:      syncService,
+		clients:          clients,
+	}
+}
+
+// CreateConnectionRequest represents request to connect a marketplace account
+type CreateConnectionRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Marketplace string `json:"marketplace" binding:"required"`
+	APIKey      string `json:"api_key" binding:"required"`
+	APISecret   string `json:"api_secret"`
+	MerchantID  string `json:"merchant_id"` // Kaspi merchant ID or Ozon Client-Id
+	// Kaspi store IDs listed in the XML price feed; defaults to PP1
+	PickupPoints []string `json:"pickup_points"`
+}
+
+// UpdateConnectionRequest represents a partial connection update
+type UpdateConnectionRequest struct {
+	Name         *string   `json:"name"`
+	APIKey       *string   `json:"api_key"`
+	APISecret    *string   `json:"api_secret"`
+	MerchantID   *string   `json:"merchant_id"`
+	PickupPoints *[]string `json:"pickup_points"`
+	IsActive     *bool     `json:"is_active"`
+}
+
+// ConnectionResponse represents a connection without credentials
+type ConnectionResponse struct {
+	ID                string   `json:"id"`
+	Name              string   `json:"name"`
+	Marketplace       string   `json:"marketplace"`
+	MerchantID        string   `json:"merchant_id,omitempty"`
+	PickupPoints      []string `json:"pickup_points,omitempty"`
+	IsActive          bool     `json:"is_active"`
+	DeactivatedReason string   `json:"deactivated_reason,omitempty"`
+	LastSyncAt        string   `json:"last_sync_at,omitempty"`
+	CreatedAt         string   `json:"created_at"`
+}
+
+func newConnectionResponse(conn *domain.MarketplaceConnection) ConnectionResponse {
+	response := ConnectionResponse{
+		ID:                conn.ID,
+		Name:              conn.Name,
+		Marketplace:       conn.Marketplace,
+		MerchantID:        conn.MerchantID,
+		PickupPoints:      conn.PickupPoints,
+		IsActive:          conn.IsActive,
+		DeactivatedReason: conn.DeactivatedReason,
+		CreatedAt:         conn.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if !conn.LastSyncAt.IsZero() {
+		response.LastSyncAt = conn.LastSyncAt.Format("2006-01-02 15:04:05")
+	}
+	return response
+}
+
+// requiresMerchantID reports whether the marketplace needs a merchant/client ID besides the key
+func requiresMerchantID(marketplaceType string) bool {
+	return marketplaceType == domain.MarketplaceKaspi || marketplaceType == domain.MarketplaceOzon
+}
+
+func isSupportedMarketplace(marketplaceType string) bool {
+	switch marketplaceType {
+	case domain.MarketplaceKaspi, domain.MarketplaceWildberries, domain.MarketplaceOzon:
+		return true
+	}
+	return false
+}
+
+// ownedConnection loads the connection from the :id param and verifies ownership,
+// writing the error response itself when it returns nil
+func ownedConnection(c *gin.Context, connectionRepo domain.MarketplaceConnectionRepository) *domain.MarketplaceConnection {
+	telegramID := middleware.GetUserID(c)
+
+	conn, err := connectionRepo.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil || conn == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Connection not found"})
+		return nil
+	}
+
+	// Verify ownership
+	if conn.UserID != telegramID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return nil
+	}
+
+	return conn
+}
+
+// ListConnections returns all user's marketplace connections
+// GET /api/v1/connections
+func (h *ConnectionHandler) ListConnections(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+
+	conns, err := h.connectionRepo.GetByUserID(c.Request.Context(), telegramID)
+	if err != nil {
+		logger.Log.Error("Failed to get connections", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get connections"})
+		return
+	}
+
+	response := make([]ConnectionResponse, 0, len(conns))
+	for i := range conns {
+		response = append(response, newConnectionResponse(&conns[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"connections": response,
+		"count":       len(response),
+	})
+}
+
+// GetConnection returns a single connection
+// GET /api/v1/connections/:id
+func (h *ConnectionHandler) GetConnection(c *gin.Context) {
+	conn := ownedConnection(c, h.connectionRepo)
+	if conn == nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, newConnectionResponse(conn))
+}
+
+// CreateConnection connects a new marketplace account
+// POST /api/v1/connections
+func (h *ConnectionHandler) CreateConnection(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+
+	var req CreateConnectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	if !isSupportedMarketplace(req.Marketplace) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported marketplace. Use kaspi, wildberries or ozon"})
+		return
+	}
+
+	if requiresMerchantID(req.Marketplace) && req.MerchantID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "merchant_id is required for " + req.Marketplace})
+		return
+	}
+
+	conn := &domain.MarketplaceConnection{
+		UserID:       telegramID,
+		Name:         req.Name,
+		Marketplace:  req.Marketplace,
+		MerchantID:   req.MerchantID,
+		PickupPoints: req.PickupPoints,
+		IsActive:     true,
+	}
+
+	if err := h.setCredentials(conn, &req.APIKey, &req.APISecret); err != nil {
+		logger.Log.Error("Failed to encrypt credentials", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encrypt credentials"})
+		return
+	}
+
+	if err := h.connectionRepo.Create(c.Request.Context(), conn); err != nil {
+		logger.Log.Error("Failed to create connection", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create connection"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Connection created successfully",
+		"connection": newConnectionResponse(conn),
+	})
+}
+
+// UpdateConnection renames a connection, rotates its credentials or toggles it
+// PATCH /api/v1/connections/:id
+func (h *ConnectionHandler) UpdateConnection(c *gin.Context) {
+	conn := ownedConnection(c, h.connectionRepo)
+	if conn == nil {
+		return
+	}
+
+	var req UpdateConnectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	if req.Name != nil {
+		conn.Name = *req.Name
+	}
+	if req.MerchantID != nil {
+		if requiresMerchantID(conn.Marketplace) && *req.MerchantID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "merchant_id is required for " + conn.Marketplace})
+			return
+		}
+		conn.MerchantID = *req.MerchantID
+	}
+	if req.PickupPoints != nil {
+		conn.PickupPoints = *req.PickupPoints
+	}
+
+	credentialsChanged := req.APIKey != nil || req.APISecret != nil || req.MerchantID != nil
+	if err := h.setCredentials(conn, req.APIKey, req.APISecret); err != nil {
+		logger.Log.Error("Failed to encrypt credentials", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encrypt credentials"})
+		return
+	}
+
+	if req.IsActive != nil {
+		conn.IsActive = *req.IsActive
+	}
+
+	// New credentials or a manual re-activation give the connection a clean slate
+	if credentialsChanged || (req.IsActive != nil && *req.IsActive) {
+		conn.AuthFailures = 0
+		conn.DeactivatedReason = ""
+		conn.DeactivatedAt = time.Time{}
+	}
+
+	if err := h.connectionRepo.Update(c.Request.Context(), conn); err != nil {
+		logger.Log.Error("Failed to update connection", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update connection"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Connection updated successfully",
+		"connection": newConnectionResponse(conn),
+	})
+}
+
+// DeleteConnection removes a connection together with the data synced through it and its price feed
+// DELETE /api/v1/connections/:id
+func (h *ConnectionHandler) DeleteConnection(c *gin.Context) {
+	conn := ownedConnection(c, h.connectionRepo)
+	if conn == nil {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if err := h.connectionRepo.Delete(ctx, conn.ID); err != nil {
+		logger.Log.Error("Failed to delete connection", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete connection"})
+		return
+	}
+	h.clients.Forget(conn.ID)
+
+	// The connection is gone either way; leftovers are only logged
+	if err := h.salesHistoryRepo.DeleteByConnectionID(ctx, conn.ID); err != nil {
+		logger.Log.Error("Failed to delete connection sales history", zap.String("connection_id", conn.ID), zap.Error(err))
+	}
+	if err := h.orderRepo.DeleteByConnectionID(ctx, conn.ID); err != nil {
+		logger.Log.Error("Failed to delete connection orders", zap.String("connection_id", conn.ID), zap.Error(err))
+	}
+	if err := h.reviewRepo.DeleteByConnectionID(ctx, conn.ID); err != nil {
+		logger.Log.Error("Failed to delete connection reviews", zap.String("connection_id", conn.ID), zap.Error(err))
+	}
+	if err := h.productRepo.DeleteByConnectionID(ctx, conn.ID); err != nil {
+		logger.Log.Error("Failed to delete connection products", zap.String("connection_id", conn.ID), zap.Error(err))
+	}
+	if err := h.priceFeedRepo.DeleteByConnectionID(ctx, conn.ID); err != nil {
+		logger.Log.Error("Failed to delete connection price feed", zap.String("connection_id", conn.ID), zap.Error(err))
+	}
+	if err := h.priceChangeRepo.DeleteByConnectionID(ctx, conn.ID); err != nil {
+		logger.Log.Error("Failed to delete connection price history", zap.String("connection_id", conn.ID), zap.Error(err))
+	}
+	if err := h.offerRepo.DeleteByConnectionID(ctx, conn.ID); err != nil {
+		logger.Log.Error("Failed to delete connection competitor history", zap.String("connection_id", conn.ID), zap.Error(err))
+	}
+	if err := h.proposalRepo.DeleteByConnectionID(ctx, conn.ID); err != nil {
+		logger.Log.Error("Failed to delete connection price proposals", zap.String("connection_id", conn.ID), zap.Error(err))
+	}
+	if err := h.guardAlertRepo.DeleteByConnectionID(ctx, conn.ID); err != nil {
+		logger.Log.Error("Failed to delete connection price guard alerts", zap.String("connection_id", conn.ID), zap.Error(err))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Connection deleted successfully"})
+}
+
+// SyncConnection triggers manual synchronization of one connection
+// POST /api/v1/connections/:id/sync
+func (h *ConnectionHandler) SyncConnection(c *gin.Context) {
+	conn := ownedConnection(c, h.connectionRepo)
+	if conn == nil {
+		return
+	}
+
+	logger.Log.Info("Manual sync triggered",
+		zap.String("user_id", conn.UserID),
+		zap.String("connection_id", conn.ID),
+	)
+
+	if err := h.syncService.SyncConnection(c.Request.Context(), conn); err != nil {
+		logger.Log.Error("Manual sync failed",
+			zap.String("connection_id", conn.ID),
+			zap.Error(err),
+		)
+		if marketplace.IsAuthError(err) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "The marketplace rejected the credentials. Please update this connection.",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Synchronization failed",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	logger.Log.Info("Manual sync completed successfully",
+		zap.String("connection_id", conn.ID),
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Synchronization completed successfully",
+		"connection": newConnectionResponse(conn),
+	})
+}
+
+// setCredentials encrypts the given key and secret into conn; nil leaves a field unchanged
+func (h *ConnectionHandler) setCredentials(conn *domain.MarketplaceConnection, apiKey, apiSecret *string) error {
+	if apiKey != nil {
+		encrypted, err := h.encryptor.Encrypt(*apiKey)
+		if err != nil {
+			return err
+		}
+		conn.APIKeyEncrypted = encrypted
+	}
+
+	if apiSecret != nil {
+		conn.APISecretEncrypted = ""
+		if *apiSecret != "" {
+			encrypted, err := h.encryptor.Encrypt(*apiSecret)
+			if err != nil {
+				return err
+			}
+			conn.APISecretEncrypted = encrypted
+		}
+	}
+
+	return nil
+}
@@ -41,7 +41,7 @@ func (h *DashboardHandler) GetStats(c *gin.Context) {
 	stats := DashboardStats{}
 
 	// Get products
-	products, err := h.productRepo.GetByUserID(telegramID)
+	products, err := h.productRepo.GetByUserID(c.Request.Context(), telegramID)
 	if err != nil {
 		logger.Log.Error("Failed to get products", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get stats"})
@@ -62,7 +62,7 @@ func (h *DashboardHandler) GetStats(c *gin.Context) {
 	}
 
 	// Get reviews
-	reviews, err := h.reviewRepo.GetByUserID(telegramID, 100)
+	reviews, err := h.reviewRepo.GetByUserID(c.Request.Context(), telegramID, 100)
 	if err != nil {
 		logger.Log.Error("Failed to get reviews", zap.Error(err))
 	} else {
@@ -91,7 +91,7 @@ func (h *DashboardHandler) GetOverview(c *gin.Context) {
 	telegramID := middleware.GetUserID(c)
 
 	// Get products
-	products, err := h.productRepo.GetByUserID(telegramID)
+	products, err := h.productRepo.GetByUserID(c.Request.Context(), telegramID)
 	if err != nil {
 		logger.Log.Error("Failed to get products", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get overview"})
@@ -99,28 +99,28 @@ func (h *DashboardHandler) GetOverview(c *gin.Context) {
 	}
 
 	// Get low stock products
-	lowStockProducts, err := h.productRepo.GetLowStockProducts(telegramID, 7)
+	lowStockProducts, err := h.productRepo.GetLowStockProducts(c.Request.Context(), telegramID, 7)
 	if err != nil {
 		logger.Log.Error("Failed to get low stock products", zap.Error(err))
 		lowStockProducts = []domain.Product{}
 	}
 
 	// Get dumping products
-	dumpingProducts, err := h.productRepo.GetProductsForDumping(telegramID)
+	dumpingProducts, err := h.productRepo.GetProductsForDumping(c.Request.Context(), telegramID)
 	if err != nil {
 		logger.Log.Error("Failed to get dumping products", zap.Error(err))
 		dumpingProducts = []domain.Product{}
 	}
 
 	// Get recent reviews
-	reviews, err := h.reviewRepo.GetByUserID(telegramID, 10)
+	reviews, err := h.reviewRepo.GetByUserID(c.Request.Context(), telegramID, 10)
 	if err != nil {
 		logger.Log.Error("Failed to get reviews", zap.Error(err))
 		reviews = []domain.Review{}
 	}
 
 	// Get pending reviews
-	pendingReviews, err := h.reviewRepo.GetPendingReviews(telegramID)
+	pendingReviews, err := h.reviewRepo.GetPendingReviews(c.Request.Context(), telegramID)
 	if err != nil {
 		logger.Log.Error("Failed to get pending reviews", zap.Error(err))
 		pendingReviews = []domain.Review{}
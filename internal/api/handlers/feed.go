@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/service"
+	"github.com/yourusername/seller-assistant/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// kaspiFeedPath is where Kaspi downloads a feed; the token is appended
+const kaspiFeedPath = "/api/v1/feeds/kaspi/"
+
+type FeedHandler struct {
+	connectionRepo domain.MarketplaceConnectionRepository
+	priceFeedRepo  domain.PriceFeedRepository
+	feedService    *service.PriceFeedService
+	publicBaseURL  string
+}
+
+func NewFeedHandler(
+	connectionRepo domain.MarketplaceConnectionRepository,
+	priceFeedRepo domain.PriceFeedRepository,
+	feedService *service.PriceFeedService,
+	publicBaseURL string,
+) *FeedHandler {
+	return &FeedHandler{
+		connectionRepo: connectionRepo,
+		priceFeedRepo:  priceFeedRepo,
+		feedService:    feedService,
+		publicBaseURL:  strings.TrimRight(publicBaseURL, "/"),
+	}
+}
+
+// FeedResponse describes a feed without its content
+type FeedResponse struct {
+	URL          string `json:"url"`
+	OfferCount   int    `json:"offer_count"`
+	SkippedCount int    `json:"skipped_count"`
+	GeneratedAt  string `json:"generated_at,omitempty"`
+}
+
+func (h *FeedHandler) newFeedResponse(feed *domain.PriceFeed) FeedResponse {
+	response := FeedResponse{
+		URL:          h.publicBaseURL + kaspiFeedPath + feed.Token,
+		OfferCount:   feed.OfferCount,
+		SkippedCount: feed.SkippedCount,
+	}
+	if !feed.GeneratedAt.IsZero() {
+		response.GeneratedAt = feed.GeneratedAt.Format("2006-01-02 15:04:05")
+	}
+	return response
+}
+
+// ServeKaspiFeed returns the XML price list; the secret token is the only credential
+// GET /api/v1/feeds/kaspi/:token
+func (h *FeedHandler) ServeKaspiFeed(c *gin.Context) {
+	feed, err := h.priceFeedRepo.GetByToken(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		logger.Log.Error("Failed to get price feed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get price feed"})
+		return
+	}
+
+	// Unknown tokens and not yet generated feeds look the same to the caller
+	if feed == nil || feed.Content == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+		return
+	}
+
+	c.Header("Last-Modified", feed.GeneratedAt.UTC().Format(http.TimeFormat))
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", []byte(feed.Content))
+}
+
+// GetFeed returns the feed URL and stats of a Kaspi connection
+// GET /api/v1/connections/:id/feed
+func (h *FeedHandler) GetFeed(c *gin.Context) {
+	conn := ownedConnection(c, h.connectionRepo)
+	if conn == nil {
+		return
+	}
+
+	feed, err := h.priceFeedRepo.GetByConnectionID(c.Request.Context(), conn.ID)
+	if err != nil {
+		logger.Log.Error("Failed to get price feed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get price feed"})
+		return
+	}
+
+	// The first request creates the feed so the user gets a URL right away
+	if feed == nil {
+		h.regenerate(c, conn)
+		return
+	}
+
+	c.JSON(http.StatusOK, h.newFeedResponse(feed))
+}
+
+// RegenerateFeed rebuilds the feed from the current products
+// POST /api/v1/connections/:id/feed/regenerate
+func (h *FeedHandler) RegenerateFeed(c *gin.Context) {
+	conn := ownedConnection(c, h.connectionRepo)
+	if conn == nil {
+		return
+	}
+
+	h.regenerate(c, conn)
+}
+
+// RotateFeedToken issues a new secret token; the old feed URL stops working
+// POST /api/v1/connections/:id/feed/rotate-token
+func (h *FeedHandler) RotateFeedToken(c *gin.Context) {
+	conn := ownedConnection(c, h.connectionRepo)
+	if conn == nil {
+		return
+	}
+
+	feed, err := h.feedService.RotateToken(c.Request.Context(), conn)
+	if errors.Is(err, service.ErrFeedNotSupported) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to rotate feed token", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rotate feed token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.newFeedResponse(feed))
+}
+
+func (h *FeedHandler) regenerate(c *gin.Context, conn *domain.MarketplaceConnection) {
+	feed, err := h.feedService.Regenerate(c.Request.Context(), conn)
+	if errors.Is(err, service.ErrFeedNotSupported) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to regenerate price feed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to regenerate price feed", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.newFeedResponse(feed))
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/seller-assistant/internal/api/middleware"
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/internal/service"
+	"github.com/yourusername/seller-assistant/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// maxBulkOrders caps how many orders one bulk request may change
+const maxBulkOrders = 100
+
+type OrderHandler struct {
+	orderRepo    domain.OrderRepository
+	orderService *service.OrderService
+}
+
+func NewOrderHandler(orderRepo domain.OrderRepository, orderService *service.OrderService) *OrderHandler {
+	return &OrderHandler{
+		orderRepo:    orderRepo,
+		orderService: orderService,
+	}
+}
+
+// GetOrders returns the user's orders, newest first
+// GET /api/v1/orders?connection_id=<id>&status=<status>&days=<n>&limit=<n>
+func (h *OrderHandler) GetOrders(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+
+	filter := domain.OrderFilter{
+		ConnectionID: c.Query("connection_id"),
+		Status:       c.Query("status"),
+		Limit:        100,
+	}
+
+	if d := c.Query("days"); d != "" {
+		days, err := strconv.Atoi(d)
+		if err != nil || days <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a positive number"})
+			return
+		}
+		filter.Since = time.Now().AddDate(0, 0, -days)
+	}
+
+	if l := c.Query("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	orders, err := h.orderRepo.GetByUserID(c.Request.Context(), telegramID, filter)
+	if err != nil {
+		logger.Log.Error("Failed to get orders", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get orders"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"orders": orders,
+		"count":  len(orders),
+	})
+}
+
+// GetOrder returns single order by ID
+// GET /api/v1/orders/:id
+func (h *OrderHandler) GetOrder(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	orderID := c.Param("id")
+
+	order, err := h.orderRepo.GetByID(c.Request.Context(), orderID)
+	if err != nil || order == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+		return
+	}
+
+	// Verify ownership
+	if order.UserID != telegramID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// CancelOrderRequest represents request to cancel an order
+type CancelOrderRequest struct {
+	Reason string `json:"reason" binding:"required"` // out_of_stock, customer_request, customer_unreachable, wrong_price or other
+}
+
+// BulkOrderActionRequest represents one action applied to several orders
+type BulkOrderActionRequest struct {
+	Action   string   `json:"action" binding:"required"` // accept, ready or cancel
+	OrderIDs []string `json:"order_ids" binding:"required"`
+	Reason   string   `json:"reason"` // Required for cancel
+}
+
+// AcceptOrder confirms a new order on the marketplace
+// POST /api/v1/orders/:id/accept
+func (h *OrderHandler) AcceptOrder(c *gin.Context) {
+	h.applyAction(c, domain.OrderActionAccept, "")
+}
+
+// MarkOrderReady reports an accepted order as ready for pickup or shipped
+// POST /api/v1/orders/:id/ready
+func (h *OrderHandler) MarkOrderReady(c *gin.Context) {
+	h.applyAction(c, domain.OrderActionReady, "")
+}
+
+// CancelOrder cancels an order with a reason code
+// POST /api/v1/orders/:id/cancel
+func (h *OrderHandler) CancelOrder(c *gin.Context) {
+	var req CancelOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	h.applyAction(c, domain.OrderActionCancel, req.Reason)
+}
+
+// BulkOrderAction applies accept, ready or cancel to several orders and reports the result per order
+// POST /api/v1/orders/bulk
+func (h *OrderHandler) BulkOrderAction(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+
+	var req BulkOrderActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	switch req.Action {
+	case domain.OrderActionAccept, domain.OrderActionReady, domain.OrderActionCancel:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "action must be accept, ready or cancel"})
+		return
+	}
+	if len(req.OrderIDs) == 0 || len(req.OrderIDs) > maxBulkOrders {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order_ids must contain between 1 and 100 orders"})
+		return
+	}
+	if req.Action == domain.OrderActionCancel && !marketplace.IsCancelReason(req.Reason) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": service.ErrInvalidCancelReason.Error()})
+		return
+	}
+
+	results := h.orderService.ApplyBulk(c.Request.Context(), telegramID, req.OrderIDs, req.Action, req.Reason)
+
+	succeeded := 0
+	for _, r := range results {
+		if r.Error == "" {
+			succeeded++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results":   results,
+		"succeeded": succeeded,
+		"failed":    len(results) - succeeded,
+	})
+}
+
+func (h *OrderHandler) applyAction(c *gin.Context, action, reason string) {
+	telegramID := middleware.GetUserID(c)
+	orderID := c.Param("id")
+
+	order, err := h.orderService.Apply(c.Request.Context(), telegramID, orderID, action, reason)
+	if err != nil {
+		status, message := orderActionError(err)
+		if status == http.StatusInternalServerError {
+			logger.Log.Error("Order action failed",
+				zap.String("order_id", orderID),
+				zap.String("action", action),
+				zap.Error(err),
+			)
+		}
+		c.JSON(status, gin.H{"error": message, "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// orderActionError maps an order action failure to an HTTP status and message
+func orderActionError(err error) (int, string) {
+	switch {
+	case errors.Is(err, service.ErrOrderNotFound):
+		return http.StatusNotFound, "Order not found"
+	case errors.Is(err, service.ErrInvalidCancelReason):
+		return http.StatusBadRequest, "Invalid cancellation reason"
+	case errors.Is(err, domain.ErrOrderTransition), errors.Is(err, marketplace.ErrConflict):
+		return http.StatusConflict, "The order's status does not allow this action"
+	case errors.Is(err, marketplace.ErrNotSupported):
+		return http.StatusUnprocessableEntity, "The marketplace does not support this action"
+	case marketplace.IsAuthError(err):
+		return http.StatusUnprocessableEntity, "The marketplace rejected the credentials. Please update this connection."
+	}
+	return http.StatusInternalServerError, "Failed to update order"
+}
@@ -0,0 +1,817 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/seller-assistant/internal/api/middleware"
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/internal/service"
+	"github.com/yourusername/seller-assistant/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// maxBulkPrices caps how many prices one bulk request may change
+const maxBulkPrices = 100
+
+// maxPriceHistoryLimit caps how many price changes one price history request returns
+const maxPriceHistoryLimit = 500
+
+type PricingHandler struct {
+	pricingService *service.PricingService
+	historyService *service.PriceHistoryService
+	offerHistory   *service.CompetitorHistoryService
+	reportService  *service.DumpingReportService
+	backtests      *service.BacktestService
+	guards         *service.PriceGuardService
+}
+
+func NewPricingHandler(
+	pricingService *service.PricingService,
+	historyService *service.PriceHistoryService,
+	offerHistory *service.CompetitorHistoryService,
+	reportService *service.DumpingReportService,
+	backtests *service.BacktestService,
+	guards *service.PriceGuardService,
+) *PricingHandler {
+	return &PricingHandler{
+		pricingService: pricingService,
+		historyService: historyService,
+		offerHistory:   offerHistory,
+		reportService:  reportService,
+		backtests:      backtests,
+		guards:         guards,
+	}
+}
+
+// UpdatePriceRequest represents a manual price change of one product
+type UpdatePriceRequest struct {
+	Price float64 `json:"price" binding:"required"`
+}
+
+// BulkPriceRequest represents manual price changes of several products
+type BulkPriceRequest struct {
+	Prices []BulkPriceItem `json:"prices" binding:"required"`
+}
+
+// BulkPriceItem is the new price of one product in a bulk request
+type BulkPriceItem struct {
+	ProductID string  `json:"product_id" binding:"required"`
+	Price     float64 `json:"price" binding:"required"`
+}
+
+// UpdatePrice sets a product's price on the marketplace
+// PUT /api/v1/products/:id/price
+func (h *PricingHandler) UpdatePrice(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	productID := c.Param("id")
+
+	var req UpdatePriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	product, err := h.pricingService.SetPrice(c.Request.Context(), telegramID, productID, req.Price)
+	if err != nil {
+		h.respondError(c, err, "Failed to update price", productID)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Price updated successfully",
+		"product": product,
+	})
+}
+
+// BulkUpdatePrices sets the prices of several products and reports the result per product
+// POST /api/v1/products/prices/bulk
+func (h *PricingHandler) BulkUpdatePrices(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+
+	var req BulkPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+	if len(req.Prices) == 0 || len(req.Prices) > maxBulkPrices {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "prices must contain between 1 and 100 products"})
+		return
+	}
+
+	updates := make([]service.PriceUpdate, len(req.Prices))
+	for i, p := range req.Prices {
+		updates[i] = service.PriceUpdate{ProductID: p.ProductID, Price: p.Price}
+	}
+
+	results := h.pricingService.SetPrices(c.Request.Context(), telegramID, updates)
+
+	succeeded := 0
+	for _, r := range results {
+		if r.Error == "" {
+			succeeded++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results":   results,
+		"succeeded": succeeded,
+		"failed":    len(results) - succeeded,
+	})
+}
+
+// GetPriceHistory returns a product's price changes, newest first, at most maxPriceHistoryLimit
+// GET /api/v1/products/:id/price-history?days=<n>&limit=<n>
+func (h *PricingHandler) GetPriceHistory(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	productID := c.Param("id")
+
+	days := 30
+	if d := c.Query("days"); d != "" {
+		n, err := strconv.Atoi(d)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a positive number"})
+			return
+		}
+		days = n
+	}
+
+	limit := 100
+	if l := c.Query("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+			return
+		}
+		limit = min(n, maxPriceHistoryLimit)
+	}
+
+	changes, err := h.historyService.GetHistory(c.Request.Context(), telegramID, productID, days, limit)
+	if err != nil {
+		h.respondError(c, err, "Failed to get price history", productID)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"changes": changes,
+		"count":   len(changes),
+	})
+}
+
+// GetCompetitorHistory returns the cheapest and median competitor price and our price of a
+// product at each observation, oldest first
+// GET /api/v1/products/:id/competitor-history?days=<n>&city=<city>
+func (h *PricingHandler) GetCompetitorHistory(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	productID := c.Param("id")
+
+	days, ok := competitorHistoryDays(c)
+	if !ok {
+		return
+	}
+
+	points, err := h.offerHistory.GetPriceSeries(c.Request.Context(), telegramID, productID, c.Query("city"), days)
+	if err != nil {
+		h.respondError(c, err, "Failed to get competitor history", productID)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"points": points,
+		"count":  len(points),
+	})
+}
+
+// GetCompetitorSellers returns how each competitor priced a product: its price changes and
+// when it appeared on or disappeared from the listing
+// GET /api/v1/products/:id/competitor-history/sellers?days=<n>&city=<city>
+func (h *PricingHandler) GetCompetitorSellers(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	productID := c.Param("id")
+
+	days, ok := competitorHistoryDays(c)
+	if !ok {
+		return
+	}
+
+	sellers, err := h.offerHistory.GetSellerHistory(c.Request.Context(), telegramID, productID, c.Query("city"), days)
+	if err != nil {
+		h.respondError(c, err, "Failed to get competitor sellers", productID)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sellers": sellers,
+		"count":   len(sellers),
+	})
+}
+
+// competitorHistoryDays reads the days query parameter (7 by default), responding with
+// 400 and returning false when it is invalid
+func competitorHistoryDays(c *gin.Context) (int, bool) {
+	d := c.Query("days")
+	if d == "" {
+		return 7, true
+	}
+
+	n, err := strconv.Atoi(d)
+	if err != nil || n <= 0 || n > service.MaxCompetitorHistoryDays {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("days must be between 1 and %d", service.MaxCompetitorHistoryDays),
+		})
+		return 0, false
+	}
+
+	return n, true
+}
+
+// UpdateCityPriceRequest represents a change to a product's price settings in one city
+type UpdateCityPriceRequest struct {
+	MinPrice *float64 `json:"min_price"` // Dumping floor in the city; 0 uses the product's min price
+	MaxPrice *float64 `json:"max_price"` // Ceiling in the city; 0 uses the product's max price
+	Price    *float64 `json:"price"`     // Pushed to the marketplace right away
+}
+
+// UpdatePriceLimitsRequest represents a change to the range dumping keeps a product's price in
+type UpdatePriceLimitsRequest struct {
+	MinPrice *float64 `json:"min_price"` // 0 for no floor
+	MaxPrice *float64 `json:"max_price"` // 0 for no ceiling
+}
+
+// UpdatePriceLimits sets the min and max price of a product
+// PUT /api/v1/products/:id/price-limits
+func (h *PricingHandler) UpdatePriceLimits(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	productID := c.Param("id")
+
+	var req UpdatePriceLimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+	if req.MinPrice == nil && req.MaxPrice == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_price or max_price is required"})
+		return
+	}
+
+	product, err := h.pricingService.SetPriceLimits(c.Request.Context(), telegramID, productID, service.PriceLimits{
+		MinPrice: req.MinPrice,
+		MaxPrice: req.MaxPrice,
+	})
+	if err != nil {
+		h.respondError(c, err, "Failed to update price limits", productID)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Price limits updated successfully",
+		"product": product,
+	})
+}
+
+// UpdateCityPrice sets a product's min and max price and optionally its price in one city
+// PUT /api/v1/products/:id/cities/:city
+func (h *PricingHandler) UpdateCityPrice(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	productID := c.Param("id")
+
+	var req UpdateCityPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+	if req.MinPrice == nil && req.MaxPrice == nil && req.Price == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_price, max_price or price is required"})
+		return
+	}
+
+	product, err := h.pricingService.SetCityPrice(c.Request.Context(), telegramID, productID, c.Param("city"), service.CityPriceUpdate{
+		PriceLimits: service.PriceLimits{
+			MinPrice: req.MinPrice,
+			MaxPrice: req.MaxPrice,
+		},
+		Price: req.Price,
+	})
+	if err != nil {
+		h.respondError(c, err, "Failed to update city price", productID)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "City price updated successfully",
+		"product": product,
+	})
+}
+
+// UpdateProductTagsRequest replaces a product's tags
+type UpdateProductTagsRequest struct {
+	Tags []string `json:"tags" binding:"required"`
+}
+
+// UpdateProductCosts sets a product's cost price, commission, fee and minimum margin,
+// from which dumping computes its floor
+// PUT /api/v1/products/:id/costs
+func (h *PricingHandler) UpdateProductCosts(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	productID := c.Param("id")
+
+	var req domain.ProductCosts
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	margin, err := h.pricingService.SetProductCosts(c.Request.Context(), telegramID, productID, &req)
+	if err != nil {
+		h.respondError(c, err, "Failed to update product costs", productID)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Costs updated successfully",
+		"margin":  margin,
+	})
+}
+
+// DeleteProductCosts removes a product's costs, so only its min price limits dumping
+// DELETE /api/v1/products/:id/costs
+func (h *PricingHandler) DeleteProductCosts(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	productID := c.Param("id")
+
+	margin, err := h.pricingService.SetProductCosts(c.Request.Context(), telegramID, productID, nil)
+	if err != nil {
+		h.respondError(c, err, "Failed to delete product costs", productID)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Costs removed successfully",
+		"margin":  margin,
+	})
+}
+
+// GetProductMargin returns a product's margin at its current prices and at a proposed price
+// GET /api/v1/products/:id/margin?price=<proposed>
+func (h *PricingHandler) GetProductMargin(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	productID := c.Param("id")
+
+	var proposed *float64
+	if p := c.Query("price"); p != "" {
+		price, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "price must be a number"})
+			return
+		}
+		proposed = &price
+	}
+
+	margin, err := h.pricingService.GetMargin(c.Request.Context(), telegramID, productID, proposed)
+	if err != nil {
+		h.respondError(c, err, "Failed to get product margin", productID)
+		return
+	}
+
+	c.JSON(http.StatusOK, margin)
+}
+
+// UpdateDryRunRequest turns dry-run mode on or off
+type UpdateDryRunRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// UpdateProductDryRun turns dry-run mode of a product on or off
+// PUT /api/v1/products/:id/dry-run
+func (h *PricingHandler) UpdateProductDryRun(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	productID := c.Param("id")
+
+	var req UpdateDryRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	product, err := h.pricingService.SetProductDryRun(c.Request.Context(), telegramID, productID, *req.Enabled)
+	if err != nil {
+		h.respondError(c, err, "Failed to update product dry-run mode", productID)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Dry-run mode updated successfully",
+		"product": product,
+	})
+}
+
+// GetDryRunReport compares the prices dumping proposed in dry-run mode with the current ones
+// GET /api/v1/pricing/dry-run/report?connection_id=<id>&changes_only=true
+func (h *PricingHandler) GetDryRunReport(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+
+	changesOnly, _ := strconv.ParseBool(c.Query("changes_only"))
+
+	report, err := h.reportService.Report(c.Request.Context(), telegramID, c.Query("connection_id"), changesOnly)
+	if err != nil {
+		logger.Log.Error("Failed to get dry-run report", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get dry-run report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// RunBacktest replays a product's recorded competitor offers through two strategies and
+// compares the prices they would have set and what they would have earned
+// POST /api/v1/products/:id/backtest
+func (h *PricingHandler) RunBacktest(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	productID := c.Param("id")
+
+	var req service.BacktestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	report, err := h.backtests.RunForUser(c.Request.Context(), telegramID, productID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to run backtest", productID)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ResumeDumping lets dumping reprice a product that a price guard paused
+// DELETE /api/v1/products/:id/dumping-pause
+func (h *PricingHandler) ResumeDumping(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	productID := c.Param("id")
+
+	product, err := h.guards.Resume(c.Request.Context(), telegramID, productID)
+	if err != nil {
+		h.respondError(c, err, "Failed to resume price dumping", productID)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Price dumping resumed",
+		"product": product,
+	})
+}
+
+// GetPriceGuard returns the price guard of the user's products
+// GET /api/v1/pricing/price-guard
+func (h *PricingHandler) GetPriceGuard(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+
+	guard, err := h.guards.GetGuard(c.Request.Context(), telegramID)
+	if err != nil {
+		logger.Log.Error("Failed to get price guard", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get price guard"})
+		return
+	}
+
+	c.JSON(http.StatusOK, guard)
+}
+
+// UpdatePriceGuard sets the price guard of all the user's products
+// PUT /api/v1/pricing/price-guard
+func (h *PricingHandler) UpdatePriceGuard(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+
+	var req domain.PriceGuard
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	guard, err := h.guards.SetGuard(c.Request.Context(), telegramID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to update price guard", "")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Price guard updated successfully",
+		"guard":   guard,
+	})
+}
+
+// ResetPriceGuard returns the user's products to the default price guard
+// DELETE /api/v1/pricing/price-guard
+func (h *PricingHandler) ResetPriceGuard(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+
+	guard, err := h.guards.ResetGuard(c.Request.Context(), telegramID)
+	if err != nil {
+		logger.Log.Error("Failed to reset price guard", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset price guard"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Price guard reset to default",
+		"guard":   guard,
+	})
+}
+
+// GetPriceGuardAlerts returns the alerts of price guards pausing the user's products, newest first
+// GET /api/v1/pricing/alerts?days=<n>
+func (h *PricingHandler) GetPriceGuardAlerts(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+
+	days, ok := competitorHistoryDays(c)
+	if !ok {
+		return
+	}
+
+	alerts, err := h.guards.GetAlerts(c.Request.Context(), telegramID, days)
+	if err != nil {
+		logger.Log.Error("Failed to get price guard alerts", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get price guard alerts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"alerts": alerts,
+		"count":  len(alerts),
+	})
+}
+
+// GetProductStrategy returns the strategy a product is repriced with and whether it is
+// the product's own, its tag's or the default
+// GET /api/v1/products/:id/strategy
+func (h *PricingHandler) GetProductStrategy(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	productID := c.Param("id")
+
+	strategy, err := h.pricingService.GetProductStrategy(c.Request.Context(), telegramID, productID)
+	if err != nil {
+		h.respondError(c, err, "Failed to get product strategy", productID)
+		return
+	}
+
+	c.JSON(http.StatusOK, strategy)
+}
+
+// UpdateProductStrategy gives a product its own repricing strategy
+// PUT /api/v1/products/:id/strategy
+func (h *PricingHandler) UpdateProductStrategy(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	productID := c.Param("id")
+
+	var req domain.PricingStrategyConfig
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	product, err := h.pricingService.SetProductStrategy(c.Request.Context(), telegramID, productID, &req)
+	if err != nil {
+		h.respondError(c, err, "Failed to update product strategy", productID)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Pricing strategy updated successfully",
+		"product": product,
+	})
+}
+
+// ResetProductStrategy removes a product's own strategy, so it uses its tags' strategy or the default
+// DELETE /api/v1/products/:id/strategy
+func (h *PricingHandler) ResetProductStrategy(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	productID := c.Param("id")
+
+	product, err := h.pricingService.SetProductStrategy(c.Request.Context(), telegramID, productID, nil)
+	if err != nil {
+		h.respondError(c, err, "Failed to reset product strategy", productID)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Pricing strategy reset successfully",
+		"product": product,
+	})
+}
+
+// UpdateProductTags replaces a product's tags
+// PUT /api/v1/products/:id/tags
+func (h *PricingHandler) UpdateProductTags(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	productID := c.Param("id")
+
+	var req UpdateProductTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	product, err := h.pricingService.SetProductTags(c.Request.Context(), telegramID, productID, req.Tags)
+	if err != nil {
+		h.respondError(c, err, "Failed to update product tags", productID)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Tags updated successfully",
+		"product": product,
+	})
+}
+
+// GetTagStrategies returns the user's tag strategies and the default strategy
+// GET /api/v1/pricing/strategies
+func (h *PricingHandler) GetTagStrategies(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+
+	strategies, err := h.pricingService.ListTagStrategies(c.Request.Context(), telegramID)
+	if err != nil {
+		logger.Log.Error("Failed to get tag strategies", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get tag strategies"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"strategies": strategies,
+		"count":      len(strategies),
+		"default":    service.DefaultPricingStrategy,
+	})
+}
+
+// UpdateTagStrategy sets the repricing strategy of the products carrying a tag
+// PUT /api/v1/pricing/strategies/:tag
+func (h *PricingHandler) UpdateTagStrategy(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+
+	var req domain.PricingStrategyConfig
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	strategy, err := h.pricingService.SetTagStrategy(c.Request.Context(), telegramID, c.Param("tag"), req)
+	if err != nil {
+		h.respondError(c, err, "Failed to update tag strategy", "")
+		return
+	}
+
+	c.JSON(http.StatusOK, strategy)
+}
+
+// DeleteTagStrategy removes the strategy of a tag
+// DELETE /api/v1/pricing/strategies/:tag
+func (h *PricingHandler) DeleteTagStrategy(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+
+	if err := h.pricingService.DeleteTagStrategy(c.Request.Context(), telegramID, c.Param("tag")); err != nil {
+		h.respondError(c, err, "Failed to delete tag strategy", "")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag strategy deleted successfully"})
+}
+
+// GetCompetitorFilter returns the competitor filter of all the user's products
+// GET /api/v1/pricing/competitor-filter
+func (h *PricingHandler) GetCompetitorFilter(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+
+	filter, err := h.pricingService.GetCompetitorFilter(c.Request.Context(), telegramID)
+	if err != nil {
+		logger.Log.Error("Failed to get competitor filter", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get competitor filter"})
+		return
+	}
+
+	c.JSON(http.StatusOK, filter)
+}
+
+// UpdateCompetitorFilter sets the competitor filter of all the user's products
+// PUT /api/v1/pricing/competitor-filter
+func (h *PricingHandler) UpdateCompetitorFilter(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+
+	var req domain.CompetitorFilter
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	filter, err := h.pricingService.SetCompetitorFilter(c.Request.Context(), telegramID, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to update competitor filter", "")
+		return
+	}
+
+	c.JSON(http.StatusOK, filter)
+}
+
+// GetProductCompetitorFilter returns a product's competitor filter, the user's, and the two combined
+// GET /api/v1/products/:id/competitor-filter
+func (h *PricingHandler) GetProductCompetitorFilter(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	productID := c.Param("id")
+
+	filter, err := h.pricingService.GetProductCompetitorFilter(c.Request.Context(), telegramID, productID)
+	if err != nil {
+		h.respondError(c, err, "Failed to get product competitor filter", productID)
+		return
+	}
+
+	c.JSON(http.StatusOK, filter)
+}
+
+// UpdateProductCompetitorFilter gives a product its own competitor filter
+// PUT /api/v1/products/:id/competitor-filter
+func (h *PricingHandler) UpdateProductCompetitorFilter(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	productID := c.Param("id")
+
+	var req domain.CompetitorFilter
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	product, err := h.pricingService.SetProductCompetitorFilter(c.Request.Context(), telegramID, productID, &req)
+	if err != nil {
+		h.respondError(c, err, "Failed to update product competitor filter", productID)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Competitor filter updated successfully",
+		"product": product,
+	})
+}
+
+// ResetProductCompetitorFilter removes a product's own competitor filter, so only the user's applies
+// DELETE /api/v1/products/:id/competitor-filter
+func (h *PricingHandler) ResetProductCompetitorFilter(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	productID := c.Param("id")
+
+	product, err := h.pricingService.SetProductCompetitorFilter(c.Request.Context(), telegramID, productID, nil)
+	if err != nil {
+		h.respondError(c, err, "Failed to reset product competitor filter", productID)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Competitor filter reset successfully",
+		"product": product,
+	})
+}
+
+// respondError writes a pricing failure, logging the ones that are not the client's fault
+func (h *PricingHandler) respondError(c *gin.Context, err error, logMessage, productID string) {
+	status, message := pricingError(err)
+	if status == http.StatusInternalServerError {
+		logger.Log.Error(logMessage,
+			zap.String("product_id", productID),
+			zap.Error(err),
+		)
+	}
+	c.JSON(status, gin.H{"error": message, "details": err.Error()})
+}
+
+// pricingError maps a price change failure to an HTTP status and message
+func pricingError(err error) (int, string) {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		return http.StatusNotFound, "Product not found"
+	case errors.Is(err, service.ErrTagStrategyNotFound):
+		return http.StatusNotFound, "Tag has no pricing strategy"
+	case errors.Is(err, service.ErrInvalidPrice), errors.Is(err, service.ErrCityRequired):
+		return http.StatusBadRequest, "Invalid price update"
+	case errors.Is(err, service.ErrInvalidStrategy):
+		return http.StatusBadRequest, "Invalid pricing strategy"
+	case errors.Is(err, service.ErrInvalidTag):
+		return http.StatusBadRequest, "Invalid tags"
+	case errors.Is(err, service.ErrInvalidCompetitorFilter):
+		return http.StatusBadRequest, "Invalid competitor filter"
+	case errors.Is(err, service.ErrInvalidCosts):
+		return http.StatusBadRequest, "Invalid product costs"
+	case errors.Is(err, service.ErrInvalidPriceGuard):
+		return http.StatusBadRequest, "Invalid price guard"
+	case errors.Is(err, service.ErrInvalidBacktest):
+		return http.StatusBadRequest, "Invalid backtest"
+	case errors.Is(err, service.ErrNoOfferHistory):
+		return http.StatusUnprocessableEntity, "No competitor history recorded for this product yet"
+	case errors.Is(err, marketplace.ErrNotSupported):
+		return http.StatusUnprocessableEntity, "The marketplace does not support city prices"
+	case errors.Is(err, marketplace.ErrNotFound):
+		return http.StatusUnprocessableEntity, "The marketplace does not know this product"
+	case marketplace.IsAuthError(err):
+		return http.StatusUnprocessableEntity, "The marketplace rejected the credentials. Please update this connection."
+	}
+	return http.StatusInternalServerError, "Failed to update price"
+}
@@ -24,17 +24,28 @@ func NewProductHandler(productRepo domain.ProductRepository, priceDumpingService
 }
 
 // GetProducts returns all user's products
-// GET /api/v1/products
+// GET /api/v1/products?connection_id=<id>
 func (h *ProductHandler) GetProducts(c *gin.Context) {
 	telegramID := middleware.GetUserID(c)
 
-	products, err := h.productRepo.GetByUserID(telegramID)
+	products, err := h.productRepo.GetByUserID(c.Request.Context(), telegramID)
 	if err != nil {
 		logger.Log.Error("Failed to get products", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get products"})
 		return
 	}
 
+	// Optional filter by marketplace connection
+	if connectionID := c.Query("connection_id"); connectionID != "" {
+		filtered := make([]domain.Product, 0, len(products))
+		for _, p := range products {
+			if p.ConnectionID == connectionID {
+				filtered = append(filtered, p)
+			}
+		}
+		products = filtered
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"products": products,
 		"count":    len(products),
@@ -47,7 +58,7 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 	telegramID := middleware.GetUserID(c)
 	productID := c.Param("id")
 
-	product, err := h.productRepo.GetByID(productID)
+	product, err := h.productRepo.GetByID(c.Request.Context(), productID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
 		return
@@ -67,7 +78,7 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 func (h *ProductHandler) GetLowStockProducts(c *gin.Context) {
 	telegramID := middleware.GetUserID(c)
 
-	products, err := h.productRepo.GetLowStockProducts(telegramID, 7)
+	products, err := h.productRepo.GetLowStockProducts(c.Request.Context(), telegramID, 7)
 	if err != nil {
 		logger.Log.Error("Failed to get low stock products", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get low stock products"})
@@ -100,7 +111,7 @@ func (h *ProductHandler) EnableDumping(c *gin.Context) {
 	}
 
 	// Verify product exists and ownership
-	product, err := h.productRepo.GetByID(productID)
+	product, err := h.productRepo.GetByID(c.Request.Context(), productID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
 		return
@@ -112,14 +123,14 @@ func (h *ProductHandler) EnableDumping(c *gin.Context) {
 	}
 
 	// Enable dumping
-	if err := h.priceDumpingService.EnableProductDumping(productID, req.MinPrice); err != nil {
+	if err := h.priceDumpingService.EnableProductDumping(c.Request.Context(), productID, req.MinPrice); err != nil {
 		logger.Log.Error("Failed to enable dumping", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enable dumping"})
 		return
 	}
 
 	// Get updated product
-	product, _ = h.productRepo.GetByID(productID)
+	product, _ = h.productRepo.GetByID(c.Request.Context(), productID)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Price dumping enabled successfully",
@@ -134,7 +145,7 @@ func (h *ProductHandler) DisableDumping(c *gin.Context) {
 	productID := c.Param("id")
 
 	// Verify product exists and ownership
-	product, err := h.productRepo.GetByID(productID)
+	product, err := h.productRepo.GetByID(c.Request.Context(), productID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
 		return
@@ -146,14 +157,14 @@ func (h *ProductHandler) DisableDumping(c *gin.Context) {
 	}
 
 	// Disable dumping
-	if err := h.priceDumpingService.DisableProductDumping(productID); err != nil {
+	if err := h.priceDumpingService.DisableProductDumping(c.Request.Context(), productID); err != nil {
 		logger.Log.Error("Failed to disable dumping", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to disable dumping"})
 		return
 	}
 
 	// Get updated product
-	product, _ = h.productRepo.GetByID(productID)
+	product, _ = h.productRepo.GetByID(c.Request.Context(), productID)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Price dumping disabled successfully",
@@ -166,13 +177,21 @@ func (h *ProductHandler) DisableDumping(c *gin.Context) {
 func (h *ProductHandler) GetDumpingProducts(c *gin.Context) {
 	telegramID := middleware.GetUserID(c)
 
-	products, err := h.productRepo.GetProductsForDumping(telegramID)
+	all, err := h.productRepo.GetByUserID(c.Request.Context(), telegramID)
 	if err != nil {
 		logger.Log.Error("Failed to get dumping products", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get dumping products"})
 		return
 	}
 
+	// Dumping runs per connection; across all of a user's connections just filter the flag
+	products := make([]domain.Product, 0)
+	for _, p := range all {
+		if p.AutoDumpingEnabled {
+			products = append(products, p)
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"products": products,
 		"count":    len(products),
@@ -36,7 +36,7 @@ func (h *ReviewHandler) GetReviews(c *gin.Context) {
 		}
 	}
 
-	reviews, err := h.reviewRepo.GetByUserID(telegramID, limit)
+	reviews, err := h.reviewRepo.GetByUserID(c.Request.Context(), telegramID, limit)
 	if err != nil {
 		logger.Log.Error("Failed to get reviews", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get reviews"})
@@ -55,7 +55,7 @@ func (h *ReviewHandler) GetReview(c *gin.Context) {
 	telegramID := middleware.GetUserID(c)
 	reviewID := c.Param("id")
 
-	review, err := h.reviewRepo.GetByID(reviewID)
+	review, err := h.reviewRepo.GetByID(c.Request.Context(), reviewID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
 		return
@@ -75,7 +75,7 @@ func (h *ReviewHandler) GetReview(c *gin.Context) {
 func (h *ReviewHandler) GetPendingReviews(c *gin.Context) {
 	telegramID := middleware.GetUserID(c)
 
-	reviews, err := h.reviewRepo.GetPendingReviews(telegramID)
+	reviews, err := h.reviewRepo.GetPendingReviews(c.Request.Context(), telegramID)
 	if err != nil {
 		logger.Log.Error("Failed to get pending reviews", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get pending reviews"})
@@ -105,7 +105,7 @@ func (h *ReviewHandler) GenerateReply(c *gin.Context) {
 	}
 
 	// Get review
-	review, err := h.reviewRepo.GetByID(reviewID)
+	review, err := h.reviewRepo.GetByID(c.Request.Context(), reviewID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
 		return
@@ -123,7 +123,7 @@ func (h *ReviewHandler) GenerateReply(c *gin.Context) {
 	}
 
 	// Generate AI response
-	aiResponse, err := h.aiResponder.GenerateResponse(review)
+	aiResponse, err := h.aiResponder.GenerateResponse(c.Request.Context(), review)
 	if err != nil {
 		logger.Log.Error("Failed to generate AI response", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate AI response"})
@@ -132,14 +132,14 @@ func (h *ReviewHandler) GenerateReply(c *gin.Context) {
 
 	// Update review with AI response
 	review.AIResponse = aiResponse
-	if err := h.reviewRepo.Update(review); err != nil {
+	if err := h.reviewRepo.Update(c.Request.Context(), review); err != nil {
 		logger.Log.Error("Failed to save AI response", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save AI response"})
 		return
 	}
 
 	// Get updated review
-	review, _ = h.reviewRepo.GetByID(reviewID)
+	review, _ = h.reviewRepo.GetByID(c.Request.Context(), reviewID)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message":     "AI response generated successfully",
@@ -166,7 +166,7 @@ func (h *ReviewHandler) UpdateReply(c *gin.Context) {
 	}
 
 	// Get review
-	review, err := h.reviewRepo.GetByID(reviewID)
+	review, err := h.reviewRepo.GetByID(c.Request.Context(), reviewID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
 		return
@@ -180,14 +180,14 @@ func (h *ReviewHandler) UpdateReply(c *gin.Context) {
 
 	// Update AI response
 	review.AIResponse = req.AIResponse
-	if err := h.reviewRepo.Update(review); err != nil {
+	if err := h.reviewRepo.Update(c.Request.Context(), review); err != nil {
 		logger.Log.Error("Failed to update AI response", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update AI response"})
 		return
 	}
 
 	// Get updated review
-	review, _ = h.reviewRepo.GetByID(reviewID)
+	review, _ = h.reviewRepo.GetByID(c.Request.Context(), reviewID)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "AI response updated successfully",
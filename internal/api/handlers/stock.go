@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/seller-assistant/internal/api/middleware"
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/internal/service"
+	"github.com/yourusername/seller-assistant/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// maxStockCSVSize caps the size of an uploaded stock CSV
+const maxStockCSVSize = 5 << 20
+
+type StockHandler struct {
+	connectionRepo domain.MarketplaceConnectionRepository
+	stockService   *service.StockService
+}
+
+func NewStockHandler(connectionRepo domain.MarketplaceConnectionRepository, stockService *service.StockService) *StockHandler {
+	return &StockHandler{
+		connectionRepo: connectionRepo,
+		stockService:   stockService,
+	}
+}
+
+// UpdateStockRequest represents a stock change of one product at one warehouse
+type UpdateStockRequest struct {
+	WarehouseID string `json:"warehouse_id"` // Kaspi pickup point or Ozon FBS warehouse; Kaspi defaults to the first pickup point
+	Quantity    *int   `json:"quantity" binding:"required"`
+}
+
+// UpdateProductStock sets a product's stock at one warehouse on the marketplace
+// PUT /api/v1/products/:id/stock
+func (h *StockHandler) UpdateProductStock(c *gin.Context) {
+	telegramID := middleware.GetUserID(c)
+	productID := c.Param("id")
+
+	var req UpdateStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "details": err.Error()})
+		return
+	}
+
+	product, err := h.stockService.SetProductStock(c.Request.Context(), telegramID, productID, req.WarehouseID, *req.Quantity)
+	if err != nil {
+		status, message := stockError(err)
+		if status == http.StatusInternalServerError {
+			logger.Log.Error("Failed to update stock",
+				zap.String("product_id", productID),
+				zap.Error(err),
+			)
+		}
+		c.JSON(status, gin.H{"error": message, "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Stock updated successfully",
+		"product": product,
+	})
+}
+
+// UploadStockCSV applies a CSV of stock levels (sku, warehouse_id, quantity) to the
+// connection's products and returns the result of every row
+// POST /api/v1/connections/:id/stock/upload
+func (h *StockHandler) UploadStockCSV(c *gin.Context) {
+	conn := ownedConnection(c, h.connectionRepo)
+	if conn == nil {
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A CSV file is required in the \"file\" field"})
+		return
+	}
+	if header.Size > maxStockCSVSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "The CSV file must not exceed 5 MB"})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read the uploaded file"})
+		return
+	}
+	defer file.Close()
+
+	results, err := h.stockService.ImportCSV(c.Request.Context(), conn, file)
+	if err != nil {
+		status, message := stockError(err)
+		if status == http.StatusInternalServerError {
+			logger.Log.Error("Failed to apply stock CSV",
+				zap.String("connection_id", conn.ID),
+				zap.Error(err),
+			)
+		}
+		c.JSON(status, gin.H{"error": message, "details": err.Error()})
+		return
+	}
+
+	updated := 0
+	for _, r := range results {
+		if r.Updated {
+			updated++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": results,
+		"updated": updated,
+		"failed":  len(results) - updated,
+	})
+}
+
+// stockError maps a stock update failure to an HTTP status and message
+func stockError(err error) (int, string) {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		return http.StatusNotFound, "Product not found"
+	case errors.Is(err, service.ErrInvalidStock), errors.Is(err, service.ErrWarehouseRequired),
+		errors.Is(err, service.ErrInvalidStockCSV):
+		return http.StatusBadRequest, "Invalid stock update"
+	case errors.Is(err, marketplace.ErrNotSupported):
+		return http.StatusUnprocessableEntity, "The marketplace does not support stock updates"
+	case errors.Is(err, marketplace.ErrNotFound):
+		return http.StatusUnprocessableEntity, "The marketplace does not know this product or warehouse"
+	case marketplace.IsAuthError(err):
+		return http.StatusUnprocessableEntity, "The marketplace rejected the credentials. Please update this connection."
+	}
+	return http.StatusInternalServerError, "Failed to update stock"
+}
@@ -24,6 +24,7 @@ func NewUserHandler(userRepo domain.UserRepository) *UserHandler {
 type UpdateSettingsRequest struct {
 	AutoReplyEnabled   *bool   `json:"auto_reply_enabled"`
 	AutoDumpingEnabled *bool   `json:"auto_dumping_enabled"`
+	DumpingDryRun      *bool   `json:"dumping_dry_run"`
 	Language           *string `json:"language"`
 }
 
@@ -32,7 +33,7 @@ type UpdateSettingsRequest struct {
 func (h *UserHandler) GetProfile(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 
-	user, err := h.userRepo.GetByID(userID)
+	user, err := h.userRepo.GetByID(c.Request.Context(), userID)
 	if err != nil || user == nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
@@ -52,7 +53,7 @@ func (h *UserHandler) UpdateSettings(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userRepo.GetByID(userID)
+	user, err := h.userRepo.GetByID(c.Request.Context(), userID)
 	if err != nil || user == nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
@@ -60,7 +61,7 @@ func (h *UserHandler) UpdateSettings(c *gin.Context) {
 
 	// Update settings
 	if req.AutoReplyEnabled != nil {
-		if err := h.userRepo.ToggleAutoReply(userID, *req.AutoReplyEnabled); err != nil {
+		if err := h.userRepo.ToggleAutoReply(c.Request.Context(), userID, *req.AutoReplyEnabled); err != nil {
 			logger.Log.Error("Failed to toggle auto-reply", zap.Error(err))
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update auto-reply"})
 			return
@@ -69,7 +70,7 @@ func (h *UserHandler) UpdateSettings(c *gin.Context) {
 	}
 
 	if req.AutoDumpingEnabled != nil {
-		if err := h.userRepo.ToggleAutoDumping(userID, *req.AutoDumpingEnabled); err != nil {
+		if err := h.userRepo.ToggleAutoDumping(c.Request.Context(), userID, *req.AutoDumpingEnabled); err != nil {
 			logger.Log.Error("Failed to toggle auto-dumping", zap.Error(err))
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update auto-dumping"})
 			return
@@ -77,9 +78,18 @@ func (h *UserHandler) UpdateSettings(c *gin.Context) {
 		user.AutoDumpingEnabled = *req.AutoDumpingEnabled
 	}
 
+	if req.DumpingDryRun != nil {
+		if err := h.userRepo.ToggleDumpingDryRun(c.Request.Context(), userID, *req.DumpingDryRun); err != nil {
+			logger.Log.Error("Failed to toggle dumping dry-run", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update dumping dry-run"})
+			return
+		}
+		user.DumpingDryRun = *req.DumpingDryRun
+	}
+
 	if req.Language != nil {
 		user.LanguageCode = *req.Language
-		if err := h.userRepo.Update(user); err != nil {
+		if err := h.userRepo.Update(c.Request.Context(), user); err != nil {
 			logger.Log.Error("Failed to update language", zap.Error(err))
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update language"})
 			return
@@ -87,7 +97,7 @@ func (h *UserHandler) UpdateSettings(c *gin.Context) {
 	}
 
 	// Return updated user
-	user, err = h.userRepo.GetByID(userID)
+	user, err = h.userRepo.GetByID(c.Request.Context(), userID)
 	if err != nil || user == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get updated user"})
 		return
@@ -12,11 +12,29 @@ import (
 // RouterConfig holds dependencies for router setup
 type RouterConfig struct {
 	UserRepo           domain.UserRepository
-	KaspiKeyRepo       domain.KaspiKeyRepository
+	ConnectionRepo     domain.MarketplaceConnectionRepository
 	ProductRepo        domain.ProductRepository
+	OrderRepo          domain.OrderRepository
 	ReviewRepo         domain.ReviewRepository
+	SalesHistoryRepo   domain.SalesHistoryRepository
 	AIResponder        *service.AIResponderService
-	SyncService        *service.KaspiSyncService
+	SyncService        *service.SyncService
+	Clients            *service.MarketplaceClients
+	OrderService       *service.OrderService
+	StockService       *service.StockService
+	PricingService     *service.PricingService
+	PriceHistory       *service.PriceHistoryService
+	CompetitorHistory  *service.CompetitorHistoryService
+	DumpingReport      *service.DumpingReportService
+	Backtests          *service.BacktestService
+	PriceGuards        *service.PriceGuardService
+	PriceFeedRepo      domain.PriceFeedRepository
+	PriceChangeRepo    domain.PriceChangeRepository
+	OfferSnapshotRepo  domain.OfferSnapshotRepository
+	PriceProposalRepo  domain.PriceProposalRepository
+	GuardAlertRepo     domain.PriceGuardAlertRepository
+	FeedService        *service.PriceFeedService
+	PublicBaseURL      string
 	Encryptor          *crypto.Encryptor
 	JWTSecret          string
 	JWTExpirationHours int
@@ -40,8 +58,12 @@ func SetupRouter(cfg *RouterConfig) *gin.Engine {
 		// Initialize handlers
 		authHandler := handlers.NewAuthHandler(cfg.UserRepo, cfg.JWTSecret, cfg.JWTExpirationHours)
 		userHandler := handlers.NewUserHandler(cfg.UserRepo)
-		kaspiKeyHandler := handlers.NewKaspiKeyHandler(cfg.KaspiKeyRepo, cfg.Encryptor, cfg.SyncService)
+		connectionHandler := handlers.NewConnectionHandler(cfg.ConnectionRepo, cfg.ProductRepo, cfg.OrderRepo, cfg.ReviewRepo, cfg.SalesHistoryRepo, cfg.PriceFeedRepo, cfg.PriceChangeRepo, cfg.OfferSnapshotRepo, cfg.PriceProposalRepo, cfg.GuardAlertRepo, cfg.Encryptor, cfg.SyncService, cfg.Clients)
+		feedHandler := handlers.NewFeedHandler(cfg.ConnectionRepo, cfg.PriceFeedRepo, cfg.FeedService, cfg.PublicBaseURL)
 		productHandler := handlers.NewProductHandler(cfg.ProductRepo, nil) // Price dumping disabled
+		stockHandler := handlers.NewStockHandler(cfg.ConnectionRepo, cfg.StockService)
+		pricingHandler := handlers.NewPricingHandler(cfg.PricingService, cfg.PriceHistory, cfg.CompetitorHistory, cfg.DumpingReport, cfg.Backtests, cfg.PriceGuards)
+		orderHandler := handlers.NewOrderHandler(cfg.OrderRepo, cfg.OrderService)
 		reviewHandler := handlers.NewReviewHandler(cfg.ReviewRepo, cfg.AIResponder)
 		dashboardHandler := handlers.NewDashboardHandler(cfg.ProductRepo, cfg.ReviewRepo)
 
@@ -51,6 +73,9 @@ func SetupRouter(cfg *RouterConfig) *gin.Engine {
 			auth.POST("/login", authHandler.Login)
 		}
 
+		// Kaspi pulls price lists without a JWT; the secret token in the URL authenticates it
+		v1.GET("/feeds/kaspi/:token", feedHandler.ServeKaspiFeed)
+
 		// Protected routes (auth required)
 		protected := v1.Group("")
 		protected.Use(middleware.AuthMiddleware())
@@ -65,13 +90,19 @@ func SetupRouter(cfg *RouterConfig) *gin.Engine {
 				user.PATCH("/settings", userHandler.UpdateSettings)
 			}
 
-			// Kaspi key endpoints
-			kaspiKey := protected.Group("/kaspi-key")
+			// Marketplace connection endpoints
+			connections := protected.Group("/connections")
 			{
-				kaspiKey.GET("", kaspiKeyHandler.GetKey)
-				kaspiKey.POST("", kaspiKeyHandler.CreateKey)
-				kaspiKey.DELETE("", kaspiKeyHandler.DeleteKey)
-				kaspiKey.POST("/sync", kaspiKeyHandler.SyncNow)
+				connections.GET("", connectionHandler.ListConnections)
+				connections.POST("", connectionHandler.CreateConnection)
+				connections.GET("/:id", connectionHandler.GetConnection)
+				connections.PATCH("/:id", connectionHandler.UpdateConnection)
+				connections.DELETE("/:id", connectionHandler.DeleteConnection)
+				connections.POST("/:id/sync", connectionHandler.SyncConnection)
+				connections.GET("/:id/feed", feedHandler.GetFeed)
+				connections.POST("/:id/feed/regenerate", feedHandler.RegenerateFeed)
+				connections.POST("/:id/feed/rotate-token", feedHandler.RotateFeedToken)
+				connections.POST("/:id/stock/upload", stockHandler.UploadStockCSV)
 			}
 
 			// Product endpoints
@@ -79,13 +110,60 @@ func SetupRouter(cfg *RouterConfig) *gin.Engine {
 			{
 				products.GET("", productHandler.GetProducts)
 				products.GET("/low-stock", productHandler.GetLowStockProducts)
+				products.POST("/prices/bulk", pricingHandler.BulkUpdatePrices)
 				// Temporarily disabled price dumping
 				// products.GET("/dumping", productHandler.GetDumpingProducts)
 				products.GET("/:id", productHandler.GetProduct)
+				products.PUT("/:id/stock", stockHandler.UpdateProductStock)
+				products.PUT("/:id/price", pricingHandler.UpdatePrice)
+				products.GET("/:id/price-history", pricingHandler.GetPriceHistory)
+				products.GET("/:id/competitor-history", pricingHandler.GetCompetitorHistory)
+				products.GET("/:id/competitor-history/sellers", pricingHandler.GetCompetitorSellers)
+				products.PUT("/:id/price-limits", pricingHandler.UpdatePriceLimits)
+				products.PUT("/:id/costs", pricingHandler.UpdateProductCosts)
+				products.DELETE("/:id/costs", pricingHandler.DeleteProductCosts)
+				products.GET("/:id/margin", pricingHandler.GetProductMargin)
+				products.PUT("/:id/cities/:city", pricingHandler.UpdateCityPrice)
+				products.GET("/:id/strategy", pricingHandler.GetProductStrategy)
+				products.PUT("/:id/strategy", pricingHandler.UpdateProductStrategy)
+				products.DELETE("/:id/strategy", pricingHandler.ResetProductStrategy)
+				products.PUT("/:id/tags", pricingHandler.UpdateProductTags)
+				products.PUT("/:id/dry-run", pricingHandler.UpdateProductDryRun)
+				products.POST("/:id/backtest", pricingHandler.RunBacktest)
+				products.DELETE("/:id/dumping-pause", pricingHandler.ResumeDumping)
+				products.GET("/:id/competitor-filter", pricingHandler.GetProductCompetitorFilter)
+				products.PUT("/:id/competitor-filter", pricingHandler.UpdateProductCompetitorFilter)
+				products.DELETE("/:id/competitor-filter", pricingHandler.ResetProductCompetitorFilter)
 				// products.POST("/:id/dumping/enable", productHandler.EnableDumping)
 				// products.POST("/:id/dumping/disable", productHandler.DisableDumping)
 			}
 
+			// Pricing strategy endpoints
+			pricing := protected.Group("/pricing")
+			{
+				pricing.GET("/strategies", pricingHandler.GetTagStrategies)
+				pricing.PUT("/strategies/:tag", pricingHandler.UpdateTagStrategy)
+				pricing.DELETE("/strategies/:tag", pricingHandler.DeleteTagStrategy)
+				pricing.GET("/competitor-filter", pricingHandler.GetCompetitorFilter)
+				pricing.PUT("/competitor-filter", pricingHandler.UpdateCompetitorFilter)
+				pricing.GET("/dry-run/report", pricingHandler.GetDryRunReport)
+				pricing.GET("/price-guard", pricingHandler.GetPriceGuard)
+				pricing.PUT("/price-guard", pricingHandler.UpdatePriceGuard)
+				pricing.DELETE("/price-guard", pricingHandler.ResetPriceGuard)
+				pricing.GET("/alerts", pricingHandler.GetPriceGuardAlerts)
+			}
+
+			// Order endpoints
+			orders := protected.Group("/orders")
+			{
+				orders.GET("", orderHandler.GetOrders)
+				orders.POST("/bulk", orderHandler.BulkOrderAction)
+				orders.GET("/:id", orderHandler.GetOrder)
+				orders.POST("/:id/accept", orderHandler.AcceptOrder)
+				orders.POST("/:id/ready", orderHandler.MarkOrderReady)
+				orders.POST("/:id/cancel", orderHandler.CancelOrder)
+			}
+
 			// Review endpoints
 			reviews := protected.Group("/reviews")
 			{
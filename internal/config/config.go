@@ -19,8 +19,27 @@ type Config struct {
 	Environment        string
 	SyncIntervalHours  int
 	LogLevel           string
+	KaspiBaseURL       string
+	KaspiPageSize      int
+	KaspiMaxPages      int
+	KaspiRateLimitRPS  int
+	KaspiRateBurst     int
+	KaspiMaxAttempts   int
+	WildberriesBaseURL string
+	OzonBaseURL        string
+	PublicBaseURL      string
+	PriceHistoryDays   int
+	OfferHistoryDays   int
+	PriceDumpingMode   string
 }
 
+// Price dumping modes of the worker
+const (
+	PriceDumpingOff    = "off"     // Not run
+	PriceDumpingDryRun = "dry_run" // Prices are proposed for every product but never changed
+	PriceDumpingOn     = "on"      // Prices are changed, except for users and products in dry-run mode
+)
+
 func Load() (*Config, error) {
 	// Load .env file if exists (for local development)
 	_ = godotenv.Load()
@@ -36,6 +55,18 @@ func Load() (*Config, error) {
 		Environment:        getEnv("ENVIRONMENT", "production"),
 		SyncIntervalHours:  getEnvAsInt("SYNC_INTERVAL_HOURS", 6),
 		LogLevel:           getEnv("LOG_LEVEL", "info"),
+		KaspiBaseURL:       getEnv("KASPI_BASE_URL", ""), // empty uses production Kaspi API
+		KaspiPageSize:      getEnvAsInt("KASPI_PAGE_SIZE", 100),
+		KaspiMaxPages:      getEnvAsInt("KASPI_MAX_PAGES", 500),
+		KaspiRateLimitRPS:  getEnvAsInt("KASPI_RATE_LIMIT_RPS", 5),
+		KaspiRateBurst:     getEnvAsInt("KASPI_RATE_BURST", 10),
+		KaspiMaxAttempts:   getEnvAsInt("KASPI_MAX_ATTEMPTS", 4),
+		WildberriesBaseURL: getEnv("WILDBERRIES_BASE_URL", ""), // empty uses production Wildberries APIs
+		OzonBaseURL:        getEnv("OZON_BASE_URL", ""),        // empty uses production Ozon Seller API
+		PublicBaseURL:      getEnv("PUBLIC_BASE_URL", ""),      // external API URL, used in price feed links
+		PriceHistoryDays:   getEnvAsInt("PRICE_HISTORY_RETENTION_DAYS", 180),
+		OfferHistoryDays:   getEnvAsInt("COMPETITOR_HISTORY_RETENTION_DAYS", 90),
+		PriceDumpingMode:   getEnv("PRICE_DUMPING_MODE", PriceDumpingOff),
 	}
 
 	if err := cfg.validate(); err != nil {
@@ -61,6 +92,11 @@ func (c *Config) validate() error {
 	if c.JWTSecret == "" {
 		return fmt.Errorf("JWT_SECRET is required")
 	}
+	switch c.PriceDumpingMode {
+	case PriceDumpingOff, PriceDumpingDryRun, PriceDumpingOn:
+	default:
+		return fmt.Errorf("PRICE_DUMPING_MODE must be %s, %s or %s", PriceDumpingOff, PriceDumpingDryRun, PriceDumpingOn)
+	}
 	return nil
 }
 
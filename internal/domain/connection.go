@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Marketplaces a connection can point at
+const (
+	MarketplaceKaspi       = "kaspi"
+	MarketplaceWildberries = "wildberries"
+	MarketplaceOzon        = "ozon"
+)
+
+// MarketplaceConnection is one seller account on a marketplace, with encrypted credentials.
+// A user may have any number of connections, including several on the same marketplace.
+type MarketplaceConnection struct {
+	ID                 string    `bson:"_id,omitempty" json:"id"`
+	UserID             string    `bson:"user_id" json:"user_id"`
+	Name               string    `bson:"name" json:"name"`               // User-facing label, e.g. "Almaty shop"
+	Marketplace        string    `bson:"marketplace" json:"marketplace"` // kaspi, wildberries or ozon
+	APIKeyEncrypted    string    `bson:"api_key_encrypted" json:"-"`
+	APISecretEncrypted string    `bson:"api_secret_encrypted" json:"-"`
+	MerchantID         string    `bson:"merchant_id" json:"merchant_id"`                         // Kaspi merchant ID / Ozon Client-Id; unused for Wildberries
+	PickupPoints       []string  `bson:"pickup_points,omitempty" json:"pickup_points,omitempty"` // Kaspi store IDs listed in the price feed
+	IsActive           bool      `bson:"is_active" json:"is_active"`
+	AuthFailures       int       `bson:"auth_failures" json:"auth_failures"`                               // Consecutive unauthorized responses
+	DeactivatedReason  string    `bson:"deactivated_reason,omitempty" json:"deactivated_reason,omitempty"` // Why the connection was switched off automatically
+	DeactivatedAt      time.Time `bson:"deactivated_at,omitempty" json:"deactivated_at,omitempty"`
+	LastSyncAt         time.Time `bson:"last_sync_at,omitempty" json:"last_sync_at,omitempty"`
+	CreatedAt          time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt          time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+type MarketplaceConnectionRepository interface {
+	Create(ctx context.Context, conn *MarketplaceConnection) error
+	GetByID(ctx context.Context, id string) (*MarketplaceConnection, error)
+	GetByUserID(ctx context.Context, userID string) ([]MarketplaceConnection, error)
+	GetAllActive(ctx context.Context) ([]MarketplaceConnection, error)
+	Update(ctx context.Context, conn *MarketplaceConnection) error
+	UpdateSyncState(ctx context.Context, id string, lastSyncAt time.Time) error
+	IncrementAuthFailures(ctx context.Context, id string) (int, error)
+	ResetAuthFailures(ctx context.Context, id string) error
+	Deactivate(ctx context.Context, id, reason string) (bool, error)
+	Delete(ctx context.Context, id string) error
+}
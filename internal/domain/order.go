@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Order statuses
+const (
+	OrderStatusNew       = "new"
+	OrderStatusAccepted  = "accepted"
+	OrderStatusReady     = "ready" // Ready for pickup or handed over for shipping
+	OrderStatusDelivered = "delivered"
+	OrderStatusCancelled = "cancelled"
+	OrderStatusReturned  = "returned"
+)
+
+type Order struct {
+	ID           string              `bson:"_id,omitempty" json:"id"`
+	UserID       string              `bson:"user_id" json:"user_id"`
+	ConnectionID string              `bson:"connection_id" json:"connection_id"` // Marketplace connection the order was synced from
+	Marketplace  string              `bson:"marketplace" json:"marketplace"`
+	ExternalID   string              `bson:"external_id" json:"external_id"` // Order ID on the marketplace
+	Status       string              `bson:"status" json:"status"`
+	CustomerCity string              `bson:"customer_city" json:"customer_city"`
+	DeliveryType string              `bson:"delivery_type" json:"delivery_type"`
+	WarehouseID  string              `bson:"warehouse_id,omitempty" json:"warehouse_id,omitempty"` // Warehouse or pickup point fulfilling the order
+	TotalPrice   float64             `bson:"total_price" json:"total_price"`
+	Lines        []OrderLine         `bson:"lines" json:"lines"`
+	History      []OrderStatusChange `bson:"history,omitempty" json:"history,omitempty"` // Status changes made through the API
+	OrderedAt    time.Time           `bson:"ordered_at" json:"ordered_at"`               // When the customer placed the order
+	ChangedAt    time.Time           `bson:"changed_at" json:"changed_at"`               // Last change on the marketplace
+	LastSyncAt   time.Time           `bson:"last_sync_at" json:"last_sync_at"`           // Last time we pulled it
+	CreatedAt    time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+type OrderLine struct {
+	ProductID         string  `bson:"product_id,omitempty" json:"product_id,omitempty"` // Reference to Product._id
+	ProductExternalID string  `bson:"product_external_id" json:"product_external_id"`
+	Name              string  `bson:"name" json:"name"`
+	Quantity          int     `bson:"quantity" json:"quantity"`
+	Price             float64 `bson:"price" json:"price"` // Unit price
+}
+
+// Fulfillment actions an operator can take on an order
+const (
+	OrderActionAccept = "accept"
+	OrderActionReady  = "ready"
+	OrderActionCancel = "cancel"
+)
+
+// ErrOrderTransition is returned when an action is not allowed in the order's current status
+var ErrOrderTransition = errors.New("order status does not allow this action")
+
+// orderTransitions lists, per action, the statuses it may be taken from and the status it leads to
+var orderTransitions = map[string]struct {
+	from []string
+	to   string
+}{
+	OrderActionAccept: {from: []string{OrderStatusNew}, to: OrderStatusAccepted},
+	OrderActionReady:  {from: []string{OrderStatusAccepted}, to: OrderStatusReady},
+	OrderActionCancel: {from: []string{OrderStatusNew, OrderStatusAccepted, OrderStatusReady}, to: OrderStatusCancelled},
+}
+
+// OrderStatusChange records one fulfillment action and who took it
+type OrderStatusChange struct {
+	Action string    `bson:"action" json:"action"`
+	From   string    `bson:"from" json:"from"`
+	To     string    `bson:"to" json:"to"`
+	Reason string    `bson:"reason,omitempty" json:"reason,omitempty"` // Cancellation reason code
+	UserID string    `bson:"user_id" json:"user_id"`                   // Acting user
+	At     time.Time `bson:"at" json:"at"`
+}
+
+// NextStatus returns the status the action leads to, or ErrOrderTransition
+// if the order's lifecycle does not allow it from the current status
+func (o *Order) NextStatus(action string) (string, error) {
+	t, ok := orderTransitions[action]
+	if !ok {
+		return "", fmt.Errorf("unknown order action %q", action)
+	}
+
+	for _, from := range t.from {
+		if o.Status == from {
+			return t.to, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: cannot %s an order that is %s", ErrOrderTransition, action, o.Status)
+}
+
+// CountsAsSale reports whether the order's lines count towards sales history
+func (o *Order) CountsAsSale() bool {
+	return o.Status != OrderStatusCancelled && o.Status != OrderStatusReturned
+}
+
+// OrderFilter narrows order listings; zero values are ignored
+type OrderFilter struct {
+	ConnectionID string
+	Status       string
+	Since        time.Time // OrderedAt lower bound
+	Limit        int
+}
+
+type OrderRepository interface {
+	GetByID(ctx context.Context, id string) (*Order, error)
+	GetByUserID(ctx context.Context, userID string, filter OrderFilter) ([]Order, error)
+	GetByConnectionID(ctx context.Context, connectionID string, since time.Time) ([]Order, error)
+	GetByProductID(ctx context.Context, productID string, since time.Time) ([]Order, error)
+	UpsertOrder(ctx context.Context, order *Order) error
+	RecordStatusChange(ctx context.Context, id string, change OrderStatusChange) error
+	DeleteByConnectionID(ctx context.Context, connectionID string) error
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PriceFeed is the rendered XML price list of one Kaspi connection. Kaspi pulls it
+// from a public URL that is authenticated only by the secret Token.
+type PriceFeed struct {
+	ID           string    `bson:"_id,omitempty" json:"id"`
+	UserID       string    `bson:"user_id" json:"user_id"`
+	ConnectionID string    `bson:"connection_id" json:"connection_id"`
+	Token        string    `bson:"token" json:"-"`
+	Content      string    `bson:"content" json:"-"` // Rendered XML
+	OfferCount   int       `bson:"offer_count" json:"offer_count"`
+	SkippedCount int       `bson:"skipped_count" json:"skipped_count"` // Products left out because they failed validation
+	GeneratedAt  time.Time `bson:"generated_at" json:"generated_at"`
+	CreatedAt    time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+type PriceFeedRepository interface {
+	Save(ctx context.Context, feed *PriceFeed) error
+	UpdateToken(ctx context.Context, feed *PriceFeed) error
+	GetByConnectionID(ctx context.Context, connectionID string) (*PriceFeed, error)
+	GetByToken(ctx context.Context, token string) (*PriceFeed, error)
+	DeleteByConnectionID(ctx context.Context, connectionID string) error
+}
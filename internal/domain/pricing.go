@@ -0,0 +1,248 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Repricing strategy types
+const (
+	StrategyUndercutAmount  = "undercut_amount"  // Amount under the cheapest competitor
+	StrategyUndercutPercent = "undercut_percent" // Percent under the cheapest competitor
+	StrategyMatchLowest     = "match_lowest"     // Same price as the cheapest competitor
+	StrategyTargetRank      = "target_rank"      // Amount under the competitor at Rank, e.g. 2nd cheapest
+	StrategyMedianBand      = "median_band"      // Amount under the cheapest, but at most Percent under the median
+)
+
+// PricingStrategyConfig selects a repricing strategy and its parameters.
+// Which parameters apply depends on Type.
+type PricingStrategyConfig struct {
+	Type    string  `bson:"type" json:"type"`
+	Amount  float64 `bson:"amount,omitempty" json:"amount,omitempty"`
+	Percent float64 `bson:"percent,omitempty" json:"percent,omitempty"`
+	Rank    int     `bson:"rank,omitempty" json:"rank,omitempty"`
+}
+
+// TagPricingStrategy is the repricing strategy of the user's products carrying a tag,
+// used by those that have no strategy of their own
+type TagPricingStrategy struct {
+	ID        string                `bson:"_id,omitempty" json:"id"`
+	UserID    string                `bson:"user_id" json:"user_id"`
+	Tag       string                `bson:"tag" json:"tag"`
+	Strategy  PricingStrategyConfig `bson:"strategy" json:"strategy"`
+	CreatedAt time.Time             `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time             `bson:"updated_at" json:"updated_at"`
+}
+
+type TagPricingStrategyRepository interface {
+	Save(ctx context.Context, strategy *TagPricingStrategy) error
+	GetByUserID(ctx context.Context, userID string) ([]TagPricingStrategy, error)
+	GetByTag(ctx context.Context, userID, tag string) (*TagPricingStrategy, error)
+	Delete(ctx context.Context, userID, tag string) error
+}
+
+// CompetitorFilter decides which competitor offers repricing follows. Offers it excludes
+// are neither undercut nor counted in the competitor min price.
+type CompetitorFilter struct {
+	IgnoreSellers   []string `bson:"ignore_sellers,omitempty" json:"ignore_sellers,omitempty"`       // Never followed, e.g. our own other shops
+	OnlySellers     []string `bson:"only_sellers,omitempty" json:"only_sellers,omitempty"`           // When set, the only sellers followed
+	MaxDeliveryDays int      `bson:"max_delivery_days,omitempty" json:"max_delivery_days,omitempty"` // Slower offers are not followed; 0 for no limit
+	MinSellerRating float64  `bson:"min_seller_rating,omitempty" json:"min_seller_rating,omitempty"` // Lower rated sellers are not followed; 0 for no limit
+}
+
+// IsZero reports whether the filter excludes nothing
+func (f CompetitorFilter) IsZero() bool {
+	return len(f.IgnoreSellers) == 0 && len(f.OnlySellers) == 0 && f.MaxDeliveryDays == 0 && f.MinSellerRating == 0
+}
+
+// UserCompetitorFilter is the competitor filter of all of a user's products
+type UserCompetitorFilter struct {
+	ID        string           `bson:"_id,omitempty" json:"id"`
+	UserID    string           `bson:"user_id" json:"user_id"`
+	Filter    CompetitorFilter `bson:"filter" json:"filter"`
+	CreatedAt time.Time        `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time        `bson:"updated_at" json:"updated_at"`
+}
+
+type CompetitorFilterRepository interface {
+	Save(ctx context.Context, filter *UserCompetitorFilter) error
+	GetByUserID(ctx context.Context, userID string) (*UserCompetitorFilter, error)
+	Delete(ctx context.Context, userID string) error
+}
+
+// Why a competitor offer was not followed
+const (
+	ExcludeIgnoredSeller = "ignored_seller" // In the ignore list
+	ExcludeNotFollowed   = "not_followed"   // Not in the follow-only list
+	ExcludeSlowDelivery  = "slow_delivery"  // Delivers slower than MaxDeliveryDays
+	ExcludeLowRating     = "low_rating"     // Rated below MinSellerRating
+)
+
+// What changed a price
+const (
+	PriceTriggerAuto   = "auto"   // Price dumping
+	PriceTriggerManual = "manual" // The user, for one product
+	PriceTriggerBulk   = "bulk"   // The user, in a bulk update
+)
+
+// PriceActorSystem is the actor of automatic price changes
+const PriceActorSystem = "system"
+
+// PriceChange is one change of a product's price, with what the market looked like at the time
+type PriceChange struct {
+	ID                 string                 `bson:"_id,omitempty" json:"id"`
+	ProductID          string                 `bson:"product_id" json:"product_id"`
+	UserID             string                 `bson:"user_id" json:"user_id"`
+	ConnectionID       string                 `bson:"connection_id" json:"connection_id"`
+	City               string                 `bson:"city,omitempty" json:"city,omitempty"` // Empty for the product's base price
+	OldPrice           float64                `bson:"old_price" json:"old_price"`
+	NewPrice           float64                `bson:"new_price" json:"new_price"`
+	CompetitorMinPrice float64                `bson:"competitor_min_price" json:"competitor_min_price"`
+	Competitors        []CompetitorSnapshot   `bson:"competitors,omitempty" json:"competitors,omitempty"` // Offers the price followed
+	Excluded           []ExcludedOffer        `bson:"excluded,omitempty" json:"excluded,omitempty"`       // Offers the competitor filter left out
+	Strategy           *PricingStrategyConfig `bson:"strategy,omitempty" json:"strategy,omitempty"`       // Auto changes only
+	Reason             string                 `bson:"reason,omitempty" json:"reason,omitempty"`           // Why dumping chose the price, e.g. above_floor
+	Trigger            string                 `bson:"trigger" json:"trigger"`                             // auto, manual or bulk
+	Actor              string                 `bson:"actor" json:"actor"`                                 // User ID, or system for auto
+	CreatedAt          time.Time              `bson:"created_at" json:"created_at"`
+}
+
+// CompetitorSnapshot is a competitor offer as seen when a price was changed
+type CompetitorSnapshot struct {
+	SellerID   string  `bson:"seller_id" json:"seller_id"`
+	SellerName string  `bson:"seller_name" json:"seller_name"`
+	Price      float64 `bson:"price" json:"price"`
+}
+
+// ExcludedOffer is a competitor offer the competitor filter left out, and why
+type ExcludedOffer struct {
+	SellerID   string  `bson:"seller_id" json:"seller_id"`
+	SellerName string  `bson:"seller_name" json:"seller_name"`
+	Price      float64 `bson:"price" json:"price"`
+	Reason     string  `bson:"reason" json:"reason"` // ignored_seller, not_followed, slow_delivery or low_rating
+}
+
+type PriceChangeRepository interface {
+	Create(ctx context.Context, change *PriceChange) error
+	GetByProductID(ctx context.Context, productID string, since time.Time, limit int) ([]PriceChange, error)
+	DeleteOlderThan(ctx context.Context, before time.Time) (int64, error)
+	DeleteByConnectionID(ctx context.Context, connectionID string) error
+}
+
+// OfferSnapshot is the competitor offers of a product as observed by one dumping run
+type OfferSnapshot struct {
+	ID           string          `bson:"_id,omitempty" json:"id"`
+	ProductID    string          `bson:"product_id" json:"product_id"`
+	UserID       string          `bson:"user_id" json:"user_id"`
+	ConnectionID string          `bson:"connection_id" json:"connection_id"`
+	City         string          `bson:"city,omitempty" json:"city,omitempty"` // Empty when the offers of all cities were observed
+	OurPrice     float64         `bson:"our_price" json:"our_price"`
+	MinPrice     float64         `bson:"min_price" json:"min_price"`
+	MedianPrice  float64         `bson:"median_price" json:"median_price"`
+	Offers       []ObservedOffer `bson:"offers" json:"offers"`
+	Appeared     []string        `bson:"appeared,omitempty" json:"appeared,omitempty"`       // Sellers not in the previous snapshot
+	Disappeared  []string        `bson:"disappeared,omitempty" json:"disappeared,omitempty"` // Sellers of the previous snapshot now gone
+	ObservedAt   time.Time       `bson:"observed_at" json:"observed_at"`
+}
+
+// ObservedOffer is one competitor offer in a snapshot
+type ObservedOffer struct {
+	SellerID   string  `bson:"seller_id" json:"seller_id"`
+	SellerName string  `bson:"seller_name" json:"seller_name"`
+	Price      float64 `bson:"price" json:"price"`
+	Position   int     `bson:"position" json:"position"` // 1 for the cheapest competitor
+}
+
+type OfferSnapshotRepository interface {
+	Create(ctx context.Context, snapshot *OfferSnapshot) error
+	GetLatest(ctx context.Context, productID, city string) (*OfferSnapshot, error)
+	GetByProductID(ctx context.Context, productID, city string, since time.Time) ([]OfferSnapshot, error)
+	DeleteOlderThan(ctx context.Context, before time.Time) (int64, error)
+	DeleteByConnectionID(ctx context.Context, connectionID string) error
+}
+
+// PriceProposal is the price dumping would set in dry-run mode, kept per product and city
+// and replaced on every run
+type PriceProposal struct {
+	ID                 string                `bson:"_id,omitempty" json:"id"`
+	ProductID          string                `bson:"product_id" json:"product_id"`
+	UserID             string                `bson:"user_id" json:"user_id"`
+	ConnectionID       string                `bson:"connection_id" json:"connection_id"`
+	ProductName        string                `bson:"product_name" json:"product_name"`
+	SKU                string                `bson:"sku" json:"sku"`
+	City               string                `bson:"city,omitempty" json:"city,omitempty"` // Empty for the product's base price
+	CurrentPrice       float64               `bson:"current_price" json:"current_price"`
+	ProposedPrice      float64               `bson:"proposed_price" json:"proposed_price"` // Equal to CurrentPrice when dumping would hold
+	CompetitorMinPrice float64               `bson:"competitor_min_price" json:"competitor_min_price"`
+	CompetitorCount    int                   `bson:"competitor_count" json:"competitor_count"` // Offers followed
+	Excluded           []ExcludedOffer       `bson:"excluded,omitempty" json:"excluded,omitempty"`
+	Floor              float64               `bson:"floor" json:"floor"`
+	Ceiling            float64               `bson:"ceiling" json:"ceiling"`
+	Strategy           PricingStrategyConfig `bson:"strategy" json:"strategy"`
+	Reason             string                `bson:"reason" json:"reason"`
+	Guard              string                `bson:"guard,omitempty" json:"guard,omitempty"` // Price guard that would have paused dumping
+	ProposedAt         time.Time             `bson:"proposed_at" json:"proposed_at"`
+}
+
+type PriceProposalRepository interface {
+	Save(ctx context.Context, proposal *PriceProposal) error
+	GetByUserID(ctx context.Context, userID, connectionID string, since time.Time) ([]PriceProposal, error)
+	DeleteByConnectionID(ctx context.Context, connectionID string) error
+}
+
+// PriceGuard keeps price dumping from racing another bot down to the floor. It caps how much
+// a price may fall and pauses repricing of a product caught in a price war. 0 turns a limit off.
+type PriceGuard struct {
+	MaxDecreasePercentPerCycle float64 `bson:"max_decrease_percent_per_cycle" json:"max_decrease_percent_per_cycle"` // Largest cut in one run
+	MaxDecreasePercentPerDay   float64 `bson:"max_decrease_percent_per_day" json:"max_decrease_percent_per_day"`     // Largest fall over 24 hours; reaching it pauses the product
+	MaxConsecutiveUndercuts    int     `bson:"max_consecutive_undercuts" json:"max_consecutive_undercuts"`           // Cuts in a row after which the product pauses
+	PingPongRounds             int     `bson:"ping_pong_rounds" json:"ping_pong_rounds"`                             // Rounds of undercutting one competitor who undercuts back, after which the product pauses
+	CooldownMinutes            int     `bson:"cooldown_minutes" json:"cooldown_minutes"`                             // Pause after too many undercuts or a ping-pong
+}
+
+// UserPriceGuard is the price guard of all of a user's products
+type UserPriceGuard struct {
+	ID        string     `bson:"_id,omitempty" json:"id"`
+	UserID    string     `bson:"user_id" json:"user_id"`
+	Guard     PriceGuard `bson:"guard" json:"guard"`
+	CreatedAt time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time  `bson:"updated_at" json:"updated_at"`
+}
+
+type PriceGuardRepository interface {
+	Save(ctx context.Context, guard *UserPriceGuard) error
+	GetByUserID(ctx context.Context, userID string) (*UserPriceGuard, error)
+	Delete(ctx context.Context, userID string) error
+}
+
+// Price guards that pause repricing of a product
+const (
+	GuardDailyDecrease        = "daily_decrease"        // The price fell MaxDecreasePercentPerDay within 24 hours
+	GuardConsecutiveUndercuts = "consecutive_undercuts" // MaxConsecutiveUndercuts cuts in a row
+	GuardPingPong             = "ping_pong"             // PingPongRounds rounds with the same competitor
+)
+
+// PriceGuardAlert tells the user that a price guard paused repricing of a product
+type PriceGuardAlert struct {
+	ID           string    `bson:"_id,omitempty" json:"id"`
+	ProductID    string    `bson:"product_id" json:"product_id"`
+	UserID       string    `bson:"user_id" json:"user_id"`
+	ConnectionID string    `bson:"connection_id" json:"connection_id"`
+	ProductName  string    `bson:"product_name" json:"product_name"`
+	SKU          string    `bson:"sku" json:"sku"`
+	City         string    `bson:"city,omitempty" json:"city,omitempty"` // Empty for the product's base price
+	Guard        string    `bson:"guard" json:"guard"`                   // daily_decrease, consecutive_undercuts or ping_pong
+	Message      string    `bson:"message" json:"message"`
+	SellerID     string    `bson:"seller_id,omitempty" json:"seller_id,omitempty"` // The competitor of a ping-pong
+	SellerName   string    `bson:"seller_name,omitempty" json:"seller_name,omitempty"`
+	Price        float64   `bson:"price" json:"price"`               // Price kept
+	TargetPrice  float64   `bson:"target_price" json:"target_price"` // Price dumping wanted to set
+	PausedUntil  time.Time `bson:"paused_until" json:"paused_until"`
+	CreatedAt    time.Time `bson:"created_at" json:"created_at"`
+}
+
+type PriceGuardAlertRepository interface {
+	Create(ctx context.Context, alert *PriceGuardAlert) error
+	GetByUserID(ctx context.Context, userID string, since time.Time) ([]PriceGuardAlert, error)
+	DeleteByConnectionID(ctx context.Context, connectionID string) error
+}
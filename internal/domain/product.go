@@ -1,30 +1,157 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+)
 
 type Product struct {
-	ID                 string    `bson:"_id,omitempty" json:"id"`
-	UserID             string    `bson:"user_id" json:"user_id"`
-	ExternalID         string    `bson:"external_id" json:"external_id"` // Kaspi product ID
-	SKU                string    `bson:"sku" json:"sku"`
-	Name               string    `bson:"name" json:"name"`
-	CurrentStock       int       `bson:"current_stock" json:"current_stock"`
+	ID                 string                 `bson:"_id,omitempty" json:"id"`
+	UserID             string                 `bson:"user_id" json:"user_id"`
+	ConnectionID       string                 `bson:"connection_id" json:"connection_id"` // Marketplace connection the product was synced from
+	ExternalID         string                 `bson:"external_id" json:"external_id"`     // Product ID on the marketplace
+	SKU                string                 `bson:"sku" json:"sku"`
+	Name               string                 `bson:"name" json:"name"`
+	CurrentStock       int                    `bson:"current_stock" json:"current_stock"`       // Total across all warehouses
+	Stocks             []WarehouseStock       `bson:"stocks,omitempty" json:"stocks,omitempty"` // Per warehouse / pickup point
+	Price              float64                `bson:"price" json:"price"`
+	MinPrice           float64                `bson:"min_price" json:"min_price"`                                           // Dumping floor set by hand
+	Costs              *ProductCosts          `bson:"costs,omitempty" json:"costs,omitempty"`                               // Cost price and fees; they set the margin floor
+	MaxPrice           float64                `bson:"max_price" json:"max_price"`                                           // Price ceiling; 0 for none
+	CompetitorMinPrice float64                `bson:"competitor_min_price" json:"competitor_min_price"`                     // Lowest competitor price
+	CityPrices         []CityPrice            `bson:"city_prices,omitempty" json:"city_prices,omitempty"`                   // Prices per city (Kaspi)
+	AutoDumpingEnabled bool                   `bson:"auto_dumping_enabled" json:"auto_dumping_enabled"`                     // Whether auto dumping is on
+	DumpingDryRun      bool                   `bson:"dumping_dry_run" json:"dumping_dry_run"`                               // Auto dumping only proposes prices without changing them
+	DumpingPausedUntil time.Time              `bson:"dumping_paused_until,omitempty" json:"dumping_paused_until,omitempty"` // The price war guard paused auto dumping until then
+	DumpingPauseReason string                 `bson:"dumping_pause_reason,omitempty" json:"dumping_pause_reason,omitempty"` // The guard that tripped
+	Strategy           *PricingStrategyConfig `bson:"pricing_strategy,omitempty" json:"pricing_strategy,omitempty"`         // Dumping strategy; nil for the tag's or the default one
+	Tags               []string               `bson:"tags,omitempty" json:"tags,omitempty"`                                 // Set by the user, kept across syncs
+	CompetitorFilter   *CompetitorFilter      `bson:"competitor_filter,omitempty" json:"competitor_filter,omitempty"`       // Combined with the user's filter
+	Currency           string                 `bson:"currency" json:"currency"`
+	SalesVelocity      float64                `bson:"sales_velocity" json:"sales_velocity"`
+	DaysOfStock        int                    `bson:"days_of_stock" json:"days_of_stock"`
+	LastPriceCheckAt   time.Time              `bson:"last_price_check_at" json:"last_price_check_at"`
+	LastSyncAt         time.Time              `bson:"last_sync_at" json:"last_sync_at"`
+	CreatedAt          time.Time              `bson:"created_at" json:"created_at"`
+	UpdatedAt          time.Time              `bson:"updated_at" json:"updated_at"`
+}
+
+// DumpingPaused reports whether a price guard has paused dumping of the product at now
+func (p *Product) DumpingPaused(now time.Time) bool {
+	return p.DumpingPausedUntil.After(now)
+}
+
+// WarehouseStock is a product's stock at one warehouse or pickup point, with days of
+// stock calculated from the orders fulfilled there
+type WarehouseStock struct {
+	WarehouseID   string  `bson:"warehouse_id" json:"warehouse_id"`
+	Name          string  `bson:"name,omitempty" json:"name,omitempty"`
+	City          string  `bson:"city,omitempty" json:"city,omitempty"`
+	Quantity      int     `bson:"quantity" json:"quantity"`
+	SalesVelocity float64 `bson:"sales_velocity" json:"sales_velocity"`
+	DaysOfStock   int     `bson:"days_of_stock" json:"days_of_stock"`
+}
+
+// Stock returns the product's stock at a warehouse, or nil if it has none there
+func (p *Product) Stock(warehouseID string) *WarehouseStock {
+	for i := range p.Stocks {
+		if p.Stocks[i].WarehouseID == warehouseID {
+			return &p.Stocks[i]
+		}
+	}
+	return nil
+}
+
+// CityPrice is a product's price in one city. Kaspi prices and competitor offers
+// differ by city, so dumping runs for each city on its own with its own floor.
+type CityPrice struct {
+	City               string    `bson:"city" json:"city"`
 	Price              float64   `bson:"price" json:"price"`
-	MinPrice           float64   `bson:"min_price" json:"min_price"`                       // Минимальная цена для демпинга
-	CompetitorMinPrice float64   `bson:"competitor_min_price" json:"competitor_min_price"` // Минимальная цена конкурентов
-	AutoDumpingEnabled bool      `bson:"auto_dumping_enabled" json:"auto_dumping_enabled"` // Включен ли автодемпинг
-	Currency           string    `bson:"currency" json:"currency"`
-	SalesVelocity      float64   `bson:"sales_velocity" json:"sales_velocity"`
-	DaysOfStock        int       `bson:"days_of_stock" json:"days_of_stock"`
+	MinPrice           float64   `bson:"min_price" json:"min_price"` // 0 falls back to the product's MinPrice
+	MaxPrice           float64   `bson:"max_price" json:"max_price"` // 0 falls back to the product's MaxPrice
+	CompetitorMinPrice float64   `bson:"competitor_min_price" json:"competitor_min_price"`
 	LastPriceCheckAt   time.Time `bson:"last_price_check_at" json:"last_price_check_at"`
-	LastSyncAt         time.Time `bson:"last_sync_at" json:"last_sync_at"`
-	CreatedAt          time.Time `bson:"created_at" json:"created_at"`
-	UpdatedAt          time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// CityPrice returns the product's price entry for a city, or nil if it has none there
+func (p *Product) CityPrice(city string) *CityPrice {
+	for i := range p.CityPrices {
+		if strings.EqualFold(p.CityPrices[i].City, city) {
+			return &p.CityPrices[i]
+		}
+	}
+	return nil
+}
+
+// FloorFor returns the minimum price dumping may go down to in a city: the min price set
+// by hand, raised to the cost floor when the product's costs require more
+func (p *Product) FloorFor(city *CityPrice) float64 {
+	floor := p.MinPrice
+	if city != nil && city.MinPrice > 0 {
+		floor = city.MinPrice
+	}
+	if p.Costs != nil {
+		floor = math.Max(floor, p.Costs.Floor())
+	}
+	return floor
+}
+
+// CeilingFor returns the maximum price dumping may raise to in a city (0 for none)
+func (p *Product) CeilingFor(city *CityPrice) float64 {
+	if city != nil && city.MaxPrice > 0 {
+		return city.MaxPrice
+	}
+	return p.MaxPrice
+}
+
+// ProductCosts is what selling one unit of a product costs the seller, and the margin it must keep
+type ProductCosts struct {
+	CostPrice         float64 `bson:"cost_price" json:"cost_price"`                 // Cost of goods per unit
+	CommissionPercent float64 `bson:"commission_percent" json:"commission_percent"` // Marketplace category commission, percent of the price
+	Fee               float64 `bson:"fee" json:"fee"`                               // Delivery / fulfillment fee per unit
+	MinMarginPercent  float64 `bson:"min_margin_percent" json:"min_margin_percent"` // Margin to keep, percent of the price
+}
+
+// Floor returns the lowest whole price that keeps the minimum margin after commission and fee
+// (0 when the commission and margin take the whole price)
+func (c ProductCosts) Floor() float64 {
+	share := 1 - (c.CommissionPercent+c.MinMarginPercent)/100
+	if share <= 0 {
+		return 0
+	}
+	return math.Ceil((c.CostPrice + c.Fee) / share)
+}
+
+// MarginAt returns what selling one unit at a price leaves after commission, fee and cost
+func (c ProductCosts) MarginAt(price float64) Margin {
+	commission := price * c.CommissionPercent / 100
+	profit := price - commission - c.Fee - c.CostPrice
+
+	m := Margin{
+		Price:      price,
+		Commission: math.Round(commission*100) / 100,
+		Profit:     math.Round(profit*100) / 100,
+	}
+	if price > 0 {
+		m.MarginPercent = math.Round(profit/price*10000) / 100
+	}
+	return m
+}
+
+// Margin is the profit of one unit sold at a price
+type Margin struct {
+	Price         float64 `json:"price"`
+	Commission    float64 `json:"commission"`
+	Profit        float64 `json:"profit"`
+	MarginPercent float64 `json:"margin_percent"` // Profit as a percent of the price
 }
 
 type SalesHistory struct {
 	ID           string    `bson:"_id,omitempty" json:"id"`
 	ProductID    string    `bson:"product_id" json:"product_id"`
+	ConnectionID string    `bson:"connection_id" json:"connection_id"`
 	Date         time.Time `bson:"date" json:"date"`
 	QuantitySold int       `bson:"quantity_sold" json:"quantity_sold"`
 	Revenue      float64   `bson:"revenue" json:"revenue"`
@@ -35,29 +162,45 @@ type LowStockAlert struct {
 	ID            string    `bson:"_id,omitempty" json:"id"`
 	ProductID     string    `bson:"product_id" json:"product_id"`
 	UserID        string    `bson:"user_id" json:"user_id"`
+	WarehouseID   string    `bson:"warehouse_id,omitempty" json:"warehouse_id,omitempty"` // Empty for the product's total stock
 	ThresholdDays int       `bson:"threshold_days" json:"threshold_days"`
 	NotifiedAt    time.Time `bson:"notified_at" json:"notified_at"`
 	CreatedAt     time.Time `bson:"created_at" json:"created_at"`
 }
 
 type ProductRepository interface {
-	Create(product *Product) error
-	Update(product *Product) error
-	UpdatePrice(id string, newPrice float64, competitorMinPrice float64) error
-	GetByID(id string) (*Product, error)
-	GetByUserID(userID string) ([]Product, error)
-	GetProductsForDumping(userID string) ([]Product, error)
-	GetLowStockProducts(userID string, thresholdDays int) ([]Product, error)
-	UpsertProduct(product *Product) error
+	Create(ctx context.Context, product *Product) error
+	Update(ctx context.Context, product *Product) error
+	UpdatePrice(ctx context.Context, id string, newPrice float64, competitorMinPrice float64) error
+	UpdateCityPrice(ctx context.Context, id, city string, newPrice float64, competitorMinPrice float64) error
+	UpsertCityPrice(ctx context.Context, connectionID, externalID, city string, price float64) error
+	SetCityPrice(ctx context.Context, id string, cityPrice CityPrice) error
+	GetByID(ctx context.Context, id string) (*Product, error)
+	GetByUserID(ctx context.Context, userID string) ([]Product, error)
+	GetByConnectionID(ctx context.Context, connectionID string) ([]Product, error)
+	GetProductsForDumping(ctx context.Context, connectionID string) ([]Product, error)
+	SetDumpingPause(ctx context.Context, id string, until time.Time, reason string) error
+	UpdatePriceLimits(ctx context.Context, id string, minPrice, maxPrice float64) error
+	UpdateStrategy(ctx context.Context, id string, strategy *PricingStrategyConfig) error
+	UpdateTags(ctx context.Context, id string, tags []string) error
+	UpdateCompetitorFilter(ctx context.Context, id string, filter *CompetitorFilter) error
+	UpdateCosts(ctx context.Context, id string, costs *ProductCosts) error
+	SetDumpingDryRun(ctx context.Context, id string, enabled bool) error
+	UpdateStock(ctx context.Context, id string, stocks []WarehouseStock, currentStock int) error
+	UpdateStockMetrics(ctx context.Context, id string, salesVelocity float64, daysOfStock int, stocks []WarehouseStock) error
+	GetLowStockProducts(ctx context.Context, userID string, thresholdDays int) ([]Product, error)
+	UpsertProduct(ctx context.Context, product *Product) error
+	DeleteByConnectionID(ctx context.Context, connectionID string) error
 }
 
 type SalesHistoryRepository interface {
-	Create(history *SalesHistory) error
-	GetByProductID(productID string, days int) ([]SalesHistory, error)
-	UpsertSalesHistory(history *SalesHistory) error
+	Create(ctx context.Context, history *SalesHistory) error
+	GetByProductID(ctx context.Context, productID string, days int) ([]SalesHistory, error)
+	UpsertSalesHistory(ctx context.Context, history *SalesHistory) error
+	DeleteByConnectionID(ctx context.Context, connectionID string) error
 }
 
 type LowStockAlertRepository interface {
-	Create(alert *LowStockAlert) error
-	GetRecentAlerts(userID string, hours int) ([]LowStockAlert, error)
+	Create(ctx context.Context, alert *LowStockAlert) error
+	GetRecentAlerts(ctx context.Context, userID string, hours int) ([]LowStockAlert, error)
 }
@@ -0,0 +1,73 @@
+package domain
+
+import "testing"
+
+func TestProductCostsFloor(t *testing.T) {
+	tests := []struct {
+		name  string
+		costs ProductCosts
+		want  float64
+	}{
+		{"cost and fee over the share left", ProductCosts{CostPrice: 1000, Fee: 200, CommissionPercent: 10, MinMarginPercent: 15}, 1600},
+		{"rounded up to a whole price", ProductCosts{CostPrice: 1000, CommissionPercent: 12}, 1137},
+		{"no commission or margin", ProductCosts{CostPrice: 1000, Fee: 150}, 1150},
+		{"commission and margin take the whole price", ProductCosts{CostPrice: 1000, CommissionPercent: 60, MinMarginPercent: 40}, 0},
+		{"nothing set", ProductCosts{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.costs.Floor(); got != tt.want {
+				t.Errorf("Floor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProductCostsMarginAt(t *testing.T) {
+	costs := ProductCosts{CostPrice: 1000, Fee: 200, CommissionPercent: 10, MinMarginPercent: 15}
+
+	tests := []struct {
+		name  string
+		price float64
+		want  Margin
+	}{
+		{"at the floor", 1600, Margin{Price: 1600, Commission: 160, Profit: 240, MarginPercent: 15}},
+		{"at a loss", 1000, Margin{Price: 1000, Commission: 100, Profit: -300, MarginPercent: -30}},
+		{"rounded to cents", 1999.99, Margin{Price: 1999.99, Commission: 200, Profit: 599.99, MarginPercent: 30}},
+		{"zero price", 0, Margin{Profit: -1200}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := costs.MarginAt(tt.price); got != tt.want {
+				t.Errorf("MarginAt(%v) = %+v, want %+v", tt.price, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFloorFor(t *testing.T) {
+	costs := &ProductCosts{CostPrice: 1000, Fee: 200, CommissionPercent: 10, MinMarginPercent: 15}
+
+	tests := []struct {
+		name    string
+		product Product
+		city    *CityPrice
+		want    float64
+	}{
+		{"min price", Product{MinPrice: 1500}, nil, 1500},
+		{"raised to the cost floor", Product{MinPrice: 1500, Costs: costs}, nil, 1600},
+		{"min price above the cost floor", Product{MinPrice: 1800, Costs: costs}, nil, 1800},
+		{"city min price", Product{MinPrice: 1500, Costs: costs}, &CityPrice{MinPrice: 1700}, 1700},
+		{"city without a min price", Product{MinPrice: 1500}, &CityPrice{City: "Almaty"}, 1500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.product.FloorFor(tt.city); got != tt.want {
+				t.Errorf("FloorFor = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,12 +1,16 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type Review struct {
 	ID             string    `bson:"_id,omitempty" json:"id"`
 	UserID         string    `bson:"user_id" json:"user_id"`
+	ConnectionID   string    `bson:"connection_id" json:"connection_id"`               // Marketplace connection the review was synced from
 	ProductID      string    `bson:"product_id,omitempty" json:"product_id,omitempty"` // Reference to Product._id
-	ExternalID     string    `bson:"external_id" json:"external_id"`                   // Kaspi review ID
+	ExternalID     string    `bson:"external_id" json:"external_id"`                   // Review ID on the marketplace
 	AuthorName     string    `bson:"author_name" json:"author_name"`
 	Rating         int       `bson:"rating" json:"rating"`
 	Comment        string    `bson:"comment" json:"comment"`
@@ -18,10 +22,11 @@ type Review struct {
 }
 
 type ReviewRepository interface {
-	Create(review *Review) error
-	Update(review *Review) error
-	GetByID(id string) (*Review, error)
-	GetPendingReviews(userID string) ([]Review, error)
-	GetByUserID(userID string, limit int) ([]Review, error)
-	UpsertReview(review *Review) error
+	Create(ctx context.Context, review *Review) error
+	Update(ctx context.Context, review *Review) error
+	GetByID(ctx context.Context, id string) (*Review, error)
+	GetPendingReviews(ctx context.Context, userID string) ([]Review, error)
+	GetByUserID(ctx context.Context, userID string, limit int) ([]Review, error)
+	UpsertReview(ctx context.Context, review *Review) error
+	DeleteByConnectionID(ctx context.Context, connectionID string) error
 }
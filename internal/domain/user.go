@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type User struct {
 	ID                 string    `bson:"_id,omitempty" json:"id"`
@@ -11,15 +14,17 @@ type User struct {
 	LanguageCode       string    `bson:"language_code" json:"language_code"`
 	AutoReplyEnabled   bool      `bson:"auto_reply_enabled" json:"auto_reply_enabled"`
 	AutoDumpingEnabled bool      `bson:"auto_dumping_enabled" json:"auto_dumping_enabled"` // Глобальный переключатель автодемпинга
+	DumpingDryRun      bool      `bson:"dumping_dry_run" json:"dumping_dry_run"`           // Автодемпинг только рассчитывает цены, не меняя их
 	CreatedAt          time.Time `bson:"created_at" json:"created_at"`
 	UpdatedAt          time.Time `bson:"updated_at" json:"updated_at"`
 }
 
 type UserRepository interface {
-	Create(user *User) error
-	GetByEmail(email string) (*User, error)
-	GetByID(id string) (*User, error)
-	Update(user *User) error
-	ToggleAutoReply(userID string, enabled bool) error
-	ToggleAutoDumping(userID string, enabled bool) error
+	Create(ctx context.Context, user *User) error
+	GetByEmail(ctx context.Context, email string) (*User, error)
+	GetByID(ctx context.Context, id string) (*User, error)
+	Update(ctx context.Context, user *User) error
+	ToggleAutoReply(ctx context.Context, userID string, enabled bool) error
+	ToggleAutoDumping(ctx context.Context, userID string, enabled bool) error
+	ToggleDumpingDryRun(ctx context.Context, userID string, enabled bool) error
 }
@@ -0,0 +1,59 @@
+package marketplace
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors shared by all marketplace clients. Check them with errors.Is.
+var (
+	ErrUnauthorized = errors.New("marketplace: unauthorized")         // token missing, expired or revoked
+	ErrForbidden    = errors.New("marketplace: forbidden")            // token valid but not allowed for this merchant/resource
+	ErrNotFound     = errors.New("marketplace: not found")            // product, review or order does not exist
+	ErrConflict     = errors.New("marketplace: conflict")             // the resource is not in a state that allows the action
+	ErrRateLimited  = errors.New("marketplace: rate limited")         // 429 after all retries
+	ErrUnavailable  = errors.New("marketplace: upstream unavailable") // 5xx or network failure after all retries
+	ErrDecode       = errors.New("marketplace: decode failure")       // response body could not be parsed
+	ErrNotSupported = errors.New("marketplace: not supported")        // the marketplace API has no equivalent operation
+)
+
+// APIError is a non-2xx response from a marketplace API.
+// It unwraps to the matching sentinel error, so errors.Is(err, ErrUnauthorized) works.
+type APIError struct {
+	Marketplace string
+	StatusCode  int
+	Body        string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s API error (status %d): %s", e.Marketplace, e.StatusCode, e.Body)
+}
+
+func (e *APIError) Unwrap() error {
+	return ErrorForStatus(e.StatusCode)
+}
+
+// ErrorForStatus maps an HTTP status code to a sentinel error (nil if none applies)
+func ErrorForStatus(status int) error {
+	switch {
+	case status == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case status == http.StatusForbidden:
+		return ErrForbidden
+	case status == http.StatusNotFound:
+		return ErrNotFound
+	case status == http.StatusConflict:
+		return ErrConflict
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status >= 500:
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// IsAuthError reports whether err means the stored credentials are no longer valid
+func IsAuthError(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
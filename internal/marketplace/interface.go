@@ -1,23 +1,78 @@
 package marketplace
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // MarketplaceClient defines the interface for marketplace API integrations
 type MarketplaceClient interface {
 	// GetProducts fetches all products from the marketplace
-	GetProducts() ([]ProductData, error)
+	GetProducts(ctx context.Context) ([]ProductData, error)
 
 	// GetProductStock fetches current stock for a specific product
-	GetProductStock(externalID string) (int, error)
+	GetProductStock(ctx context.Context, externalID string) (int, error)
 
 	// GetSalesData fetches sales data for a date range
-	GetSalesData(startDate, endDate time.Time) ([]SalesData, error)
+	GetSalesData(ctx context.Context, startDate, endDate time.Time) ([]SalesData, error)
 
 	// GetReviews fetches new reviews
-	GetReviews() ([]ReviewData, error)
+	GetReviews(ctx context.Context) ([]ReviewData, error)
 
 	// PostReviewResponse posts a response to a review
-	PostReviewResponse(reviewID, response string) error
+	PostReviewResponse(ctx context.Context, reviewID, response string) error
+
+	// ForEachProductPage streams the product catalog to fn one page at a time
+	ForEachProductPage(ctx context.Context, fn func([]ProductData) error) error
+
+	// ForEachSalesPage streams sales for a date range to fn one page at a time
+	ForEachSalesPage(ctx context.Context, startDate, endDate time.Time, fn func([]SalesData) error) error
+
+	// ForEachReviewPage streams reviews to fn one page at a time
+	ForEachReviewPage(ctx context.Context, fn func([]ReviewData) error) error
+
+	// GetCompetitorPrices fetches other sellers' offers for a product (our own offer excluded)
+	// in one city, or in all cities if city is empty
+	GetCompetitorPrices(ctx context.Context, externalID, city string) ([]CompetitorOffer, error)
+
+	// UpdateProductPrice sets our selling price for a product
+	UpdateProductPrice(ctx context.Context, externalID string, newPrice float64) error
+
+	// UpdateCityPrice sets our selling price for a product in one city only
+	UpdateCityPrice(ctx context.Context, externalID, city string, newPrice float64) error
+
+	// UpdateStock sets the available quantity of a product at one of our warehouses
+	// (a Kaspi pickup point, an Ozon FBS warehouse)
+	UpdateStock(ctx context.Context, externalID, warehouseID string, quantity int) error
+
+	// GetOrders fetches orders created or changed since the given time
+	GetOrders(ctx context.Context, since time.Time) ([]OrderData, error)
+
+	// ForEachOrderPage streams orders created or changed since the given time to fn one page at a time
+	ForEachOrderPage(ctx context.Context, since time.Time, fn func([]OrderData) error) error
+
+	// AcceptOrder confirms a new order
+	AcceptOrder(ctx context.Context, externalID string) error
+
+	// MarkOrderReady reports an accepted order as ready for pickup or handed over for shipping
+	MarkOrderReady(ctx context.Context, externalID string) error
+
+	// CancelOrder cancels an order that has not been delivered; reason is one of the CancelReason constants
+	CancelOrder(ctx context.Context, externalID, reason string) error
+}
+
+// StatsReporter is implemented by clients that count their API calls
+type StatsReporter interface {
+	Stats() ClientStats
+}
+
+// ClientStats are cumulative API call counters of a client
+type ClientStats struct {
+	Calls     int64
+	Attempts  int64
+	Retries   int64
+	Throttles int64
+	Failures  int64
 }
 
 // ProductData represents product information from marketplace
@@ -25,11 +80,28 @@ type ProductData struct {
 	ExternalID   string
 	SKU          string
 	Name         string
-	CurrentStock int
+	CurrentStock int              // total across all warehouses
+	Stocks       []WarehouseStock // per warehouse, if the marketplace reports it
 	Price        float64
+	CityPrices   []CityPrice // prices that differ from Price in some cities, if the marketplace has them
 	Currency     string
 }
 
+// CityPrice is our selling price of a product in one city
+type CityPrice struct {
+	City  string
+	Price float64
+}
+
+// WarehouseStock is the available quantity of a product at one warehouse
+// (a Kaspi pickup point, an Ozon FBS warehouse or Ozon's own, a Wildberries warehouse)
+type WarehouseStock struct {
+	WarehouseID string
+	Name        string
+	City        string
+	Quantity    int
+}
+
 // SalesData represents sales information from marketplace
 type SalesData struct {
 	ProductExternalID string
@@ -48,3 +120,80 @@ type ReviewData struct {
 	Language   string
 	CreatedAt  time.Time
 }
+
+// Order statuses shared by all marketplaces; adapters map their own states onto these
+const (
+	OrderStatusNew       = "new"
+	OrderStatusAccepted  = "accepted"
+	OrderStatusReady     = "ready" // ready for pickup or handed over for shipping
+	OrderStatusDelivered = "delivered"
+	OrderStatusCancelled = "cancelled"
+	OrderStatusReturned  = "returned"
+)
+
+// Cancellation reasons accepted by CancelOrder; adapters map them onto their own codes
+const (
+	CancelReasonOutOfStock          = "out_of_stock"
+	CancelReasonCustomerRequest     = "customer_request"
+	CancelReasonCustomerUnreachable = "customer_unreachable"
+	CancelReasonWrongPrice          = "wrong_price"
+	CancelReasonOther               = "other"
+)
+
+// IsCancelReason reports whether reason is one of the CancelReason constants
+func IsCancelReason(reason string) bool {
+	switch reason {
+	case CancelReasonOutOfStock, CancelReasonCustomerRequest, CancelReasonCustomerUnreachable,
+		CancelReasonWrongPrice, CancelReasonOther:
+		return true
+	}
+	return false
+}
+
+// OrderData represents an order from marketplace
+type OrderData struct {
+	ExternalID   string
+	Status       string // one of the OrderStatus constants
+	CustomerCity string
+	DeliveryType string // pickup, delivery, express, or the marketplace's own scheme (fbo, fbs)
+	WarehouseID  string // warehouse the order is fulfilled from, if known; matches WarehouseStock.WarehouseID
+	TotalPrice   float64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Lines        []OrderLineData
+}
+
+// OrderLineData represents one product in an order
+type OrderLineData struct {
+	ProductExternalID string
+	Name              string
+	Quantity          int
+	Price             float64 // unit price
+}
+
+// CompetitorOffer represents another seller's offer for the same product
+type CompetitorOffer struct {
+	SellerID     string  `json:"seller_id"`
+	SellerName   string  `json:"seller_name"`
+	Price        float64 `json:"price"`
+	DeliveryType string  `json:"delivery_type"` // pickup, delivery, express
+	DeliveryDays int     `json:"delivery_days"`
+	City         string  `json:"city"`
+	SellerRating float64 `json:"seller_rating"` // 0 when the marketplace does not report it
+}
+
+// MinOfferPrice returns the lowest price among offers (0 if there are none)
+func MinOfferPrice(offers []CompetitorOffer) float64 {
+	if len(offers) == 0 {
+		return 0
+	}
+
+	minPrice := offers[0].Price
+	for _, o := range offers {
+		if o.Price < minPrice {
+			minPrice = o.Price
+		}
+	}
+
+	return minPrice
+}
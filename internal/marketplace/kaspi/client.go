@@ -2,81 +2,165 @@ package kaspi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/yourusername/seller-assistant/internal/marketplace"
 )
 
 const (
-	kaspiAPIBaseURL = "https://kaspi.kz/merchantcabinet/api/v1"
+	// DefaultBaseURL is the production Kaspi merchant API endpoint
+	DefaultBaseURL = "https://kaspi.kz/merchantcabinet/api/v1"
 )
 
 // Client implements marketplace.MarketplaceClient for Kaspi
 type Client struct {
 	apiKey     string
 	merchantID string
+	baseURL    string
 	httpClient *http.Client
+	pageSize   int
+	maxPages   int
+
+	retry     RetryPolicy
+	rateLimit float64
+	rateBurst int
+	limiter   *tokenBucket
+	observer  func(CallStats)
+	stats     statsCounters
 }
 
-func NewClient(apiKey, merchantID string) *Client {
-	return &Client{
+var _ marketplace.MarketplaceClient = (*Client)(nil)
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithBaseURL overrides the Kaspi API base URL (e.g. to point at a local fake server)
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		if baseURL != "" {
+			c.baseURL = strings.TrimRight(baseURL, "/")
+		}
+	}
+}
+
+// WithHTTPClient replaces the default HTTP client (30s timeout)
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+func NewClient(apiKey, merchantID string, opts ...Option) *Client {
+	c := &Client{
 		apiKey:     apiKey,
 		merchantID: merchantID,
+		baseURL:    DefaultBaseURL,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		pageSize:  DefaultPageSize,
+		maxPages:  DefaultMaxPages,
+		retry:     DefaultRetryPolicy,
+		rateLimit: DefaultRateLimit,
+		rateBurst: DefaultRateBurst,
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
+
+	if c.rateLimit > 0 {
+		c.limiter = limiterFor(merchantID, c.rateLimit, c.rateBurst)
+	}
+
+	return c
 }
 
-func (c *Client) GetProducts() ([]marketplace.ProductData, error) {
-	// Note: This is a mock implementation. Replace with actual Kaspi API endpoints
-	// when the real API documentation is available
-	url := fmt.Sprintf("%s/merchants/%s/products", kaspiAPIBaseURL, c.merchantID)
+func (c *Client) GetProducts(ctx context.Context) ([]marketplace.ProductData, error) {
+	var products []marketplace.ProductData
+	err := c.ForEachProductPage(ctx, func(page []marketplace.ProductData) error {
+		products = append(products, page...)
+		return nil
+	})
+	return products, err
+}
 
-	resp, err := c.makeRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
+// ForEachProductPage streams the product catalog to fn one page at a time
+func (c *Client) ForEachProductPage(ctx context.Context, fn func([]marketplace.ProductData) error) error {
+	type product struct {
+		ID         string        `json:"id"`
+		SKU        string        `json:"sku"`
+		Name       string        `json:"name"`
+		Stock      int           `json:"stock"`
+		Stocks     []pointStocks `json:"stocks"`
+		Price      float64       `json:"price"`
+		CityPrices []struct {
+			City  string  `json:"city"`
+			Price float64 `json:"price"`
+		} `json:"city_prices"`
+		Currency string `json:"currency"`
 	}
-	defer resp.Body.Close()
 
-	var response struct {
-		Data []struct {
-			ID       string  `json:"id"`
-			SKU      string  `json:"sku"`
-			Name     string  `json:"name"`
-			Stock    int     `json:"stock"`
-			Price    float64 `json:"price"`
-			Currency string  `json:"currency"`
-		} `json:"data"`
+	path := fmt.Sprintf("/merchants/%s/products", c.merchantID)
+
+	return fetchPages(ctx, c, path, nil, func(page []product) error {
+		products := make([]marketplace.ProductData, 0, len(page))
+		for _, p := range page {
+			data := marketplace.ProductData{
+				ExternalID:   p.ID,
+				SKU:          p.SKU,
+				Name:         p.Name,
+				CurrentStock: p.Stock,
+				Stocks:       toWarehouseStocks(p.Stocks),
+				Price:        p.Price,
+				Currency:     p.Currency,
+			}
+			for _, cp := range p.CityPrices {
+				data.CityPrices = append(data.CityPrices, marketplace.CityPrice{City: cp.City, Price: cp.Price})
+			}
+			products = append(products, data)
+		}
+		return fn(products)
+	})
+}
+
+// pointStocks is a product's stock at one pickup point
+type pointStocks struct {
+	WarehouseID string `json:"warehouse_id"`
+	City        string `json:"city"`
+	Stock       int    `json:"stock"`
+}
+
+func toWarehouseStocks(points []pointStocks) []marketplace.WarehouseStock {
+	if len(points) == 0 {
+		return nil
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	products := make([]marketplace.ProductData, 0, len(response.Data))
-	for _, p := range response.Data {
-		products = append(products, marketplace.ProductData{
-			ExternalID:   p.ID,
-			SKU:          p.SKU,
-			Name:         p.Name,
-			CurrentStock: p.Stock,
-			Price:        p.Price,
-			Currency:     p.Currency,
+	stocks := make([]marketplace.WarehouseStock, 0, len(points))
+	for _, p := range points {
+		stocks = append(stocks, marketplace.WarehouseStock{
+			WarehouseID: p.WarehouseID,
+			Name:        p.WarehouseID,
+			City:        p.City,
+			Quantity:    p.Stock,
 		})
 	}
-
-	return products, nil
+	return stocks
 }
 
-func (c *Client) GetProductStock(externalID string) (int, error) {
-	url := fmt.Sprintf("%s/merchants/%s/products/%s/stock", kaspiAPIBaseURL, c.merchantID, externalID)
+func (c *Client) GetProductStock(ctx context.Context, externalID string) (int, error) {
+	url := fmt.Sprintf("%s/merchants/%s/products/%s/stock", c.baseURL, c.merchantID, externalID)
 
-	resp, err := c.makeRequest("GET", url, nil)
+	resp, err := c.makeRequest(ctx, "GET", url, nil)
 	if err != nil {
 		return 0, err
 	}
@@ -87,95 +171,91 @@ func (c *Client) GetProductStock(externalID string) (int, error) {
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return 0, fmt.Errorf("failed to decode response: %w", err)
+		return 0, decodeError(err)
 	}
 
 	return response.Stock, nil
 }
 
-func (c *Client) GetSalesData(startDate, endDate time.Time) ([]marketplace.SalesData, error) {
-	url := fmt.Sprintf("%s/merchants/%s/sales?start_date=%s&end_date=%s",
-		kaspiAPIBaseURL,
-		c.merchantID,
-		startDate.Format("2006-01-02"),
-		endDate.Format("2006-01-02"),
-	)
-
-	resp, err := c.makeRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+func (c *Client) GetSalesData(ctx context.Context, startDate, endDate time.Time) ([]marketplace.SalesData, error) {
+	var salesData []marketplace.SalesData
+	err := c.ForEachSalesPage(ctx, startDate, endDate, func(page []marketplace.SalesData) error {
+		salesData = append(salesData, page...)
+		return nil
+	})
+	return salesData, err
+}
 
-	var response struct {
-		Data []struct {
-			ProductID    string    `json:"product_id"`
-			Date         time.Time `json:"date"`
-			QuantitySold int       `json:"quantity_sold"`
-			Revenue      float64   `json:"revenue"`
-		} `json:"data"`
+// ForEachSalesPage streams sales rows for a date range to fn one page at a time
+func (c *Client) ForEachSalesPage(ctx context.Context, startDate, endDate time.Time, fn func([]marketplace.SalesData) error) error {
+	type sale struct {
+		ProductID    string    `json:"product_id"`
+		Date         time.Time `json:"date"`
+		QuantitySold int       `json:"quantity_sold"`
+		Revenue      float64   `json:"revenue"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	salesData := make([]marketplace.SalesData, 0, len(response.Data))
-	for _, s := range response.Data {
-		salesData = append(salesData, marketplace.SalesData{
-			ProductExternalID: s.ProductID,
-			Date:              s.Date,
-			QuantitySold:      s.QuantitySold,
-			Revenue:           s.Revenue,
-		})
-	}
-
-	return salesData, nil
+	path := fmt.Sprintf("/merchants/%s/sales", c.merchantID)
+	query := url.Values{}
+	query.Set("start_date", startDate.Format("2006-01-02"))
+	query.Set("end_date", endDate.Format("2006-01-02"))
+
+	return fetchPages(ctx, c, path, query, func(page []sale) error {
+		salesData := make([]marketplace.SalesData, 0, len(page))
+		for _, s := range page {
+			salesData = append(salesData, marketplace.SalesData{
+				ProductExternalID: s.ProductID,
+				Date:              s.Date,
+				QuantitySold:      s.QuantitySold,
+				Revenue:           s.Revenue,
+			})
+		}
+		return fn(salesData)
+	})
 }
 
-func (c *Client) GetReviews() ([]marketplace.ReviewData, error) {
-	url := fmt.Sprintf("%s/merchants/%s/reviews", kaspiAPIBaseURL, c.merchantID)
-
-	resp, err := c.makeRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	var response struct {
-		Data []struct {
-			ID         string    `json:"id"`
-			ProductID  string    `json:"product_id"`
-			AuthorName string    `json:"author_name"`
-			Rating     int       `json:"rating"`
-			Comment    string    `json:"comment"`
-			Language   string    `json:"language"`
-			CreatedAt  time.Time `json:"created_at"`
-		} `json:"data"`
-	}
+func (c *Client) GetReviews(ctx context.Context) ([]marketplace.ReviewData, error) {
+	var reviews []marketplace.ReviewData
+	err := c.ForEachReviewPage(ctx, func(page []marketplace.ReviewData) error {
+		reviews = append(reviews, page...)
+		return nil
+	})
+	return reviews, err
+}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	reviews := make([]marketplace.ReviewData, 0, len(response.Data))
-	for _, r := range response.Data {
-		reviews = append(reviews, marketplace.ReviewData{
-			ExternalID: r.ID,
-			ProductID:  r.ProductID,
-			AuthorName: r.AuthorName,
-			Rating:     r.Rating,
-			Comment:    r.Comment,
-			Language:   r.Language,
-			CreatedAt:  r.CreatedAt,
-		})
+// ForEachReviewPage streams reviews to fn one page at a time
+func (c *Client) ForEachReviewPage(ctx context.Context, fn func([]marketplace.ReviewData) error) error {
+	type review struct {
+		ID         string    `json:"id"`
+		ProductID  string    `json:"product_id"`
+		AuthorName string    `json:"author_name"`
+		Rating     int       `json:"rating"`
+		Comment    string    `json:"comment"`
+		Language   string    `json:"language"`
+		CreatedAt  time.Time `json:"created_at"`
 	}
 
-	return reviews, nil
+	path := fmt.Sprintf("/merchants/%s/reviews", c.merchantID)
+
+	return fetchPages(ctx, c, path, nil, func(page []review) error {
+		reviews := make([]marketplace.ReviewData, 0, len(page))
+		for _, r := range page {
+			reviews = append(reviews, marketplace.ReviewData{
+				ExternalID: r.ID,
+				ProductID:  r.ProductID,
+				AuthorName: r.AuthorName,
+				Rating:     r.Rating,
+				Comment:    r.Comment,
+				Language:   r.Language,
+				CreatedAt:  r.CreatedAt,
+			})
+		}
+		return fn(reviews)
+	})
 }
 
-func (c *Client) PostReviewResponse(reviewID, response string) error {
-	url := fmt.Sprintf("%s/merchants/%s/reviews/%s/response", kaspiAPIBaseURL, c.merchantID, reviewID)
+func (c *Client) PostReviewResponse(ctx context.Context, reviewID, response string) error {
+	url := fmt.Sprintf("%s/merchants/%s/reviews/%s/response", c.baseURL, c.merchantID, reviewID)
 
 	payload := map[string]string{
 		"response": response,
@@ -186,7 +266,7 @@ func (c *Client) PostReviewResponse(reviewID, response string) error {
 		return err
 	}
 
-	resp, err := c.makeRequest("POST", url, bytes.NewReader(payloadBytes))
+	resp, err := c.makeRequest(ctx, "POST", url, payloadBytes)
 	if err != nil {
 		return err
 	}
@@ -200,25 +280,64 @@ func (c *Client) PostReviewResponse(reviewID, response string) error {
 	return nil
 }
 
-func (c *Client) makeRequest(method, url string, body io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest(method, url, body)
-	if err != nil {
-		return nil, err
+// makeRequest sends a request through the rate limiter and retries it according
+// to the retry policy. Non-2xx responses are returned as *APIError.
+func (c *Client) makeRequest(ctx context.Context, method, url string, body []byte) (*http.Response, error) {
+	call := CallStats{Method: method}
+	start := time.Now()
+
+	resp, err := c.doWithRetry(ctx, method, url, body, &call)
+
+	call.Duration = time.Since(start)
+	call.Err = err
+	c.stats.record(call)
+	if c.observer != nil {
+		c.observer(call)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	req.Header.Set("Content-Type", "application/json")
+	return resp, err
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+func (c *Client) doWithRetry(ctx context.Context, method, url string, body []byte, call *CallStats) (*http.Response, error) {
+	retry := marketplace.RetryRequest{
+		Marketplace: marketplaceName,
+		Policy:      c.retry,
+		Idempotent:  marketplace.IsIdempotent(method),
+		NewRequest: func(ctx context.Context) (*http.Request, error) {
+			var bodyReader io.Reader
+			if body != nil {
+				bodyReader = bytes.NewReader(body)
+			}
+
+			req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+			if err != nil {
+				return nil, err
+			}
+			call.Path = req.URL.Path
+
+			req.Header.Set("Authorization", "Bearer "+c.apiKey)
+			req.Header.Set("Content-Type", "application/json")
+			return req, nil
+		},
+		AfterAttempt: func(status int) {
+			call.Attempts++
+			call.StatusCode = status
+			if status == http.StatusTooManyRequests {
+				call.Throttles++
+			}
+		},
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	if c.limiter != nil {
+		retry.BeforeAttempt = func(ctx context.Context) error {
+			waited, err := c.limiter.Wait(ctx)
+			call.LimiterWait += waited
+			if err != nil {
+				return fmt.Errorf("request failed: %w", err)
+			}
+			return nil
+		}
 	}
 
-	return resp, nil
+	return marketplace.DoWithRetry(ctx, c.httpClient, retry)
 }
@@ -0,0 +1,228 @@
+package kaspi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/internal/marketplace/kaspi"
+	"github.com/yourusername/seller-assistant/internal/marketplace/kaspi/kaspitest"
+)
+
+// newTestServer starts a fake Kaspi server for the test. Its clients skip the rate limiter
+// and make a single attempt unless opts say otherwise.
+func newTestServer(t *testing.T, fixtures *kaspitest.Fixtures, opts ...kaspi.Option) (*kaspitest.Server, *kaspi.Client) {
+	t.Helper()
+
+	srv := kaspitest.NewServer(fixtures)
+	t.Cleanup(srv.Close)
+
+	opts = append([]kaspi.Option{
+		kaspi.WithRateLimit(0, 0),
+		kaspi.WithRetryPolicy(kaspi.RetryPolicy{MaxAttempts: 1}),
+	}, opts...)
+
+	return srv, srv.NewClient(opts...)
+}
+
+func TestGetCompetitorPrices(t *testing.T) {
+	_, client := newTestServer(t, nil)
+
+	offers, err := client.GetCompetitorPrices(context.Background(), "100001", "")
+	if err != nil {
+		t.Fatalf("GetCompetitorPrices: %v", err)
+	}
+
+	// Our own offers (demo-merchant) are left out
+	if len(offers) != 3 {
+		t.Fatalf("got %d offers, want 3: %+v", len(offers), offers)
+	}
+	for _, o := range offers {
+		if o.SellerID == "demo-merchant" {
+			t.Errorf("own offer returned: %+v", o)
+		}
+	}
+
+	want := kaspi.CompetitorPrice{
+		SellerID:     "m-technoshop",
+		SellerName:   "TechnoShop KZ",
+		Price:        425000,
+		DeliveryType: "delivery",
+		DeliveryDays: 2,
+		City:         "Almaty",
+		SellerRating: 4.8,
+	}
+	if offers[0] != want {
+		t.Errorf("first offer = %+v, want %+v", offers[0], want)
+	}
+
+	if got := kaspi.GetMinCompetitorPrice(offers); got != 425000 {
+		t.Errorf("min competitor price = %v, want 425000", got)
+	}
+}
+
+func TestGetCompetitorPricesInCity(t *testing.T) {
+	_, client := newTestServer(t, nil)
+
+	offers, err := client.GetCompetitorPrices(context.Background(), "100001", "Astana")
+	if err != nil {
+		t.Fatalf("GetCompetitorPrices: %v", err)
+	}
+
+	if len(offers) != 1 || offers[0].SellerID != "m-astanatech" || offers[0].Price != 436000 {
+		t.Errorf("offers in Astana = %+v, want only Astana Tech at 436000", offers)
+	}
+}
+
+func TestGetCompetitorPricesSkipsInvalidPrices(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+	srv.SetOffers("100003", []kaspitest.Offer{
+		{MerchantID: "m-free", MerchantName: "Free", Price: 0, City: "Almaty"},
+		{MerchantID: "m-cheap", MerchantName: "Cheap", Price: 88990, City: "Almaty"},
+	})
+
+	offers, err := client.GetCompetitorPrices(context.Background(), "100003", "")
+	if err != nil {
+		t.Fatalf("GetCompetitorPrices: %v", err)
+	}
+
+	if len(offers) != 1 || offers[0].SellerID != "m-cheap" {
+		t.Errorf("offers = %+v, want only m-cheap", offers)
+	}
+}
+
+func TestUpdatePrice(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+	ctx := context.Background()
+
+	if err := client.UpdateProductPrice(ctx, "100002", 379990); err != nil {
+		t.Fatalf("UpdateProductPrice: %v", err)
+	}
+	if err := client.UpdateCityPrice(ctx, "100001", "Astana", 435990); err != nil {
+		t.Fatalf("UpdateCityPrice: %v", err)
+	}
+
+	updates := srv.PriceUpdates()
+	if len(updates) != 2 {
+		t.Fatalf("got %d price updates, want 2", len(updates))
+	}
+	if u := updates[0]; u.ProductID != "100002" || u.City != "" || u.Price != 379990 || u.Currency != "KZT" {
+		t.Errorf("product price update = %+v", u)
+	}
+	if u := updates[1]; u.ProductID != "100001" || u.City != "Astana" || u.Price != 435990 {
+		t.Errorf("city price update = %+v", u)
+	}
+}
+
+func TestUpdatePriceRejectsInvalidPrice(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+	ctx := context.Background()
+
+	if err := client.UpdateProductPrice(ctx, "100002", 0); !errors.Is(err, kaspi.ErrInvalidPrice) {
+		t.Errorf("zero price: err = %v, want ErrInvalidPrice", err)
+	}
+	if err := client.UpdateCityPrice(ctx, "100002", "", 379990); !errors.Is(err, kaspi.ErrInvalidPrice) {
+		t.Errorf("no city: err = %v, want ErrInvalidPrice", err)
+	}
+	if n := srv.Requests(); n != 0 {
+		t.Errorf("invalid prices sent %d requests, want 0", n)
+	}
+}
+
+func TestNotFoundErrors(t *testing.T) {
+	_, client := newTestServer(t, nil)
+	ctx := context.Background()
+
+	_, err := client.GetCompetitorPrices(ctx, "999999", "")
+	if !errors.Is(err, marketplace.ErrNotFound) || !errors.Is(err, kaspi.ErrProductNotFound) {
+		t.Errorf("GetCompetitorPrices: err = %v, want ErrNotFound and ErrProductNotFound", err)
+	}
+
+	err = client.UpdateProductPrice(ctx, "999999", 1000)
+	if !errors.Is(err, marketplace.ErrNotFound) || !errors.Is(err, kaspi.ErrProductNotFound) {
+		t.Errorf("UpdateProductPrice: err = %v, want ErrNotFound and ErrProductNotFound", err)
+	}
+
+	var apiErr *kaspi.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("UpdateProductPrice: err = %v, want an APIError with status 404", err)
+	}
+}
+
+func TestUnavailableError(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+	srv.FailNext(1, http.StatusServiceUnavailable, 0)
+
+	_, err := client.GetCompetitorPrices(context.Background(), "100001", "")
+	if !errors.Is(err, marketplace.ErrUnavailable) {
+		t.Errorf("err = %v, want ErrUnavailable", err)
+	}
+}
+
+func TestUnavailableOnNetworkError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	client := kaspi.NewClient("key", "merchant", kaspi.WithBaseURL(srv.URL),
+		kaspi.WithRateLimit(0, 0), kaspi.WithRetryPolicy(kaspi.RetryPolicy{MaxAttempts: 1}))
+
+	_, err := client.GetCompetitorPrices(context.Background(), "100001", "")
+	if !errors.Is(err, marketplace.ErrUnavailable) {
+		t.Errorf("err = %v, want ErrUnavailable", err)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": [{"price": "not a number"`))
+	}))
+	defer srv.Close()
+
+	client := kaspi.NewClient("key", "merchant", kaspi.WithBaseURL(srv.URL), kaspi.WithRateLimit(0, 0))
+
+	_, err := client.GetCompetitorPrices(context.Background(), "100001", "")
+	if !errors.Is(err, marketplace.ErrDecode) {
+		t.Errorf("GetCompetitorPrices: err = %v, want ErrDecode", err)
+	}
+
+	_, err = client.GetProducts(context.Background())
+	if !errors.Is(err, marketplace.ErrDecode) {
+		t.Errorf("GetProducts: err = %v, want ErrDecode", err)
+	}
+}
+
+func TestAuthErrors(t *testing.T) {
+	fixtures := kaspitest.DefaultFixtures()
+	fixtures.APIKey = "secret"
+	srv := kaspitest.NewServer(fixtures)
+	defer srv.Close()
+
+	opts := []kaspi.Option{kaspi.WithBaseURL(srv.URL), kaspi.WithRateLimit(0, 0)}
+	ctx := context.Background()
+
+	_, err := kaspi.NewClient("wrong-key", srv.MerchantID(), opts...).GetCompetitorPrices(ctx, "100001", "")
+	if !errors.Is(err, marketplace.ErrUnauthorized) || !marketplace.IsAuthError(err) {
+		t.Errorf("wrong key: err = %v, want an auth error", err)
+	}
+
+	_, err = kaspi.NewClient("secret", "other-merchant", opts...).GetCompetitorPrices(ctx, "100001", "")
+	if !errors.Is(err, marketplace.ErrForbidden) {
+		t.Errorf("other merchant: err = %v, want ErrForbidden", err)
+	}
+	if marketplace.IsAuthError(err) {
+		t.Errorf("other merchant: %v must not count as an auth error", err)
+	}
+
+	// Auth errors are final, so they are not retried
+	if n := srv.Requests(); n != 2 {
+		t.Errorf("server got %d requests, want 2", n)
+	}
+
+	if _, err := kaspi.NewClient("secret", srv.MerchantID(), opts...).GetCompetitorPrices(ctx, "100001", ""); err != nil {
+		t.Errorf("right key: %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package kaspi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+var (
+	// ErrProductNotFound is returned when Kaspi does not know the requested product.
+	// Errors wrapping it also match marketplace.ErrNotFound.
+	ErrProductNotFound = errors.New("kaspi: product not found")
+
+	// ErrInvalidPrice is returned when a price update is attempted with a non-positive price
+	ErrInvalidPrice = errors.New("kaspi: invalid price")
+
+	// ErrInvalidStock is returned when a stock update has a negative quantity or no pickup point
+	ErrInvalidStock = errors.New("kaspi: invalid stock")
+
+	// ErrOrderNotFound is returned when Kaspi does not know the requested order.
+	// Errors wrapping it also match marketplace.ErrNotFound.
+	ErrOrderNotFound = errors.New("kaspi: order not found")
+
+	// ErrInvalidCancelReason is returned when CancelOrder gets a reason Kaspi has no code for
+	ErrInvalidCancelReason = errors.New("kaspi: invalid cancel reason")
+)
+
+// APIError is returned for any non-2xx response from the Kaspi API
+type APIError = marketplace.APIError
+
+const marketplaceName = "kaspi"
+
+func decodeError(err error) error {
+	return fmt.Errorf("failed to decode response: %w: %v", marketplace.ErrDecode, err)
+}
@@ -0,0 +1,12 @@
+package kaspi
+
+import (
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+// NewFactory returns a marketplace.Factory that builds Kaspi clients with the given options
+func NewFactory(opts ...Option) marketplace.Factory {
+	return func(creds marketplace.Credentials) (marketplace.MarketplaceClient, error) {
+		return NewClient(creds.APIKey, creds.MerchantID, opts...), nil
+	}
+}
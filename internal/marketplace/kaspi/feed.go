@@ -0,0 +1,233 @@
+package kaspi
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Пространство имён и схема XML прайс-листа Kaspi
+const (
+	FeedNamespace      = "kaspiShopping"
+	FeedSchemaLocation = "kaspiShopping http://kaspi.kz/kaspishopping.xsd"
+
+	// DefaultPickupPoint — склад по умолчанию, если у подключения не задано ни одного
+	DefaultPickupPoint = "PP1"
+)
+
+// ErrInvalidFeed возвращается, когда прайс-лист не соответствует схеме Kaspi
+var ErrInvalidFeed = errors.New("kaspi: invalid price feed")
+
+// FeedCatalog — корневой элемент kaspi_catalog
+type FeedCatalog struct {
+	XMLName        xml.Name    `xml:"kaspi_catalog"`
+	Xmlns          string      `xml:"xmlns,attr"`
+	XmlnsXSI       string      `xml:"xmlns:xsi,attr"`
+	SchemaLocation string      `xml:"xsi:schemaLocation,attr"`
+	Date           string      `xml:"date,attr"`
+	Company        string      `xml:"company"`
+	MerchantID     string      `xml:"merchantid"`
+	Offers         []FeedOffer `xml:"offers>offer"`
+}
+
+// FeedOffer — одно предложение прайс-листа
+type FeedOffer struct {
+	SKU            string             `xml:"sku,attr"`
+	Model          string             `xml:"model"`
+	Brand          string             `xml:"brand,omitempty"`
+	Availabilities []FeedAvailability `xml:"availabilities>availability"`
+	Price          int64              `xml:"price,omitempty"`
+	CityPrices     []FeedCityPrice    `xml:"cityprices>cityprice,omitempty"` // Вместо price, если цены в городах разные
+}
+
+// FeedCityPrice — цена предложения в одном городе
+type FeedCityPrice struct {
+	CityID string `xml:"cityId,attr"`
+	Price  int64  `xml:",chardata"`
+}
+
+// FeedAvailability — наличие товара в одной точке самовывоза
+type FeedAvailability struct {
+	Available  string `xml:"available,attr"` // "yes" или "no"
+	StoreID    string `xml:"storeId,attr"`
+	StockCount *int   `xml:"stockCount,attr,omitempty"`
+}
+
+// NewFeedCatalog создаёт пустой прайс-лист продавца
+func NewFeedCatalog(company, merchantID string, generatedAt time.Time) *FeedCatalog {
+	return &FeedCatalog{
+		Xmlns:          FeedNamespace,
+		XmlnsXSI:       "http://www.w3.org/2001/XMLSchema-instance",
+		SchemaLocation: FeedSchemaLocation,
+		Date:           generatedAt.Format("2006-01-02T15:04:05"),
+		Company:        company,
+		MerchantID:     merchantID,
+	}
+}
+
+// NewFeedOffer собирает предложение: цена округляется до целых тенге, а наличие
+// проставляется для каждой точки самовывоза. Остаток указывается только при одной
+// точке, иначе Kaspi посчитал бы его отдельно для каждой.
+func NewFeedOffer(sku, model string, price float64, stock int, pickupPoints []string) FeedOffer {
+	if len(pickupPoints) == 0 {
+		pickupPoints = []string{DefaultPickupPoint}
+	}
+
+	available := "no"
+	if stock > 0 {
+		available = "yes"
+	}
+
+	offer := FeedOffer{
+		SKU:   sku,
+		Model: model,
+		Price: int64(math.Round(price)),
+	}
+
+	for _, storeID := range pickupPoints {
+		availability := FeedAvailability{Available: available, StoreID: storeID}
+		if len(pickupPoints) == 1 {
+			count := max(stock, 0)
+			availability.StockCount = &count
+		}
+		offer.Availabilities = append(offer.Availabilities, availability)
+	}
+
+	return offer
+}
+
+// NewFeedOfferByPoint собирает предложение с отдельным остатком для каждой точки
+// самовывоза. Точки, которых нет в stocks, помечаются как отсутствующие.
+func NewFeedOfferByPoint(sku, model string, price float64, stocks map[string]int, pickupPoints []string) FeedOffer {
+	if len(pickupPoints) == 0 {
+		pickupPoints = []string{DefaultPickupPoint}
+	}
+
+	offer := FeedOffer{
+		SKU:   sku,
+		Model: model,
+		Price: int64(math.Round(price)),
+	}
+
+	for _, storeID := range pickupPoints {
+		count := max(stocks[storeID], 0)
+		available := "no"
+		if count > 0 {
+			available = "yes"
+		}
+		offer.Availabilities = append(offer.Availabilities, FeedAvailability{Available: available, StoreID: storeID, StockCount: &count})
+	}
+
+	return offer
+}
+
+// SetCityPrices заменяет общую цену предложения ценами по городам (город -> цена).
+// Схема допускает только один из тегов price и cityprices.
+func (o *FeedOffer) SetCityPrices(prices map[string]float64) {
+	cities := make([]string, 0, len(prices))
+	for city := range prices {
+		cities = append(cities, city)
+	}
+	sort.Strings(cities)
+
+	o.Price = 0
+	o.CityPrices = make([]FeedCityPrice, 0, len(cities))
+	for _, city := range cities {
+		o.CityPrices = append(o.CityPrices, FeedCityPrice{CityID: city, Price: int64(math.Round(prices[city]))})
+	}
+}
+
+// Validate проверяет предложение по правилам схемы kaspishopping.xsd
+func (o *FeedOffer) Validate() error {
+	if strings.TrimSpace(o.SKU) == "" {
+		return fmt.Errorf("%w: offer without sku", ErrInvalidFeed)
+	}
+	if strings.TrimSpace(o.Model) == "" {
+		return fmt.Errorf("%w: offer %s: empty model", ErrInvalidFeed, o.SKU)
+	}
+	if len(o.CityPrices) > 0 {
+		if o.Price != 0 {
+			return fmt.Errorf("%w: offer %s: both price and cityprices set", ErrInvalidFeed, o.SKU)
+		}
+		for _, cp := range o.CityPrices {
+			if strings.TrimSpace(cp.CityID) == "" {
+				return fmt.Errorf("%w: offer %s: cityprice without cityId", ErrInvalidFeed, o.SKU)
+			}
+			if cp.Price <= 0 {
+				return fmt.Errorf("%w: offer %s: price in city %s must be positive, got %d", ErrInvalidFeed, o.SKU, cp.CityID, cp.Price)
+			}
+		}
+	} else if o.Price <= 0 {
+		return fmt.Errorf("%w: offer %s: price must be positive, got %d", ErrInvalidFeed, o.SKU, o.Price)
+	}
+	if len(o.Availabilities) == 0 {
+		return fmt.Errorf("%w: offer %s: no availabilities", ErrInvalidFeed, o.SKU)
+	}
+
+	for _, a := range o.Availabilities {
+		if a.Available != "yes" && a.Available != "no" {
+			return fmt.Errorf("%w: offer %s: available must be yes or no, got %q", ErrInvalidFeed, o.SKU, a.Available)
+		}
+		if strings.TrimSpace(a.StoreID) == "" {
+			return fmt.Errorf("%w: offer %s: availability without storeId", ErrInvalidFeed, o.SKU)
+		}
+		if a.StockCount != nil && *a.StockCount < 0 {
+			return fmt.Errorf("%w: offer %s: negative stockCount", ErrInvalidFeed, o.SKU)
+		}
+	}
+
+	return nil
+}
+
+// Validate проверяет весь прайс-лист: обязательные поля каталога, каждое предложение
+// и уникальность sku
+func (c *FeedCatalog) Validate() error {
+	if c.Xmlns != FeedNamespace {
+		return fmt.Errorf("%w: namespace must be %s", ErrInvalidFeed, FeedNamespace)
+	}
+	if c.Date == "" {
+		return fmt.Errorf("%w: missing date", ErrInvalidFeed)
+	}
+	if strings.TrimSpace(c.Company) == "" {
+		return fmt.Errorf("%w: missing company", ErrInvalidFeed)
+	}
+	if strings.TrimSpace(c.MerchantID) == "" {
+		return fmt.Errorf("%w: missing merchantid", ErrInvalidFeed)
+	}
+
+	seen := make(map[string]bool, len(c.Offers))
+	for i := range c.Offers {
+		if err := c.Offers[i].Validate(); err != nil {
+			return err
+		}
+		if seen[c.Offers[i].SKU] {
+			return fmt.Errorf("%w: duplicate sku %s", ErrInvalidFeed, c.Offers[i].SKU)
+		}
+		seen[c.Offers[i].SKU] = true
+	}
+
+	return nil
+}
+
+// Render проверяет прайс-лист и сериализует его в XML (UTF-8)
+func (c *FeedCatalog) Render() ([]byte, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(c); err != nil {
+		return nil, fmt.Errorf("failed to encode price feed: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
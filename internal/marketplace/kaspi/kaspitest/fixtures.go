@@ -0,0 +1,206 @@
+package kaspitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Product is a catalog entry served by the fake merchant API. Stock is the total;
+// Stocks, if set, splits it across pickup points. CityPrices override Price in their cities.
+type Product struct {
+	ID         string       `json:"id"`
+	SKU        string       `json:"sku"`
+	Name       string       `json:"name"`
+	Stock      int          `json:"stock"`
+	Stocks     []PointStock `json:"stocks,omitempty"`
+	Price      float64      `json:"price"`
+	CityPrices []CityPrice  `json:"city_prices,omitempty"`
+	Currency   string       `json:"currency"`
+}
+
+// CityPrice is the product's price in one city
+type CityPrice struct {
+	City  string  `json:"city"`
+	Price float64 `json:"price"`
+}
+
+// PointStock is a product's stock at one pickup point
+type PointStock struct {
+	WarehouseID string `json:"warehouse_id"`
+	City        string `json:"city"`
+	Stock       int    `json:"stock"`
+}
+
+// Sale is a per-day sales row for a product
+type Sale struct {
+	ProductID    string    `json:"product_id"`
+	Date         time.Time `json:"date"`
+	QuantitySold int       `json:"quantity_sold"`
+	Revenue      float64   `json:"revenue"`
+}
+
+// Review is a customer review of a product
+type Review struct {
+	ID         string    `json:"id"`
+	ProductID  string    `json:"product_id"`
+	AuthorName string    `json:"author_name"`
+	Rating     int       `json:"rating"`
+	Comment    string    `json:"comment"`
+	Language   string    `json:"language"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// OrderEntry is one product line of an order
+type OrderEntry struct {
+	ProductID string  `json:"product_id"`
+	Name      string  `json:"name"`
+	Quantity  int     `json:"quantity"`
+	BasePrice float64 `json:"base_price"`
+}
+
+// Order is a customer order; Status uses Kaspi's values (APPROVED_BY_BANK, ACCEPTED_BY_MERCHANT,
+// READY_FOR_PICKUP, KASPI_DELIVERY, COMPLETED, CANCELLED, RETURNED) and DeliveryMode is PICKUP,
+// DELIVERY or EXPRESS
+type Order struct {
+	ID           string       `json:"id"`
+	Status       string       `json:"status"`
+	TotalPrice   float64      `json:"total_price"`
+	CustomerCity string       `json:"customer_city"`
+	DeliveryMode string       `json:"delivery_mode"`
+	PickupPoint  string       `json:"pickup_point_id,omitempty"` // pickup point the order is fulfilled from
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+	Entries      []OrderEntry `json:"entries"`
+}
+
+// Delivery describes how an offer is delivered to the customer
+type Delivery struct {
+	Type string `json:"type"`
+	Days int    `json:"days"`
+}
+
+// Offer is a seller's offer for a product, including our own
+type Offer struct {
+	MerchantID     string   `json:"merchant_id"`
+	MerchantName   string   `json:"merchant_name"`
+	MerchantRating float64  `json:"merchant_rating,omitempty"` // 0-5
+	Price          float64  `json:"price"`
+	City           string   `json:"city"`
+	Delivery       Delivery `json:"delivery"`
+}
+
+// Fixtures is the seed data for a fake Kaspi merchant
+type Fixtures struct {
+	MerchantID string             `json:"merchant_id"`
+	APIKey     string             `json:"api_key"` // empty accepts any bearer token
+	Products   []Product          `json:"products"`
+	Sales      []Sale             `json:"sales"`
+	Reviews    []Review           `json:"reviews"`
+	Orders     []Order            `json:"orders"`
+	Offers     map[string][]Offer `json:"offers"` // keyed by product ID
+}
+
+// LoadFixtures reads fixtures from a JSON file
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+
+	var f Fixtures
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures: %w", err)
+	}
+
+	if f.Offers == nil {
+		f.Offers = make(map[string][]Offer)
+	}
+
+	return &f, nil
+}
+
+// DefaultFixtures returns a small demo catalog with sales, orders, reviews and competitor offers
+func DefaultFixtures() *Fixtures {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	f := &Fixtures{
+		MerchantID: "demo-merchant",
+		Products: []Product{
+			{ID: "100001", SKU: "IPH15-128-BLK", Name: "Apple iPhone 15 128GB черный", Stock: 12, Price: 429990, Currency: "KZT",
+				Stocks:     []PointStock{{WarehouseID: "PP1", City: "Almaty", Stock: 9}, {WarehouseID: "PP2", City: "Astana", Stock: 3}},
+				CityPrices: []CityPrice{{City: "Almaty", Price: 429990}, {City: "Astana", Price: 434990}}},
+			{ID: "100002", SKU: "SGS24-256-GRY", Name: "Samsung Galaxy S24 256GB серый", Stock: 4, Price: 389990, Currency: "KZT",
+				Stocks: []PointStock{{WarehouseID: "PP1", City: "Almaty", Stock: 0}, {WarehouseID: "PP2", City: "Astana", Stock: 4}}},
+			{ID: "100003", SKU: "XRN13-128-BLU", Name: "Xiaomi Redmi Note 13 128GB синий", Stock: 0, Price: 89990, Currency: "KZT"},
+		},
+		Reviews: []Review{
+			{ID: "r-1", ProductID: "100001", AuthorName: "Айгерим", Rating: 5, Comment: "Быстрая доставка, всё отлично", Language: "ru", CreatedAt: today.Add(-26 * time.Hour)},
+			{ID: "r-2", ProductID: "100002", AuthorName: "Данияр", Rating: 2, Comment: "Коробка была помята", Language: "ru", CreatedAt: today.Add(-5 * time.Hour)},
+		},
+		Offers: map[string][]Offer{
+			"100001": {
+				{MerchantID: "demo-merchant", MerchantName: "Demo Shop", Price: 429990, City: "Almaty", Delivery: Delivery{Type: "delivery", Days: 1}},
+				{MerchantID: "m-technoshop", MerchantName: "TechnoShop KZ", MerchantRating: 4.8, Price: 425000, City: "Almaty", Delivery: Delivery{Type: "delivery", Days: 2}},
+				{MerchantID: "m-megastore", MerchantName: "Mega Store", MerchantRating: 4.2, Price: 431500, City: "Almaty", Delivery: Delivery{Type: "pickup", Days: 0}},
+				{MerchantID: "demo-merchant", MerchantName: "Demo Shop", Price: 434990, City: "Astana", Delivery: Delivery{Type: "pickup", Days: 0}},
+				{MerchantID: "m-astanatech", MerchantName: "Astana Tech", MerchantRating: 4.6, Price: 436000, City: "Astana", Delivery: Delivery{Type: "delivery", Days: 1}},
+			},
+			"100002": {
+				{MerchantID: "m-digital", MerchantName: "Digital World", MerchantRating: 3.9, Price: 384990, City: "Astana", Delivery: Delivery{Type: "express", Days: 0}},
+				{MerchantID: "m-bestprice", MerchantName: "Best Price KZ", MerchantRating: 4.5, Price: 392000, City: "Almaty", Delivery: Delivery{Type: "delivery", Days: 3}},
+			},
+		},
+	}
+
+	cities := []string{"Almaty", "Astana", "Shymkent"}
+	modes := []string{"DELIVERY", "PICKUP", "EXPRESS"}
+	points := []string{"PP1", "PP2", "PP1"} // Shymkent is served from Almaty
+
+	for i := 0; i < 14; i++ {
+		day := today.AddDate(0, 0, -i)
+		f.Sales = append(f.Sales,
+			Sale{ProductID: "100001", Date: day, QuantitySold: 1 + i%3, Revenue: float64(1+i%3) * 429990},
+			Sale{ProductID: "100002", Date: day, QuantitySold: i % 2, Revenue: float64(i%2) * 389990},
+		)
+
+		// Orders mirror the sales rows, so sales derived from orders match the sales endpoint
+		quantity := 1 + i%3
+		placed := day.Add(10 * time.Hour)
+		f.Orders = append(f.Orders, Order{
+			ID: fmt.Sprintf("ord-%03d-1", i), Status: "COMPLETED", TotalPrice: float64(quantity) * 429990,
+			CustomerCity: cities[i%3], DeliveryMode: modes[i%3], PickupPoint: points[i%3], CreatedAt: placed, UpdatedAt: placed.Add(6 * time.Hour),
+			Entries: []OrderEntry{{ProductID: "100001", Name: "Apple iPhone 15 128GB черный", Quantity: quantity, BasePrice: 429990}},
+		})
+		if i%2 == 1 {
+			f.Orders = append(f.Orders, Order{
+				ID: fmt.Sprintf("ord-%03d-2", i), Status: "COMPLETED", TotalPrice: 389990,
+				CustomerCity: cities[(i+1)%3], DeliveryMode: modes[(i+1)%3], PickupPoint: points[(i+1)%3], CreatedAt: placed.Add(time.Hour), UpdatedAt: placed.Add(7 * time.Hour),
+				Entries: []OrderEntry{{ProductID: "100002", Name: "Samsung Galaxy S24 256GB серый", Quantity: 1, BasePrice: 389990}},
+			})
+		}
+	}
+
+	// Orders still waiting for the merchant, and one the customer cancelled
+	now := time.Now().UTC().Truncate(time.Minute)
+	f.Orders = append(f.Orders,
+		Order{
+			ID: "ord-new-1", Status: "APPROVED_BY_BANK", TotalPrice: 429990, CustomerCity: "Almaty", DeliveryMode: "DELIVERY", PickupPoint: "PP1",
+			CreatedAt: now.Add(-2 * time.Hour), UpdatedAt: now.Add(-2 * time.Hour),
+			Entries: []OrderEntry{{ProductID: "100001", Name: "Apple iPhone 15 128GB черный", Quantity: 1, BasePrice: 429990}},
+		},
+		Order{
+			ID: "ord-new-2", Status: "ACCEPTED_BY_MERCHANT", TotalPrice: 779980, CustomerCity: "Astana", DeliveryMode: "PICKUP", PickupPoint: "PP2",
+			CreatedAt: now.Add(-5 * time.Hour), UpdatedAt: now.Add(-4 * time.Hour),
+			Entries: []OrderEntry{{ProductID: "100002", Name: "Samsung Galaxy S24 256GB серый", Quantity: 2, BasePrice: 389990}},
+		},
+		Order{
+			ID: "ord-cancel-1", Status: "CANCELLED", TotalPrice: 89990, CustomerCity: "Shymkent", DeliveryMode: "DELIVERY", PickupPoint: "PP1",
+			CreatedAt: now.Add(-30 * time.Hour), UpdatedAt: now.Add(-20 * time.Hour),
+			Entries: []OrderEntry{{ProductID: "100003", Name: "Xiaomi Redmi Note 13 128GB синий", Quantity: 1, BasePrice: 89990}},
+		},
+	)
+
+	return f
+}
@@ -0,0 +1,546 @@
+package kaspitest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultPageSize = 100
+
+// PriceUpdate records a price change received by the fake server
+type PriceUpdate struct {
+	ProductID string    `json:"product_id"`
+	City      string    `json:"city,omitempty"` // empty for all cities
+	Price     float64   `json:"price"`
+	Currency  string    `json:"currency"`
+	At        time.Time `json:"at"`
+}
+
+// StockUpdate records a stock change received by the fake server
+type StockUpdate struct {
+	ProductID   string    `json:"product_id"`
+	WarehouseID string    `json:"warehouse_id"`
+	Stock       int       `json:"stock"`
+	At          time.Time `json:"at"`
+}
+
+// OrderAction records an order state change received by the fake server
+type OrderAction struct {
+	OrderID string    `json:"order_id"`
+	Action  string    `json:"action"` // accept, ready or cancel
+	Reason  string    `json:"reason,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+// cancelReasons are the cancellation codes the fake accepts
+var cancelReasons = map[string]bool{
+	"MERCHANT_OUT_OF_PRODUCT":        true,
+	"BUYER_CANCELLATION_BY_MERCHANT": true,
+	"BUYER_NOT_REACHABLE":            true,
+	"MERCHANT_WRONG_PRICE":           true,
+	"OTHER":                          true,
+}
+
+// Handler serves the Kaspi merchant API from in-memory fixtures.
+// It is safe for concurrent use and records every write it receives.
+type Handler struct {
+	mu              sync.Mutex
+	fixtures        *Fixtures
+	priceUpdates    []PriceUpdate
+	stockUpdates    []StockUpdate
+	reviewResponses map[string]string
+	orderActions    []OrderAction
+	faults          []fault
+	requests        int
+}
+
+// fault is a canned error response returned instead of the real one
+type fault struct {
+	status     int
+	retryAfter time.Duration
+}
+
+// NewHandler creates a handler seeded with the given fixtures (DefaultFixtures if nil)
+func NewHandler(fixtures *Fixtures) *Handler {
+	if fixtures == nil {
+		fixtures = DefaultFixtures()
+	}
+	if fixtures.Offers == nil {
+		fixtures.Offers = make(map[string][]Offer)
+	}
+
+	return &Handler{
+		fixtures:        fixtures,
+		reviewResponses: make(map[string]string),
+	}
+}
+
+// MerchantID returns the merchant the handler serves
+func (h *Handler) MerchantID() string {
+	return h.fixtures.MerchantID
+}
+
+// SetOffers replaces competitor offers for a product
+func (h *Handler) SetOffers(productID string, offers []Offer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.fixtures.Offers[productID] = offers
+}
+
+// PriceUpdates returns all price updates received so far
+func (h *Handler) PriceUpdates() []PriceUpdate {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	updates := make([]PriceUpdate, len(h.priceUpdates))
+	copy(updates, h.priceUpdates)
+	return updates
+}
+
+// StockUpdates returns all stock updates received so far
+func (h *Handler) StockUpdates() []StockUpdate {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	updates := make([]StockUpdate, len(h.stockUpdates))
+	copy(updates, h.stockUpdates)
+	return updates
+}
+
+// OrderActions returns all order state changes received so far
+func (h *Handler) OrderActions() []OrderAction {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	actions := make([]OrderAction, len(h.orderActions))
+	copy(actions, h.orderActions)
+	return actions
+}
+
+// FailNext makes the next count requests fail with status (e.g. 429 or 503).
+// A positive retryAfter is sent as a Retry-After header in whole seconds.
+func (h *Handler) FailNext(count, status int, retryAfter time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i := 0; i < count; i++ {
+		h.faults = append(h.faults, fault{status: status, retryAfter: retryAfter})
+	}
+}
+
+// Requests returns the number of requests received, including failed ones
+func (h *Handler) Requests() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.requests
+}
+
+// ReviewResponses returns posted review responses keyed by review ID
+func (h *Handler) ReviewResponses() map[string]string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	responses := make(map[string]string, len(h.reviewResponses))
+	for k, v := range h.reviewResponses {
+		responses[k] = v
+	}
+	return responses
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.injectFault(w) {
+		return
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || (h.fixtures.APIKey != "" && token != h.fixtures.APIKey) {
+		writeError(w, http.StatusUnauthorized, "invalid or missing API token")
+		return
+	}
+
+	// Expected path: /merchants/{merchant_id}/{resource}[/{id}[/{action}]]
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 || parts[0] != "merchants" {
+		writeError(w, http.StatusNotFound, "unknown endpoint")
+		return
+	}
+	if parts[1] != h.fixtures.MerchantID {
+		writeError(w, http.StatusForbidden, "merchant does not belong to this token")
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	route := parts[2:]
+	switch {
+	case r.Method == http.MethodGet && len(route) == 1 && route[0] == "products":
+		h.listProducts(w, r)
+	case r.Method == http.MethodGet && len(route) == 3 && route[0] == "products" && route[2] == "stock":
+		h.getStock(w, route[1])
+	case r.Method == http.MethodPut && len(route) == 3 && route[0] == "products" && route[2] == "stock":
+		h.updateStock(w, r, route[1])
+	case r.Method == http.MethodGet && len(route) == 3 && route[0] == "products" && route[2] == "offers":
+		h.getOffers(w, r, route[1])
+	case r.Method == http.MethodPut && len(route) == 3 && route[0] == "products" && route[2] == "price":
+		h.updatePrice(w, r, route[1])
+	case r.Method == http.MethodGet && len(route) == 1 && route[0] == "sales":
+		h.listSales(w, r)
+	case r.Method == http.MethodGet && len(route) == 1 && route[0] == "orders":
+		h.listOrders(w, r)
+	case r.Method == http.MethodPost && len(route) == 3 && route[0] == "orders":
+		h.orderAction(w, r, route[1], route[2])
+	case r.Method == http.MethodGet && len(route) == 1 && route[0] == "reviews":
+		h.listReviews(w, r)
+	case r.Method == http.MethodPost && len(route) == 3 && route[0] == "reviews" && route[2] == "response":
+		h.postReviewResponse(w, r, route[1])
+	default:
+		writeError(w, http.StatusNotFound, "unknown endpoint")
+	}
+}
+
+func (h *Handler) injectFault(w http.ResponseWriter) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.requests++
+	if len(h.faults) == 0 {
+		return false
+	}
+
+	f := h.faults[0]
+	h.faults = h.faults[1:]
+
+	if f.retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(f.retryAfter.Seconds())))
+	}
+	writeError(w, f.status, http.StatusText(f.status))
+	return true
+}
+
+func (h *Handler) listProducts(w http.ResponseWriter, r *http.Request) {
+	from, to, meta := paginate(r, len(h.fixtures.Products))
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": h.fixtures.Products[from:to], "meta": meta})
+}
+
+func (h *Handler) getStock(w http.ResponseWriter, productID string) {
+	p := h.findProduct(productID)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "product not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"stock": p.Stock, "stocks": p.Stocks})
+}
+
+// getOffers lists all sellers' offers for a product, optionally in one city only
+func (h *Handler) getOffers(w http.ResponseWriter, r *http.Request, productID string) {
+	if h.findProduct(productID) == nil {
+		writeError(w, http.StatusNotFound, "product not found")
+		return
+	}
+
+	city := r.URL.Query().Get("city")
+	offers := make([]Offer, 0, len(h.fixtures.Offers[productID]))
+	for _, o := range h.fixtures.Offers[productID] {
+		if city == "" || strings.EqualFold(o.City, city) {
+			offers = append(offers, o)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": offers})
+}
+
+func (h *Handler) updatePrice(w http.ResponseWriter, r *http.Request, productID string) {
+	p := h.findProduct(productID)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "product not found")
+		return
+	}
+
+	var req struct {
+		Price    float64 `json:"price"`
+		Currency string  `json:"currency"`
+		City     string  `json:"city"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Price <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid price")
+		return
+	}
+
+	// Without a city the price applies everywhere and replaces the city prices
+	if req.City == "" {
+		p.Price = req.Price
+		p.CityPrices = nil
+	} else {
+		setCityPrice(p, req.City, req.Price)
+	}
+
+	for i := range h.fixtures.Offers[productID] {
+		o := &h.fixtures.Offers[productID][i]
+		if o.MerchantID == h.fixtures.MerchantID && (req.City == "" || strings.EqualFold(o.City, req.City)) {
+			o.Price = req.Price
+		}
+	}
+
+	h.priceUpdates = append(h.priceUpdates, PriceUpdate{
+		ProductID: productID,
+		City:      req.City,
+		Price:     req.Price,
+		Currency:  req.Currency,
+		At:        time.Now(),
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": productID, "price": req.Price})
+}
+
+func setCityPrice(p *Product, city string, price float64) {
+	for i := range p.CityPrices {
+		if strings.EqualFold(p.CityPrices[i].City, city) {
+			p.CityPrices[i].Price = price
+			return
+		}
+	}
+	p.CityPrices = append(p.CityPrices, CityPrice{City: city, Price: price})
+}
+
+// updateStock sets a product's stock at one pickup point and recomputes the total
+func (h *Handler) updateStock(w http.ResponseWriter, r *http.Request, productID string) {
+	p := h.findProduct(productID)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "product not found")
+		return
+	}
+
+	var req struct {
+		WarehouseID string `json:"warehouse_id"`
+		Stock       *int   `json:"stock"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.WarehouseID == "" || req.Stock == nil || *req.Stock < 0 {
+		writeError(w, http.StatusBadRequest, "warehouse_id and a non-negative stock are required")
+		return
+	}
+
+	setPointStock(p, req.WarehouseID, *req.Stock)
+	h.stockUpdates = append(h.stockUpdates, StockUpdate{
+		ProductID:   productID,
+		WarehouseID: req.WarehouseID,
+		Stock:       *req.Stock,
+		At:          time.Now(),
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": productID, "warehouse_id": req.WarehouseID, "stock": *req.Stock})
+}
+
+// setPointStock replaces the stock at one pickup point. A product without a per-point
+// split keeps all its stock at the named point.
+func setPointStock(p *Product, warehouseID string, stock int) {
+	found := false
+	for i := range p.Stocks {
+		if p.Stocks[i].WarehouseID == warehouseID {
+			p.Stocks[i].Stock = stock
+			found = true
+			break
+		}
+	}
+	if !found {
+		p.Stocks = append(p.Stocks, PointStock{WarehouseID: warehouseID, Stock: stock})
+	}
+
+	total := 0
+	for _, s := range p.Stocks {
+		total += s.Stock
+	}
+	p.Stock = total
+}
+
+func (h *Handler) listSales(w http.ResponseWriter, r *http.Request) {
+	start, errStart := time.Parse("2006-01-02", r.URL.Query().Get("start_date"))
+	end, errEnd := time.Parse("2006-01-02", r.URL.Query().Get("end_date"))
+	if errStart != nil || errEnd != nil {
+		writeError(w, http.StatusBadRequest, "start_date and end_date are required (YYYY-MM-DD)")
+		return
+	}
+	end = end.Add(24 * time.Hour)
+
+	sales := make([]Sale, 0, len(h.fixtures.Sales))
+	for _, s := range h.fixtures.Sales {
+		if !s.Date.Before(start) && s.Date.Before(end) {
+			sales = append(sales, s)
+		}
+	}
+
+	from, to, meta := paginate(r, len(sales))
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": sales[from:to], "meta": meta})
+}
+
+func (h *Handler) listOrders(w http.ResponseWriter, r *http.Request) {
+	since, err := time.Parse(time.RFC3339, r.URL.Query().Get("updated_since"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "updated_since is required (RFC 3339)")
+		return
+	}
+
+	orders := make([]Order, 0, len(h.fixtures.Orders))
+	for _, o := range h.fixtures.Orders {
+		if !o.UpdatedAt.Before(since) {
+			orders = append(orders, o)
+		}
+	}
+
+	from, to, meta := paginate(r, len(orders))
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": orders[from:to], "meta": meta})
+}
+
+// orderAction applies accept, ready or cancel to an order, enforcing Kaspi's lifecycle
+func (h *Handler) orderAction(w http.ResponseWriter, r *http.Request, orderID, action string) {
+	var order *Order
+	for i := range h.fixtures.Orders {
+		if h.fixtures.Orders[i].ID == orderID {
+			order = &h.fixtures.Orders[i]
+			break
+		}
+	}
+	if order == nil {
+		writeError(w, http.StatusNotFound, "order not found")
+		return
+	}
+
+	var next, reason string
+	switch action {
+	case "accept":
+		if order.Status != "APPROVED_BY_BANK" {
+			writeError(w, http.StatusConflict, "order cannot be accepted in status "+order.Status)
+			return
+		}
+		next = "ACCEPTED_BY_MERCHANT"
+	case "ready":
+		if order.Status != "ACCEPTED_BY_MERCHANT" {
+			writeError(w, http.StatusConflict, "order cannot be marked ready in status "+order.Status)
+			return
+		}
+		next = "KASPI_DELIVERY"
+		if order.DeliveryMode == "PICKUP" {
+			next = "READY_FOR_PICKUP"
+		}
+	case "cancel":
+		var req struct {
+			Reason string `json:"reason"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !cancelReasons[req.Reason] {
+			writeError(w, http.StatusBadRequest, "a known cancellation reason is required")
+			return
+		}
+		switch order.Status {
+		case "COMPLETED", "CANCELLED", "RETURNED":
+			writeError(w, http.StatusConflict, "order cannot be cancelled in status "+order.Status)
+			return
+		}
+		next = "CANCELLED"
+		reason = req.Reason
+	default:
+		writeError(w, http.StatusNotFound, "unknown endpoint")
+		return
+	}
+
+	now := time.Now().UTC()
+	order.Status = next
+	order.UpdatedAt = now
+	h.orderActions = append(h.orderActions, OrderAction{OrderID: orderID, Action: action, Reason: reason, At: now})
+
+	writeJSON(w, http.StatusOK, order)
+}
+
+func (h *Handler) listReviews(w http.ResponseWriter, r *http.Request) {
+	from, to, meta := paginate(r, len(h.fixtures.Reviews))
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": h.fixtures.Reviews[from:to], "meta": meta})
+}
+
+func (h *Handler) postReviewResponse(w http.ResponseWriter, r *http.Request, reviewID string) {
+	found := false
+	for _, rv := range h.fixtures.Reviews {
+		if rv.ID == reviewID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		writeError(w, http.StatusNotFound, "review not found")
+		return
+	}
+
+	var req struct {
+		Response string `json:"response"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Response == "" {
+		writeError(w, http.StatusBadRequest, "response is required")
+		return
+	}
+
+	h.reviewResponses[reviewID] = req.Response
+
+	writeJSON(w, http.StatusCreated, map[string]string{"id": reviewID, "response": req.Response})
+}
+
+func (h *Handler) findProduct(productID string) *Product {
+	for i := range h.fixtures.Products {
+		if h.fixtures.Products[i].ID == productID {
+			return &h.fixtures.Products[i]
+		}
+	}
+	return nil
+}
+
+// pageMeta mirrors the pagination block of Kaspi list responses
+type pageMeta struct {
+	Page       int `json:"page"`
+	PageSize   int `json:"page_size"`
+	TotalPages int `json:"total_pages"`
+	Total      int `json:"total"`
+}
+
+// paginate returns the slice bounds for the page requested via ?page=&page_size=
+func paginate(r *http.Request, total int) (int, int, pageMeta) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+
+	meta := pageMeta{
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: (total + pageSize - 1) / pageSize,
+		Total:      total,
+	}
+
+	from := (page - 1) * pageSize
+	if from > total {
+		from = total
+	}
+	to := from + pageSize
+	if to > total {
+		to = total
+	}
+
+	return from, to, meta
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
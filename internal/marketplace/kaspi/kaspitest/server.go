@@ -0,0 +1,50 @@
+// Package kaspitest provides an in-memory fake of the Kaspi merchant API
+// for integration tests and local demos.
+package kaspitest
+
+import (
+	"net/http/httptest"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace/kaspi"
+)
+
+// Server is a fake Kaspi merchant API running on a local httptest listener
+type Server struct {
+	*Handler
+	URL string
+
+	srv *httptest.Server
+}
+
+// NewServer starts a fake Kaspi server seeded with fixtures (DefaultFixtures if nil).
+// Callers must Close it when done.
+func NewServer(fixtures *Fixtures) *Server {
+	h := NewHandler(fixtures)
+	srv := httptest.NewServer(h)
+
+	return &Server{
+		Handler: h,
+		URL:     srv.URL,
+		srv:     srv,
+	}
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// NewClient returns a kaspi.Client pointed at this server for the seeded merchant
+func (s *Server) NewClient(opts ...kaspi.Option) *kaspi.Client {
+	apiKey := s.fixtures.APIKey
+	if apiKey == "" {
+		apiKey = "test-api-key"
+	}
+
+	opts = append([]kaspi.Option{
+		kaspi.WithBaseURL(s.URL),
+		kaspi.WithHTTPClient(s.srv.Client()),
+	}, opts...)
+
+	return kaspi.NewClient(apiKey, s.MerchantID(), opts...)
+}
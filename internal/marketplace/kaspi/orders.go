@@ -0,0 +1,148 @@
+package kaspi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+// orderStatuses maps Kaspi order statuses onto marketplace.OrderStatus values
+var orderStatuses = map[string]string{
+	"APPROVED_BY_BANK":     marketplace.OrderStatusNew,
+	"ACCEPTED_BY_MERCHANT": marketplace.OrderStatusAccepted,
+	"READY_FOR_PICKUP":     marketplace.OrderStatusReady,
+	"KASPI_DELIVERY":       marketplace.OrderStatusReady,
+	"COMPLETED":            marketplace.OrderStatusDelivered,
+	"CANCELLED":            marketplace.OrderStatusCancelled,
+	"RETURNED":             marketplace.OrderStatusReturned,
+}
+
+// mapOrderStatus converts a Kaspi status; unknown statuses are passed through lower-cased
+func mapOrderStatus(status string) string {
+	if mapped, ok := orderStatuses[status]; ok {
+		return mapped
+	}
+	return strings.ToLower(status)
+}
+
+func (c *Client) GetOrders(ctx context.Context, since time.Time) ([]marketplace.OrderData, error) {
+	var orders []marketplace.OrderData
+	err := c.ForEachOrderPage(ctx, since, func(page []marketplace.OrderData) error {
+		orders = append(orders, page...)
+		return nil
+	})
+	return orders, err
+}
+
+// ForEachOrderPage streams orders created or changed since the given time to fn one page at a time
+func (c *Client) ForEachOrderPage(ctx context.Context, since time.Time, fn func([]marketplace.OrderData) error) error {
+	type entry struct {
+		ProductID string  `json:"product_id"`
+		Name      string  `json:"name"`
+		Quantity  int     `json:"quantity"`
+		BasePrice float64 `json:"base_price"`
+	}
+
+	type order struct {
+		ID           string    `json:"id"`
+		Status       string    `json:"status"`
+		TotalPrice   float64   `json:"total_price"`
+		CustomerCity string    `json:"customer_city"`
+		DeliveryMode string    `json:"delivery_mode"`
+		PickupPoint  string    `json:"pickup_point_id"`
+		CreatedAt    time.Time `json:"created_at"`
+		UpdatedAt    time.Time `json:"updated_at"`
+		Entries      []entry   `json:"entries"`
+	}
+
+	path := fmt.Sprintf("/merchants/%s/orders", c.merchantID)
+	query := url.Values{}
+	query.Set("updated_since", since.UTC().Format(time.RFC3339))
+
+	return fetchPages(ctx, c, path, query, func(page []order) error {
+		orders := make([]marketplace.OrderData, 0, len(page))
+		for _, o := range page {
+			lines := make([]marketplace.OrderLineData, 0, len(o.Entries))
+			for _, e := range o.Entries {
+				lines = append(lines, marketplace.OrderLineData{
+					ProductExternalID: e.ProductID,
+					Name:              e.Name,
+					Quantity:          e.Quantity,
+					Price:             e.BasePrice,
+				})
+			}
+
+			orders = append(orders, marketplace.OrderData{
+				ExternalID:   o.ID,
+				Status:       mapOrderStatus(o.Status),
+				CustomerCity: o.CustomerCity,
+				DeliveryType: strings.ToLower(o.DeliveryMode),
+				WarehouseID:  o.PickupPoint,
+				TotalPrice:   o.TotalPrice,
+				CreatedAt:    o.CreatedAt,
+				UpdatedAt:    o.UpdatedAt,
+				Lines:        lines,
+			})
+		}
+		return fn(orders)
+	})
+}
+
+// cancelReasons maps marketplace.CancelReason values onto Kaspi cancellation codes
+var cancelReasons = map[string]string{
+	marketplace.CancelReasonOutOfStock:          "MERCHANT_OUT_OF_PRODUCT",
+	marketplace.CancelReasonCustomerRequest:     "BUYER_CANCELLATION_BY_MERCHANT",
+	marketplace.CancelReasonCustomerUnreachable: "BUYER_NOT_REACHABLE",
+	marketplace.CancelReasonWrongPrice:          "MERCHANT_WRONG_PRICE",
+	marketplace.CancelReasonOther:               "OTHER",
+}
+
+// AcceptOrder moves a new order (APPROVED_BY_BANK) to ACCEPTED_BY_MERCHANT
+func (c *Client) AcceptOrder(ctx context.Context, externalID string) error {
+	return c.orderAction(ctx, externalID, "accept", nil)
+}
+
+// MarkOrderReady moves an accepted order to READY_FOR_PICKUP, or to KASPI_DELIVERY
+// for orders delivered by Kaspi; Kaspi picks the status from the delivery mode
+func (c *Client) MarkOrderReady(ctx context.Context, externalID string) error {
+	return c.orderAction(ctx, externalID, "ready", nil)
+}
+
+// CancelOrder cancels an order with one of the marketplace.CancelReason codes
+func (c *Client) CancelOrder(ctx context.Context, externalID, reason string) error {
+	code, ok := cancelReasons[reason]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrInvalidCancelReason, reason)
+	}
+
+	return c.orderAction(ctx, externalID, "cancel", map[string]string{"reason": code})
+}
+
+func (c *Client) orderAction(ctx context.Context, externalID, action string, payload interface{}) error {
+	url := fmt.Sprintf("%s/merchants/%s/orders/%s/%s", c.baseURL, c.merchantID, externalID, action)
+
+	var body []byte
+	if payload != nil {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return err
+		}
+	}
+
+	resp, err := c.makeRequest(ctx, "POST", url, body)
+	if err != nil {
+		if errors.Is(err, marketplace.ErrNotFound) {
+			return fmt.Errorf("%w: %s: %w", ErrOrderNotFound, externalID, err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package kaspi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// DefaultPageSize is the number of items requested per page
+	DefaultPageSize = 100
+
+	// DefaultMaxPages caps how many pages a single list call will follow
+	DefaultMaxPages = 500
+)
+
+// ErrPageLimitReached is returned when a list call still had pages left after MaxPages.
+// Pages fetched before the limit have already been delivered to the caller.
+var ErrPageLimitReached = errors.New("kaspi: page limit reached")
+
+// WithPageSize sets the page size for list calls
+func WithPageSize(size int) Option {
+	return func(c *Client) {
+		if size > 0 {
+			c.pageSize = size
+		}
+	}
+}
+
+// WithMaxPages caps the number of pages followed by a single list call
+func WithMaxPages(maxPages int) Option {
+	return func(c *Client) {
+		if maxPages > 0 {
+			c.maxPages = maxPages
+		}
+	}
+}
+
+// pageMeta is the pagination block returned alongside "data" by list endpoints.
+// Kaspi uses page numbers for most lists and an opaque cursor for some; both are supported.
+type pageMeta struct {
+	Page       int    `json:"page"`
+	TotalPages int    `json:"total_pages"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// fetchPages requests path page by page and hands each decoded page to fn.
+// It stops when the server reports no further pages, a page comes back empty,
+// fn returns an error, or maxPages is exceeded.
+func fetchPages[T any](ctx context.Context, c *Client, path string, query url.Values, fn func([]T) error) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("page_size", strconv.Itoa(c.pageSize))
+
+	page := 1
+	cursor := ""
+
+	for fetched := 0; ; fetched++ {
+		if fetched >= c.maxPages {
+			return fmt.Errorf("%w: stopped after %d pages of %s", ErrPageLimitReached, fetched, path)
+		}
+
+		if cursor != "" {
+			query.Del("page")
+			query.Set("cursor", cursor)
+		} else {
+			query.Set("page", strconv.Itoa(page))
+		}
+
+		resp, err := c.makeRequest(ctx, "GET", c.baseURL+path+"?"+query.Encode(), nil)
+		if err != nil {
+			return err
+		}
+
+		var response struct {
+			Data []T      `json:"data"`
+			Meta pageMeta `json:"meta"`
+		}
+
+		err = json.NewDecoder(resp.Body).Decode(&response)
+		resp.Body.Close()
+		if err != nil {
+			return decodeError(err)
+		}
+
+		if len(response.Data) == 0 {
+			return nil
+		}
+
+		if err := fn(response.Data); err != nil {
+			return err
+		}
+
+		switch {
+		case response.Meta.NextCursor != "":
+			cursor = response.Meta.NextCursor
+		case cursor == "" && response.Meta.TotalPages > page:
+			page++
+		default:
+			return nil
+		}
+	}
+}
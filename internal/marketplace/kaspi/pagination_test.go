@@ -0,0 +1,207 @@
+package kaspi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/internal/marketplace/kaspi"
+)
+
+func TestProductPagesByPageNumber(t *testing.T) {
+	srv, client := newTestServer(t, nil, kaspi.WithPageSize(1))
+
+	var pages [][]marketplace.ProductData
+	err := client.ForEachProductPage(context.Background(), func(page []marketplace.ProductData) error {
+		pages = append(pages, page)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachProductPage: %v", err)
+	}
+
+	want := []string{"100001", "100002", "100003"}
+	if len(pages) != len(want) {
+		t.Fatalf("got %d pages, want %d", len(pages), len(want))
+	}
+	for i, page := range pages {
+		if len(page) != 1 || page[0].ExternalID != want[i] {
+			t.Errorf("page %d = %+v, want product %s", i+1, page, want[i])
+		}
+	}
+	if n := srv.Requests(); n != 3 {
+		t.Errorf("server got %d requests, want 3", n)
+	}
+
+	// Products keep their city prices and per-point stock
+	p := pages[0][0]
+	if len(p.CityPrices) != 2 || p.CityPrices[1] != (marketplace.CityPrice{City: "Astana", Price: 434990}) {
+		t.Errorf("city prices = %+v", p.CityPrices)
+	}
+	if len(p.Stocks) != 2 || p.Stocks[0].WarehouseID != "PP1" || p.Stocks[0].Quantity != 9 {
+		t.Errorf("stocks = %+v", p.Stocks)
+	}
+}
+
+func TestProductsInOnePage(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+
+	products, err := client.GetProducts(context.Background())
+	if err != nil {
+		t.Fatalf("GetProducts: %v", err)
+	}
+	if len(products) != 3 {
+		t.Errorf("got %d products, want 3", len(products))
+	}
+	if n := srv.Requests(); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+}
+
+func TestPageLimitReached(t *testing.T) {
+	srv, client := newTestServer(t, nil, kaspi.WithPageSize(1), kaspi.WithMaxPages(2))
+
+	var got []string
+	err := client.ForEachProductPage(context.Background(), func(page []marketplace.ProductData) error {
+		for _, p := range page {
+			got = append(got, p.ExternalID)
+		}
+		return nil
+	})
+	if !errors.Is(err, kaspi.ErrPageLimitReached) {
+		t.Fatalf("err = %v, want ErrPageLimitReached", err)
+	}
+
+	// Pages before the limit are delivered
+	if len(got) != 2 || got[0] != "100001" || got[1] != "100002" {
+		t.Errorf("delivered %v, want the first two products", got)
+	}
+	if n := srv.Requests(); n != 2 {
+		t.Errorf("server got %d requests, want 2", n)
+	}
+}
+
+func TestPageLimitNotReachedOnLastPage(t *testing.T) {
+	_, client := newTestServer(t, nil, kaspi.WithPageSize(1), kaspi.WithMaxPages(3))
+
+	products, err := client.GetProducts(context.Background())
+	if err != nil {
+		t.Fatalf("GetProducts: %v", err)
+	}
+	if len(products) != 3 {
+		t.Errorf("got %d products, want 3", len(products))
+	}
+}
+
+func TestPagesStopOnCallbackError(t *testing.T) {
+	srv, client := newTestServer(t, nil, kaspi.WithPageSize(1))
+	stop := errors.New("stop")
+
+	err := client.ForEachProductPage(context.Background(), func([]marketplace.ProductData) error {
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("err = %v, want the callback's error", err)
+	}
+	if n := srv.Requests(); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+}
+
+// cursorServer serves reviews in pages linked by next_cursor, one review per page
+func cursorServer(t *testing.T, pages map[string][]string, next map[string]string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		q := r.URL.Query()
+		cursor := q.Get("cursor")
+		if cursor != "" && q.Get("page") != "" {
+			t.Errorf("request %s sends both cursor and page", r.URL.RawQuery)
+		}
+
+		data := []map[string]string{}
+		for _, id := range pages[cursor] {
+			data = append(data, map[string]string{"id": id})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": data,
+			"meta": map[string]string{"next_cursor": next[cursor]},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &requests
+}
+
+func TestReviewPagesByCursor(t *testing.T) {
+	srv, requests := cursorServer(t,
+		map[string][]string{"": {"r-1"}, "c2": {"r-2"}, "c3": {"r-3"}},
+		map[string]string{"": "c2", "c2": "c3"},
+	)
+	client := kaspi.NewClient("key", "merchant", kaspi.WithBaseURL(srv.URL), kaspi.WithRateLimit(0, 0))
+
+	reviews, err := client.GetReviews(context.Background())
+	if err != nil {
+		t.Fatalf("GetReviews: %v", err)
+	}
+
+	if len(reviews) != 3 {
+		t.Fatalf("got %d reviews, want 3", len(reviews))
+	}
+	for i, id := range []string{"r-1", "r-2", "r-3"} {
+		if reviews[i].ExternalID != id {
+			t.Errorf("review %d = %s, want %s", i, reviews[i].ExternalID, id)
+		}
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("server got %d requests, want 3", n)
+	}
+}
+
+func TestCursorPagesStopOnEmptyPage(t *testing.T) {
+	srv, requests := cursorServer(t,
+		map[string][]string{"": {"r-1"}},
+		map[string]string{"": "c2", "c2": "c3"},
+	)
+	client := kaspi.NewClient("key", "merchant", kaspi.WithBaseURL(srv.URL), kaspi.WithRateLimit(0, 0))
+
+	reviews, err := client.GetReviews(context.Background())
+	if err != nil {
+		t.Fatalf("GetReviews: %v", err)
+	}
+	if len(reviews) != 1 {
+		t.Errorf("got %d reviews, want 1", len(reviews))
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("server got %d requests, want 2", n)
+	}
+}
+
+func TestCursorPageLimitReached(t *testing.T) {
+	srv, requests := cursorServer(t,
+		map[string][]string{"": {"r-1"}, "c2": {"r-2"}, "c3": {"r-3"}},
+		map[string]string{"": "c2", "c2": "c3"},
+	)
+	client := kaspi.NewClient("key", "merchant", kaspi.WithBaseURL(srv.URL), kaspi.WithRateLimit(0, 0), kaspi.WithMaxPages(2))
+
+	reviews, err := client.GetReviews(context.Background())
+	if !errors.Is(err, kaspi.ErrPageLimitReached) {
+		t.Fatalf("err = %v, want ErrPageLimitReached", err)
+	}
+	if len(reviews) != 2 {
+		t.Errorf("got %d reviews, want 2", len(reviews))
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("server got %d requests, want 2", n)
+	}
+}
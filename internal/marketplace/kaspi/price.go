@@ -1,92 +1,121 @@
 package kaspi
 
 import (
-	"math/rand"
-	"time"
-)
-
-// CompetitorPrice представляет цену конкурента
-type CompetitorPrice struct {
-	SellerName string  `json:"seller_name"`
-	Price      float64 `json:"price"`
-}
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
 
-// GetCompetitorPrices получает цены конкурентов для товара (MOCK)
-// В реальной реализации здесь будет запрос к API Kaspi
-func (c *Client) GetCompetitorPrices(productExternalID string) ([]CompetitorPrice, error) {
-	// MOCK: Генерируем случайные цены конкурентов
-	// В реальности здесь будет HTTP запрос к Kaspi API
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
 
-	time.Sleep(100 * time.Millisecond) // Имитация задержки сети
+// CompetitorPrice представляет предложение конкурента по товару
+type CompetitorPrice = marketplace.CompetitorOffer
 
-	numCompetitors := rand.Intn(5) + 2 // От 2 до 6 конкурентов
-	prices := make([]CompetitorPrice, numCompetitors)
+// GetCompetitorPrices получает предложения конкурентов для товара в одном городе
+// (или во всех, если город не указан). Наше собственное предложение (по merchantID)
+// из списка исключается.
+func (c *Client) GetCompetitorPrices(ctx context.Context, productExternalID, city string) ([]CompetitorPrice, error) {
+	offersURL := fmt.Sprintf("%s/merchants/%s/products/%s/offers", c.baseURL, c.merchantID, productExternalID)
+	if city != "" {
+		offersURL += "?" + url.Values{"city": {city}}.Encode()
+	}
 
-	basePrice := 10000.0 + rand.Float64()*50000.0 // Базовая цена от 10к до 60к
+	resp, err := c.makeRequest(ctx, "GET", offersURL, nil)
+	if err != nil {
+		if errors.Is(err, marketplace.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s: %w", ErrProductNotFound, productExternalID, err)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Data []struct {
+			MerchantID     string  `json:"merchant_id"`
+			MerchantName   string  `json:"merchant_name"`
+			MerchantRating float64 `json:"merchant_rating"`
+			Price          float64 `json:"price"`
+			City           string  `json:"city"`
+			Delivery       struct {
+				Type string `json:"type"`
+				Days int    `json:"days"`
+			} `json:"delivery"`
+		} `json:"data"`
+	}
 
-	for i := 0; i < numCompetitors; i++ {
-		variation := (rand.Float64() - 0.5) * 0.2 // Вариация ±10%
-		price := basePrice * (1 + variation)
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, decodeError(err)
+	}
 
-		prices[i] = CompetitorPrice{
-			SellerName: generateSellerName(i),
-			Price:      roundToTenge(price),
+	prices := make([]CompetitorPrice, 0, len(response.Data))
+	for _, o := range response.Data {
+		// Пропускаем собственное предложение и некорректные цены
+		if o.MerchantID == c.merchantID || o.Price <= 0 {
+			continue
 		}
+
+		prices = append(prices, CompetitorPrice{
+			SellerID:     o.MerchantID,
+			SellerName:   o.MerchantName,
+			Price:        o.Price,
+			DeliveryType: o.Delivery.Type,
+			DeliveryDays: o.Delivery.Days,
+			City:         o.City,
+			SellerRating: o.MerchantRating,
+		})
 	}
 
 	return prices, nil
 }
 
-// UpdateProductPrice обновляет цену товара на Kaspi (MOCK)
-func (c *Client) UpdateProductPrice(productExternalID string, newPrice float64) error {
-	// MOCK: В реальности здесь будет HTTP PUT/PATCH запрос к Kaspi API
-	// для обновления цены товара
-
-	time.Sleep(100 * time.Millisecond) // Имитация задержки сети
-
-	// Логируем (в реальности здесь будет реальный запрос)
-	// log.Printf("Updating price for product %s to %.2f", productExternalID, newPrice)
-
-	return nil
+// UpdateProductPrice обновляет цену товара на Kaspi во всех городах
+func (c *Client) UpdateProductPrice(ctx context.Context, productExternalID string, newPrice float64) error {
+	return c.updatePrice(ctx, productExternalID, "", newPrice)
 }
 
-// GetMinCompetitorPrice возвращает минимальную цену среди конкурентов
-func GetMinCompetitorPrice(prices []CompetitorPrice) float64 {
-	if len(prices) == 0 {
-		return 0
+// UpdateCityPrice обновляет цену товара на Kaspi только в одном городе
+func (c *Client) UpdateCityPrice(ctx context.Context, productExternalID, city string, newPrice float64) error {
+	if city == "" {
+		return fmt.Errorf("%w: city is required", ErrInvalidPrice)
 	}
+	return c.updatePrice(ctx, productExternalID, city, newPrice)
+}
 
-	minPrice := prices[0].Price
-	for _, p := range prices {
-		if p.Price < minPrice {
-			minPrice = p.Price
-		}
+func (c *Client) updatePrice(ctx context.Context, productExternalID, city string, newPrice float64) error {
+	if newPrice <= 0 {
+		return fmt.Errorf("%w: %.2f", ErrInvalidPrice, newPrice)
 	}
 
-	return minPrice
-}
+	url := fmt.Sprintf("%s/merchants/%s/products/%s/price", c.baseURL, c.merchantID, productExternalID)
 
-// Helper functions
-
-func generateSellerName(index int) string {
-	names := []string{
-		"TechnoShop KZ",
-		"Mega Store",
-		"Digital World",
-		"Smart Electronics",
-		"Best Price KZ",
-		"Tech Master",
-		"Gadget Paradise",
-		"Kazakhstan Electronics",
+	payload := map[string]interface{}{
+		"price":    newPrice,
+		"currency": "KZT",
+	}
+	if city != "" {
+		payload["city"] = city
 	}
 
-	if index < len(names) {
-		return names[index]
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
 	}
 
-	return "Seller " + string(rune('A'+index))
+	resp, err := c.makeRequest(ctx, "PUT", url, payloadBytes)
+	if err != nil {
+		if errors.Is(err, marketplace.ErrNotFound) {
+			return fmt.Errorf("%w: %s: %w", ErrProductNotFound, productExternalID, err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
 }
 
-func roundToTenge(price float64) float64 {
-	return float64(int(price))
+// GetMinCompetitorPrice возвращает минимальную цену среди конкурентов
+func GetMinCompetitorPrice(prices []CompetitorPrice) float64 {
+	return marketplace.MinOfferPrice(prices)
 }
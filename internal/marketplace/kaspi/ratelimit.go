@@ -0,0 +1,94 @@
+package kaspi
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+const (
+	// DefaultRateLimit is the sustained request rate allowed per merchant (requests/second)
+	DefaultRateLimit = 5.0
+
+	// DefaultRateBurst is the number of requests a merchant may burst above the sustained rate
+	DefaultRateBurst = 10
+)
+
+// tokenBucket is a simple token-bucket rate limiter
+type tokenBucket struct {
+	mu       sync.Mutex
+	rate     float64 // tokens per second
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:     rate,
+		capacity: float64(burst),
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done and returns how long it waited
+func (b *tokenBucket) Wait(ctx context.Context) (time.Duration, error) {
+	var waited time.Duration
+
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.last = now
+
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return waited, nil
+		}
+
+		delay := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		if err := marketplace.SleepContext(ctx, delay); err != nil {
+			return waited, err
+		}
+		waited += delay
+	}
+}
+
+// Limiters are shared by merchant ID: services create a fresh Client per run,
+// but Kaspi enforces limits per merchant, so all clients for one merchant share a bucket.
+var (
+	limitersMu sync.Mutex
+	limiters   = make(map[string]*tokenBucket)
+)
+
+func limiterFor(merchantID string, rate float64, burst int) *tokenBucket {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	b, ok := limiters[merchantID]
+	if !ok || b.rate != rate || b.capacity != float64(burst) {
+		b = newTokenBucket(rate, burst)
+		limiters[merchantID] = b
+	}
+	return b
+}
+
+// WithRateLimit sets the per-merchant token bucket (requests/second and burst size).
+// A non-positive rate disables client-side rate limiting.
+func WithRateLimit(rate float64, burst int) Option {
+	return func(c *Client) {
+		c.rateLimit = rate
+		if burst > 0 {
+			c.rateBurst = burst
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package kaspi
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+// RetryPolicy controls how failed requests are retried; see marketplace.RetryPolicy
+type RetryPolicy = marketplace.RetryPolicy
+
+// DefaultRetryPolicy is used unless overridden with WithRetryPolicy
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+// WithRetryPolicy overrides the retry policy. MaxAttempts of 1 disables retries.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		if policy.MaxAttempts < 1 {
+			policy.MaxAttempts = 1
+		}
+		c.retry = policy
+	}
+}
+
+// CallStats describes a single logical API call, across all of its attempts
+type CallStats struct {
+	Method      string
+	Path        string
+	Attempts    int           // HTTP requests sent
+	Throttles   int           // 429 responses received
+	LimiterWait time.Duration // time spent waiting on the client-side rate limiter
+	StatusCode  int           // status of the last attempt, 0 on network error
+	Duration    time.Duration
+	Err         error
+}
+
+// Stats are cumulative counters for a Client
+type Stats = marketplace.ClientStats
+
+type statsCounters struct {
+	calls     atomic.Int64
+	attempts  atomic.Int64
+	throttles atomic.Int64
+	failures  atomic.Int64
+}
+
+func (s *statsCounters) record(call CallStats) {
+	s.calls.Add(1)
+	s.attempts.Add(int64(call.Attempts))
+	s.throttles.Add(int64(call.Throttles))
+	if call.Err != nil {
+		s.failures.Add(1)
+	}
+}
+
+// Stats returns cumulative call counters for this client
+func (c *Client) Stats() Stats {
+	calls := c.stats.calls.Load()
+	attempts := c.stats.attempts.Load()
+
+	return Stats{
+		Calls:     calls,
+		Attempts:  attempts,
+		Retries:   attempts - calls,
+		Throttles: c.stats.throttles.Load(),
+		Failures:  c.stats.failures.Load(),
+	}
+}
+
+// WithCallObserver registers a callback invoked after every API call with its counters
+func WithCallObserver(fn func(CallStats)) Option {
+	return func(c *Client) {
+		c.observer = fn
+	}
+}
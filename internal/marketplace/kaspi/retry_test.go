@@ -0,0 +1,178 @@
+package kaspi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/internal/marketplace/kaspi"
+	"github.com/yourusername/seller-assistant/internal/marketplace/kaspi/kaspitest"
+)
+
+// fastRetries retries quickly but still honors a Retry-After of a second
+var fastRetries = kaspi.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Second}
+
+func TestRetryAfterOn429(t *testing.T) {
+	srv, client := newTestServer(t, nil, kaspi.WithRetryPolicy(fastRetries))
+	srv.FailNext(1, http.StatusTooManyRequests, time.Second)
+
+	start := time.Now()
+	if _, err := client.GetCompetitorPrices(context.Background(), "100001", ""); err != nil {
+		t.Fatalf("GetCompetitorPrices: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least the 1s Retry-After", elapsed)
+	}
+	if n := srv.Requests(); n != 2 {
+		t.Errorf("server got %d requests, want 2", n)
+	}
+
+	stats := client.Stats()
+	if stats.Calls != 1 || stats.Retries != 1 || stats.Throttles != 1 || stats.Failures != 0 {
+		t.Errorf("stats = %+v, want 1 call with 1 retry and 1 throttle", stats)
+	}
+}
+
+func TestRetryAfterLongerThanMaxDelay(t *testing.T) {
+	srv, client := newTestServer(t, nil, kaspi.WithRetryPolicy(fastRetries))
+	srv.FailNext(1, http.StatusTooManyRequests, time.Minute)
+
+	start := time.Now()
+	_, err := client.GetCompetitorPrices(context.Background(), "100001", "")
+	if !errors.Is(err, marketplace.ErrRateLimited) {
+		t.Fatalf("err = %v, want ErrRateLimited", err)
+	}
+
+	// The client gives up instead of sleeping past MaxDelay
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("gave up after %v, want right away", elapsed)
+	}
+	if n := srv.Requests(); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+}
+
+func TestRetryOn5xx(t *testing.T) {
+	srv, client := newTestServer(t, nil, kaspi.WithRetryPolicy(fastRetries))
+	srv.FailNext(1, http.StatusServiceUnavailable, 0)
+	srv.FailNext(1, http.StatusBadGateway, 0)
+
+	if err := client.UpdateProductPrice(context.Background(), "100002", 379990); err != nil {
+		t.Fatalf("UpdateProductPrice: %v", err)
+	}
+
+	if n := srv.Requests(); n != 3 {
+		t.Errorf("server got %d requests, want 3", n)
+	}
+	if updates := srv.PriceUpdates(); len(updates) != 1 {
+		t.Errorf("got %d price updates, want 1", len(updates))
+	}
+}
+
+func TestNoRetryOn4xx(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusConflict} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			srv, client := newTestServer(t, nil, kaspi.WithRetryPolicy(fastRetries))
+			srv.FailNext(1, status, 0)
+
+			_, err := client.GetCompetitorPrices(context.Background(), "100001", "")
+
+			var apiErr *kaspi.APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != status {
+				t.Fatalf("err = %v, want an APIError with status %d", err, status)
+			}
+			if n := srv.Requests(); n != 1 {
+				t.Errorf("server got %d requests, want 1", n)
+			}
+		})
+	}
+}
+
+func TestNoRetryOn5xxForPost(t *testing.T) {
+	srv, client := newTestServer(t, nil, kaspi.WithRetryPolicy(fastRetries))
+	srv.FailNext(1, http.StatusServiceUnavailable, 0)
+
+	err := client.PostReviewResponse(context.Background(), "r-1", "Спасибо за отзыв!")
+	if !errors.Is(err, marketplace.ErrUnavailable) {
+		t.Fatalf("err = %v, want ErrUnavailable", err)
+	}
+	if n := srv.Requests(); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+
+	// 429 means the request was not processed, so even a POST is retried
+	srv.FailNext(1, http.StatusTooManyRequests, 0)
+	if err := client.PostReviewResponse(context.Background(), "r-1", "Спасибо за отзыв!"); err != nil {
+		t.Fatalf("PostReviewResponse after 429: %v", err)
+	}
+	if n := srv.Requests(); n != 3 {
+		t.Errorf("server got %d requests, want 3", n)
+	}
+}
+
+func TestRetryAttemptCap(t *testing.T) {
+	srv, client := newTestServer(t, nil, kaspi.WithRetryPolicy(fastRetries))
+	srv.FailNext(10, http.StatusInternalServerError, 0)
+
+	_, err := client.GetCompetitorPrices(context.Background(), "100001", "")
+	if !errors.Is(err, marketplace.ErrUnavailable) {
+		t.Fatalf("err = %v, want ErrUnavailable", err)
+	}
+
+	if n := srv.Requests(); n != fastRetries.MaxAttempts {
+		t.Errorf("server got %d requests, want %d", n, fastRetries.MaxAttempts)
+	}
+
+	stats := client.Stats()
+	if stats.Calls != 1 || stats.Attempts != int64(fastRetries.MaxAttempts) || stats.Failures != 1 {
+		t.Errorf("stats = %+v, want 1 failed call with %d attempts", stats, fastRetries.MaxAttempts)
+	}
+}
+
+func TestRetryStopsOnCancel(t *testing.T) {
+	srv, client := newTestServer(t, nil, kaspi.WithRetryPolicy(kaspi.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Minute}))
+	srv.FailNext(1, http.StatusServiceUnavailable, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetCompetitorPrices(ctx, "100001", "")
+	if !errors.Is(err, marketplace.ErrUnavailable) {
+		t.Fatalf("err = %v, want the last attempt's ErrUnavailable", err)
+	}
+	if n := srv.Requests(); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	fixtures := kaspitest.DefaultFixtures()
+	fixtures.MerchantID = "rate-limited-merchant" // Limiters are shared per merchant
+	srv := kaspitest.NewServer(fixtures)
+	defer srv.Close()
+
+	var waited time.Duration
+	client := srv.NewClient(
+		kaspi.WithRateLimit(20, 1),
+		kaspi.WithCallObserver(func(call kaspi.CallStats) { waited += call.LimiterWait }),
+	)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetCompetitorPrices(context.Background(), "100001", ""); err != nil {
+			t.Fatalf("GetCompetitorPrices: %v", err)
+		}
+	}
+
+	// A burst of 1 at 20 requests/second makes the 2nd and 3rd requests wait ~50ms each
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("3 requests took %v, want at least ~100ms", elapsed)
+	}
+	if waited < 90*time.Millisecond {
+		t.Errorf("limiter wait = %v, want at least ~100ms", waited)
+	}
+}
@@ -0,0 +1,43 @@
+package kaspi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+// UpdateStock sets a product's stock at one pickup point (Kaspi store ID, e.g. PP1)
+func (c *Client) UpdateStock(ctx context.Context, productExternalID, warehouseID string, quantity int) error {
+	if quantity < 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidStock, quantity)
+	}
+	if warehouseID == "" {
+		return fmt.Errorf("%w: pickup point is required", ErrInvalidStock)
+	}
+
+	url := fmt.Sprintf("%s/merchants/%s/products/%s/stock", c.baseURL, c.merchantID, productExternalID)
+
+	payload := map[string]interface{}{
+		"warehouse_id": warehouseID,
+		"stock":        quantity,
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.makeRequest(ctx, "PUT", url, payloadBytes)
+	if err != nil {
+		if errors.Is(err, marketplace.ErrNotFound) {
+			return fmt.Errorf("%w: %s: %w", ErrProductNotFound, productExternalID, err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
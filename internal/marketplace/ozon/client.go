@@ -0,0 +1,609 @@
+package ozon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+const (
+	// DefaultBaseURL is the production Ozon Seller API endpoint
+	DefaultBaseURL = "https://api-seller.ozon.ru"
+
+	// DefaultPageSize is the number of items requested per page
+	DefaultPageSize = 100
+
+	// DefaultMaxPages caps how many pages a single list call will follow
+	DefaultMaxPages = 500
+)
+
+// Client implements marketplace.MarketplaceClient for the Ozon Seller API.
+//
+// Products are identified by the seller's offer_id (ProductData.ExternalID), which
+// postings, stocks and price import all accept; Ozon's numeric product_id is kept
+// in ProductData.SKU.
+type Client struct {
+	clientID   string
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+	pageSize   int
+	maxPages   int
+	retry      RetryPolicy
+
+	calls    atomic.Int64
+	attempts atomic.Int64
+	throttle atomic.Int64
+	failures atomic.Int64
+}
+
+var (
+	_ marketplace.MarketplaceClient = (*Client)(nil)
+	_ marketplace.StatsReporter     = (*Client)(nil)
+)
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithBaseURL overrides the Ozon API base URL (e.g. to point at a local stub server)
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		if baseURL != "" {
+			c.baseURL = strings.TrimRight(baseURL, "/")
+		}
+	}
+}
+
+// WithHTTPClient replaces the default HTTP client (30s timeout)
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithPageSize sets the page size for list calls
+func WithPageSize(size int) Option {
+	return func(c *Client) {
+		if size > 0 {
+			c.pageSize = size
+		}
+	}
+}
+
+// WithMaxPages caps the number of pages followed by a single list call
+func WithMaxPages(maxPages int) Option {
+	return func(c *Client) {
+		if maxPages > 0 {
+			c.maxPages = maxPages
+		}
+	}
+}
+
+// NewClient creates a client for the seller identified by clientID (Client-Id header)
+func NewClient(clientID, apiKey string, opts ...Option) *Client {
+	c := &Client{
+		clientID: clientID,
+		apiKey:   apiKey,
+		baseURL:  DefaultBaseURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		pageSize: DefaultPageSize,
+		maxPages: DefaultMaxPages,
+		retry:    DefaultRetryPolicy,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *Client) GetProducts(ctx context.Context) ([]marketplace.ProductData, error) {
+	var products []marketplace.ProductData
+	err := c.ForEachProductPage(ctx, func(page []marketplace.ProductData) error {
+		products = append(products, page...)
+		return nil
+	})
+	return products, err
+}
+
+// ForEachProductPage streams the product catalog to fn one page at a time.
+// The list call only returns IDs, so each page is enriched via /v3/product/info/list.
+func (c *Client) ForEachProductPage(ctx context.Context, fn func([]marketplace.ProductData) error) error {
+	lastID := ""
+
+	for page := 0; ; page++ {
+		if page >= c.maxPages {
+			return fmt.Errorf("%w: stopped after %d pages of products", ErrPageLimitReached, page)
+		}
+
+		payload := map[string]interface{}{
+			"filter":  map[string]interface{}{"visibility": "ALL"},
+			"last_id": lastID,
+			"limit":   c.pageSize,
+		}
+
+		var response struct {
+			Result struct {
+				Items []struct {
+					ProductID int64  `json:"product_id"`
+					OfferID   string `json:"offer_id"`
+				} `json:"items"`
+				LastID string `json:"last_id"`
+			} `json:"result"`
+		}
+
+		if err := c.post(ctx, "/v3/product/list", payload, &response, true); err != nil {
+			return err
+		}
+
+		if len(response.Result.Items) == 0 {
+			return nil
+		}
+
+		ids := make([]int64, 0, len(response.Result.Items))
+		for _, item := range response.Result.Items {
+			ids = append(ids, item.ProductID)
+		}
+
+		infos, err := c.productInfo(ctx, map[string]interface{}{"product_id": ids})
+		if err != nil {
+			return fmt.Errorf("failed to get product info: %w", err)
+		}
+
+		stocks, err := c.warehouseStocks(ctx, infos)
+		if err != nil {
+			return fmt.Errorf("failed to get warehouse stocks: %w", err)
+		}
+
+		products := make([]marketplace.ProductData, 0, len(infos))
+		for _, info := range infos {
+			product := info.toProductData()
+			product.Stocks = stocks[info.ID]
+			products = append(products, product)
+		}
+
+		if err := fn(products); err != nil {
+			return err
+		}
+
+		if response.Result.LastID == "" || len(response.Result.Items) < c.pageSize {
+			return nil
+		}
+		lastID = response.Result.LastID
+	}
+}
+
+// productInfo is an item of /v3/product/info/list
+type productInfo struct {
+	ID           int64  `json:"id"`
+	OfferID      string `json:"offer_id"`
+	Name         string `json:"name"`
+	Price        string `json:"price"`
+	CurrencyCode string `json:"currency_code"`
+	Sources      []struct {
+		SKU int64 `json:"sku"`
+	} `json:"sources"`
+	Stocks struct {
+		Stocks []stock `json:"stocks"`
+	} `json:"stocks"`
+}
+
+// stock is the quantity of a product for one fulfilment scheme (fbo, fbs, rfbs)
+type stock struct {
+	Type     string `json:"type"`
+	Present  int    `json:"present"`
+	Reserved int    `json:"reserved"`
+}
+
+func (p productInfo) toProductData() marketplace.ProductData {
+	price, _ := strconv.ParseFloat(p.Price, 64)
+
+	return marketplace.ProductData{
+		ExternalID:   p.OfferID,
+		SKU:          strconv.FormatInt(p.ID, 10),
+		Name:         p.Name,
+		CurrentStock: available(p.Stocks.Stocks),
+		Price:        price,
+		Currency:     p.CurrencyCode,
+	}
+}
+
+// available sums sellable stock (present minus reserved) across fulfilment schemes
+func available(stocks []stock) int {
+	total := 0
+	for _, s := range stocks {
+		if n := s.Present - s.Reserved; n > 0 {
+			total += n
+		}
+	}
+	return total
+}
+
+// productInfo fetches product details filtered by product_id, offer_id or sku
+func (c *Client) productInfo(ctx context.Context, filter map[string]interface{}) ([]productInfo, error) {
+	var response struct {
+		Items []productInfo `json:"items"`
+	}
+
+	if err := c.post(ctx, "/v3/product/info/list", filter, &response, true); err != nil {
+		return nil, err
+	}
+
+	return response.Items, nil
+}
+
+func (c *Client) GetProductStock(ctx context.Context, externalID string) (int, error) {
+	var response struct {
+		Items []struct {
+			OfferID string  `json:"offer_id"`
+			Stocks  []stock `json:"stocks"`
+		} `json:"items"`
+	}
+
+	payload := map[string]interface{}{
+		"filter": map[string]interface{}{
+			"offer_id":   []string{externalID},
+			"visibility": "ALL",
+		},
+		"limit": 1,
+	}
+
+	if err := c.post(ctx, "/v4/product/info/stocks", payload, &response, true); err != nil {
+		return 0, err
+	}
+
+	if len(response.Items) == 0 {
+		return 0, fmt.Errorf("%w: %s", ErrProductNotFound, externalID)
+	}
+
+	return available(response.Items[0].Stocks), nil
+}
+
+func (c *Client) GetSalesData(ctx context.Context, startDate, endDate time.Time) ([]marketplace.SalesData, error) {
+	var salesData []marketplace.SalesData
+	err := c.ForEachSalesPage(ctx, startDate, endDate, func(page []marketplace.SalesData) error {
+		salesData = append(salesData, page...)
+		return nil
+	})
+	return salesData, err
+}
+
+// ForEachSalesPage streams postings (orders) for a date range to fn one page at a time.
+// Both FBS (seller warehouse) and FBO (Ozon warehouse) postings are included; cancelled
+// postings are skipped. Each posted product becomes one SalesData row.
+func (c *Client) ForEachSalesPage(ctx context.Context, startDate, endDate time.Time, fn func([]marketplace.SalesData) error) error {
+	return c.forEachPosting(ctx, startDate, endDate, func(postings []posting) error {
+		salesData := make([]marketplace.SalesData, 0, len(postings))
+		for _, p := range postings {
+			if p.Status == "cancelled" {
+				continue
+			}
+
+			for _, item := range p.Products {
+				price, _ := strconv.ParseFloat(item.Price, 64)
+				salesData = append(salesData, marketplace.SalesData{
+					ProductExternalID: item.OfferID,
+					Date:              p.InProcessAt,
+					QuantitySold:      item.Quantity,
+					Revenue:           price * float64(item.Quantity),
+				})
+			}
+		}
+
+		if len(salesData) == 0 {
+			return nil
+		}
+		return fn(salesData)
+	})
+}
+
+// posting is the part of an FBS/FBO posting used for sales and orders
+type posting struct {
+	PostingNumber string    `json:"posting_number"`
+	Status        string    `json:"status"`
+	InProcessAt   time.Time `json:"in_process_at"`
+	AnalyticsData struct {
+		City string `json:"city"`
+	} `json:"analytics_data"`
+	DeliveryMethod struct {
+		WarehouseID int64 `json:"warehouse_id"`
+	} `json:"delivery_method"` // FBS only
+	Products []struct {
+		OfferID  string `json:"offer_id"`
+		Name     string `json:"name"`
+		Quantity int    `json:"quantity"`
+		Price    string `json:"price"`
+	} `json:"products"`
+
+	scheme string // fbs or fbo, set from the endpoint the posting came from
+}
+
+// forEachPosting pages through FBS and then FBO postings processed within the date range
+func (c *Client) forEachPosting(ctx context.Context, startDate, endDate time.Time, fn func([]posting) error) error {
+	if err := c.forEachPostingPage(ctx, "/v3/posting/fbs/list", "fbs", startDate, endDate, fn); err != nil {
+		return fmt.Errorf("failed to list FBS postings: %w", err)
+	}
+
+	if err := c.forEachPostingPage(ctx, "/v2/posting/fbo/list", "fbo", startDate, endDate, fn); err != nil {
+		return fmt.Errorf("failed to list FBO postings: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) forEachPostingPage(ctx context.Context, path, scheme string, startDate, endDate time.Time, fn func([]posting) error) error {
+	for page := 0; ; page++ {
+		if page >= c.maxPages {
+			return fmt.Errorf("%w: stopped after %d pages of %s", ErrPageLimitReached, page, path)
+		}
+
+		payload := map[string]interface{}{
+			"dir": "ASC",
+			"filter": map[string]interface{}{
+				"since": startDate.UTC().Format(time.RFC3339),
+				"to":    endDate.UTC().Format(time.RFC3339),
+			},
+			"limit":  c.pageSize,
+			"offset": page * c.pageSize,
+			"with": map[string]bool{
+				"analytics_data": true,
+			},
+		}
+
+		// FBS wraps postings in an object with has_next; FBO returns a bare array
+		var response struct {
+			Result json.RawMessage `json:"result"`
+		}
+
+		if err := c.post(ctx, path, payload, &response, true); err != nil {
+			return err
+		}
+
+		postings, hasNext, err := decodePostings(response.Result, c.pageSize)
+		if err != nil {
+			return err
+		}
+
+		if len(postings) == 0 {
+			return nil
+		}
+
+		for i := range postings {
+			postings[i].scheme = scheme
+		}
+
+		if err := fn(postings); err != nil {
+			return err
+		}
+
+		if !hasNext {
+			return nil
+		}
+	}
+}
+
+func decodePostings(raw json.RawMessage, pageSize int) ([]posting, bool, error) {
+	var wrapped struct {
+		Postings []posting `json:"postings"`
+		HasNext  bool      `json:"has_next"`
+	}
+	if err := json.Unmarshal(raw, &wrapped); err == nil {
+		return wrapped.Postings, wrapped.HasNext, nil
+	}
+
+	var postings []posting
+	if err := json.Unmarshal(raw, &postings); err != nil {
+		return nil, false, decodeError(err)
+	}
+
+	return postings, len(postings) == pageSize, nil
+}
+
+func (c *Client) GetReviews(ctx context.Context) ([]marketplace.ReviewData, error) {
+	var reviews []marketplace.ReviewData
+	err := c.ForEachReviewPage(ctx, func(page []marketplace.ReviewData) error {
+		reviews = append(reviews, page...)
+		return nil
+	})
+	return reviews, err
+}
+
+// ForEachReviewPage streams unprocessed reviews to fn one page at a time.
+// Reviews reference Ozon's SKU, which is resolved to offer_id per page.
+func (c *Client) ForEachReviewPage(ctx context.Context, fn func([]marketplace.ReviewData) error) error {
+	type review struct {
+		ID          string    `json:"id"`
+		SKU         int64     `json:"sku"`
+		Text        string    `json:"text"`
+		Rating      int       `json:"rating"`
+		PublishedAt time.Time `json:"published_at"`
+	}
+
+	// The review list only accepts limits between 20 and 100
+	limit := c.pageSize
+	if limit < 20 {
+		limit = 20
+	} else if limit > 100 {
+		limit = 100
+	}
+
+	lastID := ""
+
+	for page := 0; ; page++ {
+		if page >= c.maxPages {
+			return fmt.Errorf("%w: stopped after %d pages of reviews", ErrPageLimitReached, page)
+		}
+
+		payload := map[string]interface{}{
+			"last_id":  lastID,
+			"limit":    limit,
+			"sort_dir": "DESC",
+			"status":   "UNPROCESSED",
+		}
+
+		var response struct {
+			Reviews []review `json:"reviews"`
+			HasNext bool     `json:"has_next"`
+			LastID  string   `json:"last_id"`
+		}
+
+		if err := c.post(ctx, "/v1/review/list", payload, &response, true); err != nil {
+			return err
+		}
+
+		if len(response.Reviews) == 0 {
+			return nil
+		}
+
+		skus := make([]int64, 0, len(response.Reviews))
+		for _, r := range response.Reviews {
+			skus = append(skus, r.SKU)
+		}
+
+		offerIDs, err := c.offerIDsBySKU(ctx, skus)
+		if err != nil {
+			return fmt.Errorf("failed to resolve review SKUs: %w", err)
+		}
+
+		reviews := make([]marketplace.ReviewData, 0, len(response.Reviews))
+		for _, r := range response.Reviews {
+			reviews = append(reviews, marketplace.ReviewData{
+				ExternalID: r.ID,
+				ProductID:  offerIDs[r.SKU],
+				Rating:     r.Rating,
+				Comment:    r.Text,
+				Language:   "ru",
+				CreatedAt:  r.PublishedAt,
+			})
+		}
+
+		if err := fn(reviews); err != nil {
+			return err
+		}
+
+		if !response.HasNext || response.LastID == "" {
+			return nil
+		}
+		lastID = response.LastID
+	}
+}
+
+func (c *Client) offerIDsBySKU(ctx context.Context, skus []int64) (map[int64]string, error) {
+	infos, err := c.productInfo(ctx, map[string]interface{}{"sku": skus})
+	if err != nil {
+		return nil, err
+	}
+
+	offerIDs := make(map[int64]string, len(skus))
+	for _, info := range infos {
+		for _, src := range info.Sources {
+			offerIDs[src.SKU] = info.OfferID
+		}
+	}
+
+	return offerIDs, nil
+}
+
+func (c *Client) PostReviewResponse(ctx context.Context, reviewID, response string) error {
+	payload := map[string]interface{}{
+		"review_id":                reviewID,
+		"text":                     response,
+		"mark_review_as_processed": true,
+	}
+
+	// Creating a comment is not idempotent: a retried request could post it twice
+	return c.post(ctx, "/v1/review/comment/create", payload, nil, false)
+}
+
+// post sends payload to path and decodes the response into out (if not nil).
+// Ozon uses POST for reads too, so the caller says whether the call is safe to retry.
+func (c *Client) post(ctx context.Context, path string, payload, out interface{}, idempotent bool) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.makeRequest(ctx, c.baseURL+path, body, idempotent)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return decodeError(err)
+	}
+
+	return nil
+}
+
+// makeRequest sends a POST request and retries it according to the retry policy.
+// Non-2xx responses are returned as *APIError.
+func (c *Client) makeRequest(ctx context.Context, url string, body []byte, idempotent bool) (*http.Response, error) {
+	c.calls.Add(1)
+
+	resp, err := c.doWithRetry(ctx, url, body, idempotent)
+	if err != nil {
+		c.failures.Add(1)
+	}
+
+	return resp, err
+}
+
+func (c *Client) doWithRetry(ctx context.Context, url string, body []byte, idempotent bool) (*http.Response, error) {
+	return marketplace.DoWithRetry(ctx, c.httpClient, marketplace.RetryRequest{
+		Marketplace: marketplaceName,
+		Policy:      c.retry,
+		Idempotent:  idempotent,
+		NewRequest: func(ctx context.Context) (*http.Request, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("Client-Id", c.clientID)
+			req.Header.Set("Api-Key", c.apiKey)
+			req.Header.Set("Content-Type", "application/json")
+			return req, nil
+		},
+		AfterAttempt: c.countAttempt,
+	})
+}
+
+// countAttempt counts one HTTP request and whether it was throttled
+func (c *Client) countAttempt(status int) {
+	c.attempts.Add(1)
+	if status == http.StatusTooManyRequests {
+		c.throttle.Add(1)
+	}
+}
+
+// Stats returns cumulative call counters for this client
+func (c *Client) Stats() marketplace.ClientStats {
+	calls := c.calls.Load()
+	attempts := c.attempts.Load()
+
+	return marketplace.ClientStats{
+		Calls:     calls,
+		Attempts:  attempts,
+		Retries:   attempts - calls,
+		Throttles: c.throttle.Load(),
+		Failures:  c.failures.Load(),
+	}
+}
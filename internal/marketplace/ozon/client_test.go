@@ -0,0 +1,244 @@
+package ozon_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/internal/marketplace/ozon"
+	"github.com/yourusername/seller-assistant/internal/marketplace/ozon/ozontest"
+)
+
+// newTestServer starts a stub Ozon server for the test. Its clients make a single
+// attempt unless opts say otherwise.
+func newTestServer(t *testing.T, fixtures *ozontest.Fixtures, opts ...ozon.Option) (*ozontest.Server, *ozon.Client) {
+	t.Helper()
+
+	srv := ozontest.NewServer(fixtures)
+	t.Cleanup(srv.Close)
+
+	opts = append([]ozon.Option{
+		ozon.WithRetryPolicy(ozon.RetryPolicy{MaxAttempts: 1}),
+	}, opts...)
+
+	return srv, srv.NewClient(opts...)
+}
+
+func TestGetProducts(t *testing.T) {
+	_, client := newTestServer(t, nil)
+
+	products, err := client.GetProducts(context.Background())
+	if err != nil {
+		t.Fatalf("GetProducts: %v", err)
+	}
+	if len(products) != 3 {
+		t.Fatalf("got %d products, want 3", len(products))
+	}
+
+	// ExternalID is the offer_id, SKU keeps Ozon's product_id
+	p := products[0]
+	if p.ExternalID != "KTL-STEEL-17" || p.SKU != "700001" || p.Price != 15990 || p.Currency != "KZT" {
+		t.Errorf("first product = %+v", p)
+	}
+
+	// Sellable stock: 20-2 at FBO plus 5 and 1 at the two FBS warehouses
+	if p.CurrentStock != 24 {
+		t.Errorf("first product stock = %d, want 24", p.CurrentStock)
+	}
+	want := []marketplace.WarehouseStock{
+		{WarehouseID: "22000001", Name: "Основной склад", Quantity: 5},
+		{WarehouseID: "22000002", Name: "Склад Казань", Quantity: 1},
+		{WarehouseID: ozon.FBOWarehouseID, Name: "Ozon FBO", Quantity: 18},
+	}
+	if len(p.Stocks) != len(want) {
+		t.Fatalf("first product stocks = %+v, want %+v", p.Stocks, want)
+	}
+	for i := range want {
+		if p.Stocks[i] != want[i] {
+			t.Errorf("stock %d = %+v, want %+v", i, p.Stocks[i], want[i])
+		}
+	}
+
+	if p := products[2]; p.ExternalID != "TST-2SL-WHT" || p.CurrentStock != 0 || len(p.Stocks) != 0 {
+		t.Errorf("product without stock = %+v", p)
+	}
+}
+
+func TestProductPagesByLastID(t *testing.T) {
+	_, client := newTestServer(t, nil, ozon.WithPageSize(1))
+
+	var pages [][]marketplace.ProductData
+	err := client.ForEachProductPage(context.Background(), func(page []marketplace.ProductData) error {
+		pages = append(pages, page)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachProductPage: %v", err)
+	}
+
+	want := []string{"KTL-STEEL-17", "BLN-PRO-800", "TST-2SL-WHT"}
+	if len(pages) != len(want) {
+		t.Fatalf("got %d pages, want %d", len(pages), len(want))
+	}
+	for i, page := range pages {
+		if len(page) != 1 || page[0].ExternalID != want[i] {
+			t.Errorf("page %d = %+v, want product %s", i+1, page, want[i])
+		}
+	}
+
+	// Each page is enriched on its own, so later pages keep their stock
+	if p := pages[1][0]; p.CurrentStock != 2 || len(p.Stocks) != 1 || p.Stocks[0].Quantity != 2 {
+		t.Errorf("second product = %+v", p)
+	}
+}
+
+func TestPageLimitReached(t *testing.T) {
+	_, client := newTestServer(t, nil, ozon.WithPageSize(1), ozon.WithMaxPages(2))
+
+	var got []string
+	err := client.ForEachProductPage(context.Background(), func(page []marketplace.ProductData) error {
+		for _, p := range page {
+			got = append(got, p.ExternalID)
+		}
+		return nil
+	})
+	if !errors.Is(err, ozon.ErrPageLimitReached) {
+		t.Fatalf("err = %v, want ErrPageLimitReached", err)
+	}
+
+	// Pages before the limit are delivered
+	if len(got) != 2 || got[0] != "KTL-STEEL-17" || got[1] != "BLN-PRO-800" {
+		t.Errorf("delivered %v, want the first two products", got)
+	}
+}
+
+func TestGetProductStock(t *testing.T) {
+	_, client := newTestServer(t, nil)
+	ctx := context.Background()
+
+	stock, err := client.GetProductStock(ctx, "KTL-STEEL-17")
+	if err != nil {
+		t.Fatalf("GetProductStock: %v", err)
+	}
+	if stock != 24 {
+		t.Errorf("stock = %d, want 24", stock)
+	}
+
+	_, err = client.GetProductStock(ctx, "NO-SUCH-OFFER")
+	if !errors.Is(err, ozon.ErrProductNotFound) || !errors.Is(err, marketplace.ErrNotFound) {
+		t.Errorf("unknown offer: err = %v, want ErrProductNotFound and ErrNotFound", err)
+	}
+}
+
+func TestReviews(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+	ctx := context.Background()
+
+	reviews, err := client.GetReviews(ctx)
+	if err != nil {
+		t.Fatalf("GetReviews: %v", err)
+	}
+	if len(reviews) != 2 {
+		t.Fatalf("got %d reviews, want 2", len(reviews))
+	}
+
+	// Newest first, with the review's SKU resolved to our offer_id
+	r := reviews[0]
+	if r.ExternalID != "rv-2" || r.ProductID != "BLN-PRO-800" || r.Rating != 1 || r.Language != "ru" {
+		t.Errorf("first review = %+v", r)
+	}
+
+	if err := client.PostReviewResponse(ctx, "rv-2", "Заменим насадку по гарантии"); err != nil {
+		t.Fatalf("PostReviewResponse: %v", err)
+	}
+	if comment := srv.ReviewComments()["rv-2"]; comment != "Заменим насадку по гарантии" {
+		t.Errorf("comment on rv-2 = %q", comment)
+	}
+
+	// Commented reviews are processed and no longer listed
+	reviews, err = client.GetReviews(ctx)
+	if err != nil {
+		t.Fatalf("GetReviews: %v", err)
+	}
+	if len(reviews) != 1 || reviews[0].ExternalID != "rv-1" {
+		t.Errorf("reviews after comment = %+v, want only rv-1", reviews)
+	}
+
+	err = client.PostReviewResponse(ctx, "rv-404", "Спасибо!")
+	if !errors.Is(err, marketplace.ErrNotFound) {
+		t.Errorf("unknown review: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestNotSupported(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+	ctx := context.Background()
+
+	_, err := client.GetCompetitorPrices(ctx, "KTL-STEEL-17", "")
+	if !errors.Is(err, marketplace.ErrNotSupported) {
+		t.Errorf("GetCompetitorPrices: err = %v, want ErrNotSupported", err)
+	}
+	if err := client.UpdateCityPrice(ctx, "KTL-STEEL-17", "Москва", 14990); !errors.Is(err, marketplace.ErrNotSupported) {
+		t.Errorf("UpdateCityPrice: err = %v, want ErrNotSupported", err)
+	}
+	if err := client.AcceptOrder(ctx, "FBS-NEW-1"); !errors.Is(err, marketplace.ErrNotSupported) {
+		t.Errorf("AcceptOrder: err = %v, want ErrNotSupported", err)
+	}
+
+	if n := srv.Requests(); n != 0 {
+		t.Errorf("unsupported calls sent %d requests, want 0", n)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items": [{"offer_id": 1`))
+	}))
+	defer srv.Close()
+
+	client := ozon.NewClient("client", "key", ozon.WithBaseURL(srv.URL))
+
+	_, err := client.GetProductStock(context.Background(), "KTL-STEEL-17")
+	if !errors.Is(err, marketplace.ErrDecode) {
+		t.Errorf("GetProductStock: err = %v, want ErrDecode", err)
+	}
+}
+
+func TestAuthErrors(t *testing.T) {
+	fixtures := ozontest.DefaultFixtures()
+	fixtures.APIKey = "secret"
+	srv := ozontest.NewServer(fixtures)
+	defer srv.Close()
+
+	opts := []ozon.Option{ozon.WithBaseURL(srv.URL)}
+	ctx := context.Background()
+
+	// Ozon answers 401 for a wrong key and for an unknown Client-Id alike
+	_, err := ozon.NewClient(srv.ClientID(), "wrong-key", opts...).GetProductStock(ctx, "KTL-STEEL-17")
+	if !errors.Is(err, marketplace.ErrUnauthorized) || !marketplace.IsAuthError(err) {
+		t.Errorf("wrong key: err = %v, want an auth error", err)
+	}
+
+	_, err = ozon.NewClient("other-client", "secret", opts...).GetProductStock(ctx, "KTL-STEEL-17")
+	if !errors.Is(err, marketplace.ErrUnauthorized) {
+		t.Errorf("other client: err = %v, want ErrUnauthorized", err)
+	}
+
+	var apiErr *ozon.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.Marketplace != "ozon" {
+		t.Errorf("other client: err = %v, want an ozon APIError with status 401", err)
+	}
+
+	// Auth errors are final, so they are not retried
+	if n := srv.Requests(); n != 2 {
+		t.Errorf("server got %d requests, want 2", n)
+	}
+
+	if _, err := ozon.NewClient(srv.ClientID(), "secret", opts...).GetProductStock(ctx, "KTL-STEEL-17"); err != nil {
+		t.Errorf("right key: %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package ozon
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+var (
+	// ErrProductNotFound is returned when Ozon does not know the requested offer_id.
+	// Errors wrapping it also match marketplace.ErrNotFound.
+	ErrProductNotFound = fmt.Errorf("ozon: product not found: %w", marketplace.ErrNotFound)
+
+	// ErrInvalidPrice is returned when a price update is attempted with a non-positive price
+	ErrInvalidPrice = errors.New("ozon: invalid price")
+
+	// ErrInvalidStock is returned when a stock update has a negative quantity or a malformed warehouse ID
+	ErrInvalidStock = errors.New("ozon: invalid stock")
+
+	// ErrOrderNotFound is returned when Ozon does not know the posting or it is not an FBS posting.
+	// Errors wrapping it also match marketplace.ErrNotFound.
+	ErrOrderNotFound = fmt.Errorf("ozon: posting not found: %w", marketplace.ErrNotFound)
+
+	// ErrInvalidCancelReason is returned when CancelOrder gets a reason Ozon has no code for
+	ErrInvalidCancelReason = errors.New("ozon: invalid cancel reason")
+
+	// ErrPageLimitReached is returned when a list call still had pages left after MaxPages.
+	// Pages fetched before the limit have already been delivered to the caller.
+	ErrPageLimitReached = errors.New("ozon: page limit reached")
+)
+
+// APIError is returned for any non-2xx response from the Ozon API
+type APIError = marketplace.APIError
+
+const marketplaceName = "ozon"
+
+func decodeError(err error) error {
+	return fmt.Errorf("failed to decode response: %w: %v", marketplace.ErrDecode, err)
+}
@@ -0,0 +1,13 @@
+package ozon
+
+import (
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+// NewFactory returns a marketplace.Factory that builds Ozon clients with the given options.
+// Credentials.MerchantID carries the Ozon Client-Id and Credentials.APIKey the Api-Key.
+func NewFactory(opts ...Option) marketplace.Factory {
+	return func(creds marketplace.Credentials) (marketplace.MarketplaceClient, error) {
+		return NewClient(creds.MerchantID, creds.APIKey, opts...), nil
+	}
+}
@@ -0,0 +1,142 @@
+package ozon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+// postingStatuses maps Ozon posting statuses onto marketplace.OrderStatus values
+var postingStatuses = map[string]string{
+	"acceptance_in_progress": marketplace.OrderStatusNew,
+	"awaiting_registration":  marketplace.OrderStatusNew,
+	"awaiting_approve":       marketplace.OrderStatusNew,
+	"awaiting_packaging":     marketplace.OrderStatusAccepted,
+	"awaiting_deliver":       marketplace.OrderStatusReady,
+	"driver_pickup":          marketplace.OrderStatusReady,
+	"delivering":             marketplace.OrderStatusReady,
+	"delivered":              marketplace.OrderStatusDelivered,
+	"cancelled":              marketplace.OrderStatusCancelled,
+	"not_accepted":           marketplace.OrderStatusCancelled,
+}
+
+// mapPostingStatus converts an Ozon status; unknown statuses are passed through lower-cased
+func mapPostingStatus(status string) string {
+	if mapped, ok := postingStatuses[status]; ok {
+		return mapped
+	}
+	return strings.ToLower(status)
+}
+
+func (c *Client) GetOrders(ctx context.Context, since time.Time) ([]marketplace.OrderData, error) {
+	var orders []marketplace.OrderData
+	err := c.ForEachOrderPage(ctx, since, func(page []marketplace.OrderData) error {
+		orders = append(orders, page...)
+		return nil
+	})
+	return orders, err
+}
+
+// ForEachOrderPage streams FBS and FBO postings to fn one page at a time. Ozon filters
+// postings by processing date only, so "since" selects postings that entered processing
+// after it; later status changes of older postings are not picked up.
+func (c *Client) ForEachOrderPage(ctx context.Context, since time.Time, fn func([]marketplace.OrderData) error) error {
+	return c.forEachPosting(ctx, since, time.Now(), func(postings []posting) error {
+		orders := make([]marketplace.OrderData, 0, len(postings))
+		for _, p := range postings {
+			order := marketplace.OrderData{
+				ExternalID:   p.PostingNumber,
+				Status:       mapPostingStatus(p.Status),
+				CustomerCity: p.AnalyticsData.City,
+				DeliveryType: p.scheme,
+				WarehouseID:  p.warehouseID(),
+				CreatedAt:    p.InProcessAt,
+				UpdatedAt:    p.InProcessAt,
+			}
+
+			for _, item := range p.Products {
+				price, _ := strconv.ParseFloat(item.Price, 64)
+				order.Lines = append(order.Lines, marketplace.OrderLineData{
+					ProductExternalID: item.OfferID,
+					Name:              item.Name,
+					Quantity:          item.Quantity,
+					Price:             price,
+				})
+				order.TotalPrice += price * float64(item.Quantity)
+			}
+
+			orders = append(orders, order)
+		}
+		return fn(orders)
+	})
+}
+
+// warehouseID is the FBS warehouse the posting ships from, or FBOWarehouseID
+func (p posting) warehouseID() string {
+	if p.scheme == "fbo" {
+		return FBOWarehouseID
+	}
+	if p.DeliveryMethod.WarehouseID == 0 {
+		return ""
+	}
+	return strconv.FormatInt(p.DeliveryMethod.WarehouseID, 10)
+}
+
+// cancelReasonIDs maps marketplace.CancelReason values onto Ozon cancel_reason_id values.
+// Ozon has no dedicated codes for most of them, so they go out as "other" with the reason as the message.
+var cancelReasonIDs = map[string]int{
+	marketplace.CancelReasonOutOfStock:          352,
+	marketplace.CancelReasonCustomerRequest:     402,
+	marketplace.CancelReasonCustomerUnreachable: 402,
+	marketplace.CancelReasonWrongPrice:          402,
+	marketplace.CancelReasonOther:               402,
+}
+
+// AcceptOrder is not supported: Ozon confirms postings itself and hands them to the
+// seller already in awaiting_packaging
+func (c *Client) AcceptOrder(ctx context.Context, externalID string) error {
+	return fmt.Errorf("ozon: accept order: %w", marketplace.ErrNotSupported)
+}
+
+// MarkOrderReady moves a packed FBS posting to awaiting_deliver. FBO postings are
+// assembled by Ozon and cannot be changed by the seller.
+func (c *Client) MarkOrderReady(ctx context.Context, externalID string) error {
+	payload := map[string]interface{}{
+		"posting_number": []string{externalID},
+	}
+
+	return c.postingAction(ctx, "/v2/posting/fbs/awaiting-delivery", externalID, payload)
+}
+
+// CancelOrder cancels an FBS posting with one of the marketplace.CancelReason codes
+func (c *Client) CancelOrder(ctx context.Context, externalID, reason string) error {
+	reasonID, ok := cancelReasonIDs[reason]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrInvalidCancelReason, reason)
+	}
+
+	payload := map[string]interface{}{
+		"posting_number":        externalID,
+		"cancel_reason_id":      reasonID,
+		"cancel_reason_message": reason,
+	}
+
+	return c.postingAction(ctx, "/v2/posting/fbs/cancel", externalID, payload)
+}
+
+// postingAction changes a posting's state; such calls are not retried since a repeat fails with a conflict
+func (c *Client) postingAction(ctx context.Context, path, postingNumber string, payload interface{}) error {
+	if err := c.post(ctx, path, payload, nil, false); err != nil {
+		if errors.Is(err, marketplace.ErrNotFound) {
+			return fmt.Errorf("%w: %s", ErrOrderNotFound, postingNumber)
+		}
+		return err
+	}
+
+	return nil
+}
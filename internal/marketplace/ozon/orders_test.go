@@ -0,0 +1,155 @@
+package ozon_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/internal/marketplace/ozon"
+)
+
+func TestGetSalesData(t *testing.T) {
+	_, client := newTestServer(t, nil)
+
+	// Three and two days ago: FBS kettles (2 and 1), one FBO blender and a cancelled toaster
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	sales, err := client.GetSalesData(context.Background(), today.AddDate(0, 0, -3), today.AddDate(0, 0, -1))
+	if err != nil {
+		t.Fatalf("GetSalesData: %v", err)
+	}
+
+	units := make(map[string]int)
+	revenue := make(map[string]float64)
+	for _, s := range sales {
+		units[s.ProductExternalID] += s.QuantitySold
+		revenue[s.ProductExternalID] += s.Revenue
+	}
+
+	if len(sales) != 3 {
+		t.Errorf("got %d sales rows, want 3: %+v", len(sales), sales)
+	}
+	if units["KTL-STEEL-17"] != 3 || revenue["KTL-STEEL-17"] != 47970 {
+		t.Errorf("kettles = %d units for %v, want 3 for 47970", units["KTL-STEEL-17"], revenue["KTL-STEEL-17"])
+	}
+	if units["BLN-PRO-800"] != 1 || revenue["BLN-PRO-800"] != 22490 {
+		t.Errorf("blenders = %d units for %v, want 1 for 22490", units["BLN-PRO-800"], revenue["BLN-PRO-800"])
+	}
+	if _, ok := units["TST-2SL-WHT"]; ok {
+		t.Error("cancelled posting counted as a sale")
+	}
+}
+
+func TestGetOrders(t *testing.T) {
+	_, client := newTestServer(t, nil, ozon.WithPageSize(2))
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	orders, err := client.GetOrders(context.Background(), today.AddDate(0, 0, -4))
+	if err != nil {
+		t.Fatalf("GetOrders: %v", err)
+	}
+
+	byID := make(map[string]marketplace.OrderData, len(orders))
+	for _, o := range orders {
+		if _, ok := byID[o.ExternalID]; ok {
+			t.Errorf("posting %s returned twice", o.ExternalID)
+		}
+		byID[o.ExternalID] = o
+	}
+
+	fbs := byID["FBS-0001-1"]
+	if fbs.Status != marketplace.OrderStatusDelivered || fbs.DeliveryType != "fbs" || fbs.WarehouseID != "22000002" ||
+		fbs.CustomerCity != "Казань" || fbs.TotalPrice != 31980 {
+		t.Errorf("FBS-0001-1 = %+v", fbs)
+	}
+	if len(fbs.Lines) != 1 || fbs.Lines[0] != (marketplace.OrderLineData{ProductExternalID: "KTL-STEEL-17", Name: "Электрочайник стальной 1.7 л", Quantity: 2, Price: 15990}) {
+		t.Errorf("FBS-0001-1 lines = %+v", fbs.Lines)
+	}
+
+	if fbo := byID["FBO-0003-1"]; fbo.DeliveryType != "fbo" || fbo.WarehouseID != ozon.FBOWarehouseID || fbo.TotalPrice != 22490 {
+		t.Errorf("FBO-0003-1 = %+v", fbo)
+	}
+	if o := byID["FBS-NEW-1"]; o.Status != marketplace.OrderStatusAccepted {
+		t.Errorf("FBS-NEW-1 status = %q, want accepted", o.Status)
+	}
+	if o := byID["FBS-CANCEL-1"]; o.Status != marketplace.OrderStatusCancelled || o.WarehouseID != "22000001" {
+		t.Errorf("FBS-CANCEL-1 = %+v", o)
+	}
+
+	// Postings that entered processing before "since" are left out
+	if _, ok := byID["FBS-0005-1"]; ok {
+		t.Error("FBS-0005-1 is older than since but was returned")
+	}
+}
+
+func TestMarkOrderReady(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+	ctx := context.Background()
+
+	if err := client.MarkOrderReady(ctx, "FBS-NEW-1"); err != nil {
+		t.Fatalf("MarkOrderReady: %v", err)
+	}
+
+	actions := srv.PostingActions()
+	if len(actions) != 1 || actions[0].PostingNumber != "FBS-NEW-1" || actions[0].Action != "awaiting-delivery" {
+		t.Fatalf("posting actions = %+v", actions)
+	}
+
+	// A repeat conflicts with the posting's new state
+	if err := client.MarkOrderReady(ctx, "FBS-NEW-1"); !errors.Is(err, marketplace.ErrConflict) {
+		t.Errorf("repeat: err = %v, want ErrConflict", err)
+	}
+
+	// FBO postings are Ozon's to assemble
+	err := client.MarkOrderReady(ctx, "FBO-0000-1")
+	if !errors.Is(err, ozon.ErrOrderNotFound) || !errors.Is(err, marketplace.ErrNotFound) {
+		t.Errorf("FBO posting: err = %v, want ErrOrderNotFound and ErrNotFound", err)
+	}
+}
+
+func TestCancelOrder(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+	ctx := context.Background()
+
+	if err := client.CancelOrder(ctx, "FBS-NEW-1", marketplace.CancelReasonOutOfStock); err != nil {
+		t.Fatalf("CancelOrder: %v", err)
+	}
+
+	actions := srv.PostingActions()
+	if len(actions) != 1 || actions[0].Action != "cancel" || actions[0].ReasonID != 352 {
+		t.Fatalf("posting actions = %+v, want a cancel with reason 352", actions)
+	}
+
+	if err := client.CancelOrder(ctx, "FBS-0001-1", marketplace.CancelReasonOther); !errors.Is(err, marketplace.ErrConflict) {
+		t.Errorf("delivered posting: err = %v, want ErrConflict", err)
+	}
+	if err := client.CancelOrder(ctx, "FBS-9999-1", marketplace.CancelReasonOther); !errors.Is(err, ozon.ErrOrderNotFound) {
+		t.Errorf("unknown posting: err = %v, want ErrOrderNotFound", err)
+	}
+
+	requests := srv.Requests()
+	if err := client.CancelOrder(ctx, "FBS-NEW-1", "changed my mind"); !errors.Is(err, ozon.ErrInvalidCancelReason) {
+		t.Errorf("unknown reason: err = %v, want ErrInvalidCancelReason", err)
+	}
+	if n := srv.Requests(); n != requests {
+		t.Errorf("unknown reason sent %d requests, want 0", n-requests)
+	}
+}
+
+func TestPostingActionsAreNotRetried(t *testing.T) {
+	srv, client := newTestServer(t, nil, ozon.WithRetryPolicy(fastRetries))
+	srv.FailNext(1, http.StatusServiceUnavailable, 0)
+
+	err := client.MarkOrderReady(context.Background(), "FBS-NEW-1")
+	if !errors.Is(err, marketplace.ErrUnavailable) {
+		t.Fatalf("err = %v, want ErrUnavailable", err)
+	}
+	if n := srv.Requests(); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+	if actions := srv.PostingActions(); len(actions) != 0 {
+		t.Errorf("posting actions = %+v, want none", actions)
+	}
+}
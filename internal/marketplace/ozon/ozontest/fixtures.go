@@ -0,0 +1,138 @@
+package ozontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Stock is a product's stock for one fulfilment scheme ("fbo", "fbs" or "rfbs").
+// FBS stock names its warehouse; zero means the first fixture warehouse.
+type Stock struct {
+	Type        string `json:"type"`
+	Present     int    `json:"present"`
+	Reserved    int    `json:"reserved"`
+	WarehouseID int64  `json:"warehouse_id,omitempty"`
+}
+
+// Product is a catalog entry served by the stub Seller API
+type Product struct {
+	ProductID int64   `json:"product_id"`
+	OfferID   string  `json:"offer_id"`
+	SKU       int64   `json:"sku"` // Ozon's storefront SKU, referenced by reviews
+	Name      string  `json:"name"`
+	Price     float64 `json:"price"`
+	Currency  string  `json:"currency"`
+	Stocks    []Stock `json:"stocks"`
+}
+
+// PostingProduct is one line of a posting
+type PostingProduct struct {
+	OfferID  string  `json:"offer_id"`
+	Name     string  `json:"name"`
+	Quantity int     `json:"quantity"`
+	Price    float64 `json:"price"`
+}
+
+// Posting is an order shipment, fulfilled by the seller ("fbs") or by Ozon ("fbo")
+type Posting struct {
+	PostingNumber string           `json:"posting_number"`
+	Scheme        string           `json:"scheme"`
+	Status        string           `json:"status"`
+	City          string           `json:"city"`
+	WarehouseID   int64            `json:"warehouse_id,omitempty"` // FBS warehouse; zero means the first fixture warehouse
+	InProcessAt   time.Time        `json:"in_process_at"`
+	Products      []PostingProduct `json:"products"`
+}
+
+// Review is a buyer's review of a product
+type Review struct {
+	ID          string    `json:"id"`
+	SKU         int64     `json:"sku"`
+	Text        string    `json:"text"`
+	Rating      int       `json:"rating"`
+	PublishedAt time.Time `json:"published_at"`
+	Comment     string    `json:"comment,omitempty"` // our reply; set marks the review processed
+}
+
+// Warehouse is one of the seller's own (FBS) warehouses
+type Warehouse struct {
+	ID   int64  `json:"warehouse_id"`
+	Name string `json:"name"`
+}
+
+// Fixtures is the seed data for a stub Ozon seller
+type Fixtures struct {
+	ClientID   string      `json:"client_id"`
+	APIKey     string      `json:"api_key"` // empty accepts any key
+	Warehouses []Warehouse `json:"warehouses"`
+	Products   []Product   `json:"products"`
+	Postings   []Posting   `json:"postings"`
+	Reviews    []Review    `json:"reviews"`
+}
+
+// LoadFixtures reads fixtures from a JSON file
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+
+	var f Fixtures
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures: %w", err)
+	}
+
+	return &f, nil
+}
+
+// DefaultFixtures returns a small demo catalog with postings and reviews
+func DefaultFixtures() *Fixtures {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	f := &Fixtures{
+		ClientID:   "demo-client",
+		Warehouses: []Warehouse{{ID: 22000001, Name: "Основной склад"}, {ID: 22000002, Name: "Склад Казань"}},
+		Products: []Product{
+			{ProductID: 700001, OfferID: "KTL-STEEL-17", SKU: 900001, Name: "Электрочайник стальной 1.7 л", Price: 15990, Currency: "KZT",
+				Stocks: []Stock{{Type: "fbo", Present: 20, Reserved: 2}, {Type: "fbs", Present: 5}, {Type: "fbs", Present: 1, WarehouseID: 22000002}}},
+			{ProductID: 700002, OfferID: "BLN-PRO-800", SKU: 900002, Name: "Блендер погружной 800 Вт", Price: 22490, Currency: "KZT",
+				Stocks: []Stock{{Type: "fbs", Present: 3, Reserved: 1}}},
+			{ProductID: 700003, OfferID: "TST-2SL-WHT", SKU: 900003, Name: "Тостер на 2 ломтика белый", Price: 11990, Currency: "KZT"},
+		},
+		Reviews: []Review{
+			{ID: "rv-1", SKU: 900001, Text: "Кипятит быстро, не шумит", Rating: 5, PublishedAt: today.Add(-20 * time.Hour)},
+			{ID: "rv-2", SKU: 900002, Text: "Насадка треснула через неделю", Rating: 1, PublishedAt: today.Add(-3 * time.Hour)},
+		},
+	}
+
+	cities := []string{"Москва", "Казань", "Екатеринбург"}
+	warehouses := []int64{22000001, 22000002, 22000001}
+
+	for i := 0; i < 14; i++ {
+		day := today.AddDate(0, 0, -i).Add(9 * time.Hour)
+		f.Postings = append(f.Postings, Posting{
+			PostingNumber: fmt.Sprintf("FBS-%04d-1", i), Scheme: "fbs", Status: "delivered", City: cities[i%3], WarehouseID: warehouses[i%3], InProcessAt: day,
+			Products: []PostingProduct{{OfferID: "KTL-STEEL-17", Name: "Электрочайник стальной 1.7 л", Quantity: 1 + i%2, Price: 15990}},
+		})
+		if i%3 == 0 {
+			f.Postings = append(f.Postings, Posting{
+				PostingNumber: fmt.Sprintf("FBO-%04d-1", i), Scheme: "fbo", Status: "delivered", City: cities[(i+1)%3], InProcessAt: day.Add(2 * time.Hour),
+				Products: []PostingProduct{{OfferID: "BLN-PRO-800", Name: "Блендер погружной 800 Вт", Quantity: 1, Price: 22490}},
+			})
+		}
+	}
+	f.Postings = append(f.Postings,
+		Posting{
+			PostingNumber: "FBS-CANCEL-1", Scheme: "fbs", Status: "cancelled", City: "Москва", InProcessAt: today.Add(-30 * time.Hour),
+			Products: []PostingProduct{{OfferID: "TST-2SL-WHT", Name: "Тостер на 2 ломтика белый", Quantity: 1, Price: 11990}},
+		},
+		Posting{
+			PostingNumber: "FBS-NEW-1", Scheme: "fbs", Status: "awaiting_packaging", City: "Казань", InProcessAt: time.Now().UTC().Add(-time.Hour).Truncate(time.Minute),
+			Products: []PostingProduct{{OfferID: "BLN-PRO-800", Name: "Блендер погружной 800 Вт", Quantity: 1, Price: 22490}},
+		},
+	)
+
+	return f
+}
@@ -0,0 +1,730 @@
+package ozontest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// PriceUpdate records a price import received by the stub server
+type PriceUpdate struct {
+	OfferID string    `json:"offer_id"`
+	Price   float64   `json:"price"`
+	At      time.Time `json:"at"`
+}
+
+// StockUpdate records a stock change received by the stub server
+type StockUpdate struct {
+	OfferID     string    `json:"offer_id"`
+	WarehouseID int64     `json:"warehouse_id"`
+	Stock       int       `json:"stock"`
+	At          time.Time `json:"at"`
+}
+
+// PostingAction records a posting state change received by the stub server
+type PostingAction struct {
+	PostingNumber string    `json:"posting_number"`
+	Action        string    `json:"action"` // awaiting-delivery or cancel
+	ReasonID      int       `json:"reason_id,omitempty"`
+	At            time.Time `json:"at"`
+}
+
+// Handler serves the Ozon Seller API from in-memory fixtures.
+// It is safe for concurrent use and records every write it receives.
+type Handler struct {
+	mu           sync.Mutex
+	fixtures     *Fixtures
+	priceUpdates []PriceUpdate
+	stockUpdates []StockUpdate
+	actions      []PostingAction
+	faults       []fault
+	requests     int
+}
+
+// fault is a canned error response returned instead of the real one
+type fault struct {
+	status     int
+	retryAfter time.Duration
+}
+
+// NewHandler creates a handler seeded with the given fixtures (DefaultFixtures if nil)
+func NewHandler(fixtures *Fixtures) *Handler {
+	if fixtures == nil {
+		fixtures = DefaultFixtures()
+	}
+
+	return &Handler{fixtures: fixtures}
+}
+
+// ClientID returns the seller Client-Id the handler serves
+func (h *Handler) ClientID() string {
+	return h.fixtures.ClientID
+}
+
+// PriceUpdates returns all price imports received so far
+func (h *Handler) PriceUpdates() []PriceUpdate {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	updates := make([]PriceUpdate, len(h.priceUpdates))
+	copy(updates, h.priceUpdates)
+	return updates
+}
+
+// StockUpdates returns all stock updates received so far
+func (h *Handler) StockUpdates() []StockUpdate {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	updates := make([]StockUpdate, len(h.stockUpdates))
+	copy(updates, h.stockUpdates)
+	return updates
+}
+
+// PostingActions returns all posting state changes received so far
+func (h *Handler) PostingActions() []PostingAction {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	actions := make([]PostingAction, len(h.actions))
+	copy(actions, h.actions)
+	return actions
+}
+
+// ReviewComments returns posted review comments keyed by review ID
+func (h *Handler) ReviewComments() map[string]string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	comments := make(map[string]string)
+	for _, r := range h.fixtures.Reviews {
+		if r.Comment != "" {
+			comments[r.ID] = r.Comment
+		}
+	}
+	return comments
+}
+
+// FailNext makes the next count requests fail with status (e.g. 429 or 503).
+// A positive retryAfter is sent as a Retry-After header in whole seconds.
+func (h *Handler) FailNext(count, status int, retryAfter time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i := 0; i < count; i++ {
+		h.faults = append(h.faults, fault{status: status, retryAfter: retryAfter})
+	}
+}
+
+// Requests returns the number of requests received, including failed ones
+func (h *Handler) Requests() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.requests
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.injectFault(w) {
+		return
+	}
+
+	// Ozon answers 401 for both an unknown Client-Id and a wrong Api-Key
+	apiKey := r.Header.Get("Api-Key")
+	if apiKey == "" || (h.fixtures.APIKey != "" && apiKey != h.fixtures.APIKey) ||
+		r.Header.Get("Client-Id") != h.fixtures.ClientID {
+		writeError(w, http.StatusUnauthorized, "Invalid Api-Key or Client-Id")
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusNotFound, "unknown endpoint")
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/v3/product/list":
+		h.listProducts(w, r)
+	case "/v3/product/info/list":
+		h.productInfo(w, r)
+	case "/v4/product/info/stocks":
+		h.productStocks(w, r)
+	case "/v1/product/info/stocks-by-warehouse/fbs":
+		h.warehouseStocks(w, r)
+	case "/v3/posting/fbs/list":
+		h.listPostings(w, r, "fbs")
+	case "/v2/posting/fbo/list":
+		h.listPostings(w, r, "fbo")
+	case "/v2/posting/fbs/awaiting-delivery":
+		h.awaitingDelivery(w, r)
+	case "/v2/posting/fbs/cancel":
+		h.cancelPosting(w, r)
+	case "/v1/review/list":
+		h.listReviews(w, r)
+	case "/v1/review/comment/create":
+		h.createComment(w, r)
+	case "/v1/product/import/prices":
+		h.importPrices(w, r)
+	case "/v2/products/stocks":
+		h.updateStocks(w, r)
+	default:
+		writeError(w, http.StatusNotFound, "unknown endpoint")
+	}
+}
+
+func (h *Handler) injectFault(w http.ResponseWriter) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.requests++
+	if len(h.faults) == 0 {
+		return false
+	}
+
+	f := h.faults[0]
+	h.faults = h.faults[1:]
+
+	if f.retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(f.retryAfter.Seconds())))
+	}
+	writeError(w, f.status, http.StatusText(f.status))
+	return true
+}
+
+func (h *Handler) listProducts(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		LastID string `json:"last_id"`
+		Limit  int    `json:"limit"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Limit < 1 || req.Limit > 1000 {
+		writeError(w, http.StatusBadRequest, "limit must be between 1 and 1000")
+		return
+	}
+
+	// last_id is the offset of the next item, as an opaque string
+	from, _ := strconv.Atoi(req.LastID)
+	from, to := bounds(from, req.Limit, len(h.fixtures.Products))
+
+	items := make([]map[string]interface{}, 0, to-from)
+	for _, p := range h.fixtures.Products[from:to] {
+		items = append(items, map[string]interface{}{"product_id": p.ProductID, "offer_id": p.OfferID})
+	}
+
+	lastID := ""
+	if to < len(h.fixtures.Products) {
+		lastID = strconv.Itoa(to)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"result": map[string]interface{}{"items": items, "total": len(h.fixtures.Products), "last_id": lastID},
+	})
+}
+
+func (h *Handler) productInfo(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ProductID []int64  `json:"product_id"`
+		OfferID   []string `json:"offer_id"`
+		SKU       []int64  `json:"sku"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	items := make([]map[string]interface{}, 0)
+	for _, p := range h.fixtures.Products {
+		if !containsInt(req.ProductID, p.ProductID) && !containsString(req.OfferID, p.OfferID) && !containsInt(req.SKU, p.SKU) {
+			continue
+		}
+
+		stocks := p.Stocks
+		if stocks == nil {
+			stocks = []Stock{}
+		}
+
+		items = append(items, map[string]interface{}{
+			"id":            p.ProductID,
+			"offer_id":      p.OfferID,
+			"name":          p.Name,
+			"price":         formatPrice(p.Price),
+			"currency_code": p.Currency,
+			"sources":       []map[string]interface{}{{"sku": p.SKU, "source": "sds"}},
+			"stocks":        map[string]interface{}{"has_stock": len(p.Stocks) > 0, "stocks": stocks},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"items": items})
+}
+
+func (h *Handler) productStocks(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Filter struct {
+			OfferID   []string `json:"offer_id"`
+			ProductID []int64  `json:"product_id"`
+		} `json:"filter"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	items := make([]map[string]interface{}, 0)
+	for _, p := range h.fixtures.Products {
+		all := len(req.Filter.OfferID) == 0 && len(req.Filter.ProductID) == 0
+		if !all && !containsString(req.Filter.OfferID, p.OfferID) && !containsInt(req.Filter.ProductID, p.ProductID) {
+			continue
+		}
+
+		stocks := p.Stocks
+		if stocks == nil {
+			stocks = []Stock{}
+		}
+
+		items = append(items, map[string]interface{}{
+			"product_id": p.ProductID,
+			"offer_id":   p.OfferID,
+			"stocks":     stocks,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"items": items, "total": len(items), "cursor": ""})
+}
+
+// warehouseStocks reports FBS stock per warehouse for the requested SKUs
+func (h *Handler) warehouseStocks(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SKU []int64 `json:"sku"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	result := make([]map[string]interface{}, 0)
+	for _, p := range h.fixtures.Products {
+		if !containsInt(req.SKU, p.SKU) {
+			continue
+		}
+
+		for _, s := range p.Stocks {
+			if s.Type != "fbs" {
+				continue
+			}
+			wh := h.warehouse(s.WarehouseID)
+			result = append(result, map[string]interface{}{
+				"sku":            p.SKU,
+				"product_id":     p.ProductID,
+				"present":        s.Present,
+				"reserved":       s.Reserved,
+				"warehouse_id":   wh.ID,
+				"warehouse_name": wh.Name,
+			})
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"result": result})
+}
+
+func (h *Handler) listPostings(w http.ResponseWriter, r *http.Request, scheme string) {
+	var req struct {
+		Filter struct {
+			Since time.Time `json:"since"`
+			To    time.Time `json:"to"`
+		} `json:"filter"`
+		Limit  int `json:"limit"`
+		Offset int `json:"offset"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Limit < 1 || req.Limit > 1000 || req.Filter.Since.IsZero() || req.Filter.To.IsZero() {
+		writeError(w, http.StatusBadRequest, "filter.since, filter.to and limit (1..1000) are required")
+		return
+	}
+
+	postings := make([]Posting, 0)
+	for _, p := range h.fixtures.Postings {
+		if p.Scheme == scheme && !p.InProcessAt.Before(req.Filter.Since) && !p.InProcessAt.After(req.Filter.To) {
+			postings = append(postings, p)
+		}
+	}
+	sort.SliceStable(postings, func(i, j int) bool {
+		return postings[i].InProcessAt.Before(postings[j].InProcessAt)
+	})
+
+	from, to := bounds(req.Offset, req.Limit, len(postings))
+
+	out := make([]map[string]interface{}, 0, to-from)
+	for _, p := range postings[from:to] {
+		products := make([]map[string]interface{}, 0, len(p.Products))
+		for _, item := range p.Products {
+			products = append(products, map[string]interface{}{
+				"offer_id": item.OfferID,
+				"name":     item.Name,
+				"quantity": item.Quantity,
+				"price":    formatPrice(item.Price),
+			})
+		}
+
+		item := map[string]interface{}{
+			"posting_number": p.PostingNumber,
+			"status":         p.Status,
+			"in_process_at":  p.InProcessAt.UTC().Format(time.RFC3339),
+			"analytics_data": map[string]string{"city": p.City},
+			"products":       products,
+		}
+		if scheme == "fbs" {
+			item["delivery_method"] = map[string]interface{}{"warehouse_id": h.warehouse(p.WarehouseID).ID}
+		}
+		out = append(out, item)
+	}
+
+	// FBS wraps the list and reports has_next; FBO returns a bare array
+	if scheme == "fbs" {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"result": map[string]interface{}{"postings": out, "has_next": to < len(postings)},
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"result": out})
+}
+
+func (h *Handler) awaitingDelivery(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PostingNumber []string `json:"posting_number"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if len(req.PostingNumber) == 0 {
+		writeError(w, http.StatusBadRequest, "posting_number is required")
+		return
+	}
+
+	// Validate the whole batch first so a bad posting number leaves the others untouched
+	postings := make([]*Posting, 0, len(req.PostingNumber))
+	for _, number := range req.PostingNumber {
+		p := h.findFBSPosting(number)
+		if p == nil {
+			writeError(w, http.StatusNotFound, "POSTING_NOT_FOUND: "+number)
+			return
+		}
+		if p.Status != "awaiting_packaging" {
+			writeError(w, http.StatusConflict, "POSTING_ALREADY_SHIPPED: "+number+" is "+p.Status)
+			return
+		}
+		postings = append(postings, p)
+	}
+
+	for _, p := range postings {
+		p.Status = "awaiting_deliver"
+		h.actions = append(h.actions, PostingAction{PostingNumber: p.PostingNumber, Action: "awaiting-delivery", At: time.Now()})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]bool{"result": true})
+}
+
+func (h *Handler) cancelPosting(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PostingNumber       string `json:"posting_number"`
+		CancelReasonID      int    `json:"cancel_reason_id"`
+		CancelReasonMessage string `json:"cancel_reason_message"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.CancelReasonID == 0 {
+		writeError(w, http.StatusBadRequest, "cancel_reason_id is required")
+		return
+	}
+
+	p := h.findFBSPosting(req.PostingNumber)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "POSTING_NOT_FOUND: "+req.PostingNumber)
+		return
+	}
+	if p.Status == "delivered" || p.Status == "cancelled" {
+		writeError(w, http.StatusConflict, "POSTING_CANNOT_BE_CANCELLED: "+p.Status)
+		return
+	}
+
+	p.Status = "cancelled"
+	h.actions = append(h.actions, PostingAction{PostingNumber: p.PostingNumber, Action: "cancel", ReasonID: req.CancelReasonID, At: time.Now()})
+
+	writeJSON(w, http.StatusOK, map[string]bool{"result": true})
+}
+
+// findFBSPosting returns an FBS posting; FBO postings are not managed by the seller
+func (h *Handler) findFBSPosting(number string) *Posting {
+	for i := range h.fixtures.Postings {
+		if h.fixtures.Postings[i].PostingNumber == number && h.fixtures.Postings[i].Scheme == "fbs" {
+			return &h.fixtures.Postings[i]
+		}
+	}
+	return nil
+}
+
+func (h *Handler) listReviews(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		LastID string `json:"last_id"`
+		Limit  int    `json:"limit"`
+		Status string `json:"status"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Limit < 20 || req.Limit > 100 {
+		writeError(w, http.StatusBadRequest, "limit must be between 20 and 100")
+		return
+	}
+
+	reviews := make([]Review, 0, len(h.fixtures.Reviews))
+	for _, rv := range h.fixtures.Reviews {
+		processed := rv.Comment != ""
+		if req.Status == "ALL" || (req.Status == "PROCESSED") == processed {
+			reviews = append(reviews, rv)
+		}
+	}
+	sort.SliceStable(reviews, func(i, j int) bool {
+		return reviews[i].PublishedAt.After(reviews[j].PublishedAt)
+	})
+
+	from, _ := strconv.Atoi(req.LastID)
+	from, to := bounds(from, req.Limit, len(reviews))
+
+	out := make([]map[string]interface{}, 0, to-from)
+	for _, rv := range reviews[from:to] {
+		status := "UNPROCESSED"
+		if rv.Comment != "" {
+			status = "PROCESSED"
+		}
+
+		out = append(out, map[string]interface{}{
+			"id":           rv.ID,
+			"sku":          rv.SKU,
+			"text":         rv.Text,
+			"rating":       rv.Rating,
+			"published_at": rv.PublishedAt.UTC().Format(time.RFC3339),
+			"status":       status,
+		})
+	}
+
+	lastID := ""
+	if to < len(reviews) {
+		lastID = strconv.Itoa(to)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"reviews":  out,
+		"has_next": to < len(reviews),
+		"last_id":  lastID,
+	})
+}
+
+func (h *Handler) createComment(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ReviewID string `json:"review_id"`
+		Text     string `json:"text"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Text == "" {
+		writeError(w, http.StatusBadRequest, "text is required")
+		return
+	}
+
+	for i := range h.fixtures.Reviews {
+		if h.fixtures.Reviews[i].ID == req.ReviewID {
+			h.fixtures.Reviews[i].Comment = req.Text
+			writeJSON(w, http.StatusOK, map[string]string{"comment_id": "c-" + req.ReviewID})
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "review not found")
+}
+
+func (h *Handler) importPrices(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Prices []struct {
+			OfferID string `json:"offer_id"`
+			Price   string `json:"price"`
+		} `json:"prices"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	results := make([]map[string]interface{}, 0, len(req.Prices))
+	for _, item := range req.Prices {
+		result := map[string]interface{}{"offer_id": item.OfferID, "updated": false, "errors": []map[string]string{}}
+
+		price, err := strconv.ParseFloat(item.Price, 64)
+		p := h.findProduct(item.OfferID)
+
+		switch {
+		case p == nil:
+			result["errors"] = []map[string]string{{"code": "PRODUCT_IS_NOT_CREATED", "message": "product not found"}}
+		case err != nil || price <= 0:
+			result["errors"] = []map[string]string{{"code": "INVALID_PRICE", "message": "price must be positive"}}
+		default:
+			p.Price = price
+			result["product_id"] = p.ProductID
+			result["updated"] = true
+			h.priceUpdates = append(h.priceUpdates, PriceUpdate{OfferID: item.OfferID, Price: price, At: time.Now()})
+		}
+
+		results = append(results, result)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"result": results})
+}
+
+// updateStocks sets FBS stock at the named warehouse
+func (h *Handler) updateStocks(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Stocks []struct {
+			OfferID     string `json:"offer_id"`
+			Stock       int    `json:"stock"`
+			WarehouseID int64  `json:"warehouse_id"`
+		} `json:"stocks"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	results := make([]map[string]interface{}, 0, len(req.Stocks))
+	for _, item := range req.Stocks {
+		result := map[string]interface{}{
+			"offer_id": item.OfferID, "warehouse_id": item.WarehouseID, "updated": false, "errors": []map[string]string{},
+		}
+
+		p := h.findProduct(item.OfferID)
+
+		switch {
+		case p == nil:
+			result["errors"] = []map[string]string{{"code": "PRODUCT_IS_NOT_CREATED", "message": "product not found"}}
+		case !h.hasWarehouse(item.WarehouseID):
+			result["errors"] = []map[string]string{{"code": "WAREHOUSE_NOT_FOUND", "message": "warehouse not found"}}
+		case item.Stock < 0:
+			result["errors"] = []map[string]string{{"code": "INVALID_STOCK", "message": "stock must not be negative"}}
+		default:
+			h.setFBSStock(p, item.WarehouseID, item.Stock)
+			result["product_id"] = p.ProductID
+			result["updated"] = true
+			h.stockUpdates = append(h.stockUpdates, StockUpdate{OfferID: item.OfferID, WarehouseID: item.WarehouseID, Stock: item.Stock, At: time.Now()})
+		}
+
+		results = append(results, result)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"result": results})
+}
+
+func (h *Handler) hasWarehouse(id int64) bool {
+	for _, wh := range h.fixtures.Warehouses {
+		if wh.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// warehouse resolves a fixture warehouse; zero (or an unknown ID) means the first one
+func (h *Handler) warehouse(id int64) Warehouse {
+	for _, wh := range h.fixtures.Warehouses {
+		if wh.ID == id {
+			return wh
+		}
+	}
+	if len(h.fixtures.Warehouses) > 0 {
+		return h.fixtures.Warehouses[0]
+	}
+	return Warehouse{ID: id}
+}
+
+// setFBSStock replaces the present quantity of the product's FBS stock at one warehouse,
+// keeping reservations
+func (h *Handler) setFBSStock(p *Product, warehouseID int64, present int) {
+	for i := range p.Stocks {
+		if p.Stocks[i].Type == "fbs" && h.warehouse(p.Stocks[i].WarehouseID).ID == warehouseID {
+			p.Stocks[i].Present = present
+			return
+		}
+	}
+	p.Stocks = append(p.Stocks, Stock{Type: "fbs", Present: present, WarehouseID: warehouseID})
+}
+
+func (h *Handler) findProduct(offerID string) *Product {
+	for i := range h.fixtures.Products {
+		if h.fixtures.Products[i].OfferID == offerID {
+			return &h.fixtures.Products[i]
+		}
+	}
+	return nil
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return false
+	}
+	return true
+}
+
+func containsInt(values []int64, v int64) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, v string) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// formatPrice renders a price the way Ozon does: a decimal string
+func formatPrice(price float64) string {
+	return strconv.FormatFloat(price, 'f', 2, 64)
+}
+
+// bounds returns the slice bounds for offset/limit paging
+func bounds(offset, limit, total int) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > total {
+		offset = total
+	}
+	to := offset + limit
+	if to > total {
+		to = total
+	}
+	return offset, to
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error in Ozon's format: {"code", "message", "details"}
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{"code": status, "message": message, "details": []string{}})
+}
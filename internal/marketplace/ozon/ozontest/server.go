@@ -0,0 +1,50 @@
+// Package ozontest provides an in-memory stub of the Ozon Seller API
+// for integration tests and local demos.
+package ozontest
+
+import (
+	"net/http/httptest"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace/ozon"
+)
+
+// Server is a stub Ozon Seller API running on a local httptest listener
+type Server struct {
+	*Handler
+	URL string
+
+	srv *httptest.Server
+}
+
+// NewServer starts a stub Ozon server seeded with fixtures (DefaultFixtures if nil).
+// Callers must Close it when done.
+func NewServer(fixtures *Fixtures) *Server {
+	h := NewHandler(fixtures)
+	srv := httptest.NewServer(h)
+
+	return &Server{
+		Handler: h,
+		URL:     srv.URL,
+		srv:     srv,
+	}
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// NewClient returns an ozon.Client pointed at this server for the seeded seller
+func (s *Server) NewClient(opts ...ozon.Option) *ozon.Client {
+	apiKey := s.fixtures.APIKey
+	if apiKey == "" {
+		apiKey = "test-api-key"
+	}
+
+	opts = append([]ozon.Option{
+		ozon.WithBaseURL(s.URL),
+		ozon.WithHTTPClient(s.srv.Client()),
+	}, opts...)
+
+	return ozon.NewClient(s.ClientID(), apiKey, opts...)
+}
@@ -0,0 +1,73 @@
+package ozon
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+// GetCompetitorPrices не поддерживается: Seller API Ozon не отдаёт
+// предложения других продавцов
+func (c *Client) GetCompetitorPrices(ctx context.Context, productExternalID, city string) ([]marketplace.CompetitorOffer, error) {
+	return nil, fmt.Errorf("ozon: competitor offers: %w", marketplace.ErrNotSupported)
+}
+
+// UpdateCityPrice не поддерживается: цена на Ozon одна для всех регионов
+func (c *Client) UpdateCityPrice(ctx context.Context, productExternalID, city string, newPrice float64) error {
+	return fmt.Errorf("ozon: city prices: %w", marketplace.ErrNotSupported)
+}
+
+// UpdateProductPrice обновляет цену товара через импорт цен. Валюта не
+// передаётся — Ozon использует валюту личного кабинета продавца.
+func (c *Client) UpdateProductPrice(ctx context.Context, productExternalID string, newPrice float64) error {
+	if newPrice <= 0 {
+		return fmt.Errorf("%w: %.2f", ErrInvalidPrice, newPrice)
+	}
+
+	payload := map[string]interface{}{
+		"prices": []map[string]interface{}{
+			{
+				"offer_id":  productExternalID,
+				"price":     strconv.FormatFloat(newPrice, 'f', 2, 64),
+				"old_price": "0",
+			},
+		},
+	}
+
+	var response struct {
+		Result []struct {
+			OfferID string `json:"offer_id"`
+			Updated bool   `json:"updated"`
+			Errors  []struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"errors"`
+		} `json:"result"`
+	}
+
+	// Импорт цены идемпотентен: повтор устанавливает то же значение
+	if err := c.post(ctx, "/v1/product/import/prices", payload, &response, true); err != nil {
+		return err
+	}
+
+	for _, r := range response.Result {
+		if r.Updated {
+			continue
+		}
+
+		// Ozon сообщает об ошибках по каждому товару внутри ответа 200
+		messages := make([]string, 0, len(r.Errors))
+		for _, e := range r.Errors {
+			if e.Code == "PRODUCT_IS_NOT_CREATED" || e.Code == "NOT_FOUND" {
+				return fmt.Errorf("%w: %s", ErrProductNotFound, productExternalID)
+			}
+			messages = append(messages, e.Code+": "+e.Message)
+		}
+		return fmt.Errorf("failed to update price for %s: %s", r.OfferID, strings.Join(messages, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package ozon_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/internal/marketplace/ozon"
+)
+
+func TestUpdateProductPrice(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+	ctx := context.Background()
+
+	if err := client.UpdateProductPrice(ctx, "BLN-PRO-800", 19990.5); err != nil {
+		t.Fatalf("UpdateProductPrice: %v", err)
+	}
+
+	updates := srv.PriceUpdates()
+	if len(updates) != 1 || updates[0].OfferID != "BLN-PRO-800" || updates[0].Price != 19990.5 {
+		t.Fatalf("price updates = %+v", updates)
+	}
+
+	products, err := client.GetProducts(ctx)
+	if err != nil {
+		t.Fatalf("GetProducts: %v", err)
+	}
+	if products[1].Price != 19990.5 {
+		t.Errorf("price after update = %v, want 19990.5", products[1].Price)
+	}
+}
+
+func TestUpdateProductPriceRejectsInvalidPrice(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+
+	for _, price := range []float64{0, -100} {
+		if err := client.UpdateProductPrice(context.Background(), "BLN-PRO-800", price); !errors.Is(err, ozon.ErrInvalidPrice) {
+			t.Errorf("price %v: err = %v, want ErrInvalidPrice", price, err)
+		}
+	}
+	if n := srv.Requests(); n != 0 {
+		t.Errorf("invalid prices sent %d requests, want 0", n)
+	}
+}
+
+func TestUpdateProductPriceNotFound(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+
+	// Ozon reports unknown offers inside a 200 response
+	err := client.UpdateProductPrice(context.Background(), "NO-SUCH-OFFER", 1000)
+	if !errors.Is(err, ozon.ErrProductNotFound) || !errors.Is(err, marketplace.ErrNotFound) {
+		t.Errorf("err = %v, want ErrProductNotFound and ErrNotFound", err)
+	}
+	if updates := srv.PriceUpdates(); len(updates) != 0 {
+		t.Errorf("got %d price updates, want 0", len(updates))
+	}
+}
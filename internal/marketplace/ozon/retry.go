@@ -0,0 +1,27 @@
+package ozon
+
+import (
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+// RetryPolicy controls how failed requests are retried; see marketplace.RetryPolicy
+type RetryPolicy = marketplace.RetryPolicy
+
+// DefaultRetryPolicy is used unless overridden with WithRetryPolicy
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+// WithRetryPolicy overrides the retry policy. MaxAttempts of 1 disables retries.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		if policy.MaxAttempts < 1 {
+			policy.MaxAttempts = 1
+		}
+		c.retry = policy
+	}
+}
@@ -0,0 +1,138 @@
+package ozon_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/internal/marketplace/ozon"
+)
+
+// fastRetries retries quickly but still honors a Retry-After of a second
+var fastRetries = ozon.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Second}
+
+func TestRetryAfterOn429(t *testing.T) {
+	srv, client := newTestServer(t, nil, ozon.WithRetryPolicy(fastRetries))
+	srv.FailNext(1, http.StatusTooManyRequests, time.Second)
+
+	start := time.Now()
+	if _, err := client.GetProductStock(context.Background(), "KTL-STEEL-17"); err != nil {
+		t.Fatalf("GetProductStock: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least the 1s Retry-After", elapsed)
+	}
+	if n := srv.Requests(); n != 2 {
+		t.Errorf("server got %d requests, want 2", n)
+	}
+
+	stats := client.Stats()
+	if stats.Calls != 1 || stats.Retries != 1 || stats.Throttles != 1 || stats.Failures != 0 {
+		t.Errorf("stats = %+v, want 1 call with 1 retry and 1 throttle", stats)
+	}
+}
+
+func TestRetryAfterLongerThanMaxDelay(t *testing.T) {
+	srv, client := newTestServer(t, nil, ozon.WithRetryPolicy(fastRetries))
+	srv.FailNext(1, http.StatusTooManyRequests, time.Minute)
+
+	start := time.Now()
+	_, err := client.GetProductStock(context.Background(), "KTL-STEEL-17")
+	if !errors.Is(err, marketplace.ErrRateLimited) {
+		t.Fatalf("err = %v, want ErrRateLimited", err)
+	}
+
+	// The client gives up instead of sleeping past MaxDelay
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("gave up after %v, want right away", elapsed)
+	}
+	if n := srv.Requests(); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+}
+
+func TestRetryOn5xx(t *testing.T) {
+	srv, client := newTestServer(t, nil, ozon.WithRetryPolicy(fastRetries))
+	srv.FailNext(1, http.StatusServiceUnavailable, 0)
+	srv.FailNext(1, http.StatusBadGateway, 0)
+
+	// Price imports are idempotent, so they are retried like reads
+	if err := client.UpdateProductPrice(context.Background(), "BLN-PRO-800", 19990); err != nil {
+		t.Fatalf("UpdateProductPrice: %v", err)
+	}
+
+	if n := srv.Requests(); n != 3 {
+		t.Errorf("server got %d requests, want 3", n)
+	}
+	if updates := srv.PriceUpdates(); len(updates) != 1 {
+		t.Errorf("got %d price updates, want 1", len(updates))
+	}
+}
+
+func TestNoRetryOn4xx(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusConflict} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			srv, client := newTestServer(t, nil, ozon.WithRetryPolicy(fastRetries))
+			srv.FailNext(1, status, 0)
+
+			_, err := client.GetProductStock(context.Background(), "KTL-STEEL-17")
+
+			var apiErr *ozon.APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != status {
+				t.Fatalf("err = %v, want an APIError with status %d", err, status)
+			}
+			if n := srv.Requests(); n != 1 {
+				t.Errorf("server got %d requests, want 1", n)
+			}
+		})
+	}
+}
+
+func TestNoRetryOn5xxForReviewComment(t *testing.T) {
+	srv, client := newTestServer(t, nil, ozon.WithRetryPolicy(fastRetries))
+	ctx := context.Background()
+	srv.FailNext(1, http.StatusServiceUnavailable, 0)
+
+	err := client.PostReviewResponse(ctx, "rv-1", "Спасибо за отзыв!")
+	if !errors.Is(err, marketplace.ErrUnavailable) {
+		t.Fatalf("err = %v, want ErrUnavailable", err)
+	}
+	if n := srv.Requests(); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+
+	// 429 means the request was not processed, so even a comment is retried
+	srv.FailNext(1, http.StatusTooManyRequests, 0)
+	if err := client.PostReviewResponse(ctx, "rv-1", "Спасибо за отзыв!"); err != nil {
+		t.Fatalf("PostReviewResponse after 429: %v", err)
+	}
+	if n := srv.Requests(); n != 3 {
+		t.Errorf("server got %d requests, want 3", n)
+	}
+	if comments := srv.ReviewComments(); len(comments) != 1 {
+		t.Errorf("comments = %v, want exactly one", comments)
+	}
+}
+
+func TestRetryAttemptCap(t *testing.T) {
+	srv, client := newTestServer(t, nil, ozon.WithRetryPolicy(fastRetries))
+	srv.FailNext(10, http.StatusInternalServerError, 0)
+
+	_, err := client.GetProductStock(context.Background(), "KTL-STEEL-17")
+	if !errors.Is(err, marketplace.ErrUnavailable) {
+		t.Fatalf("err = %v, want ErrUnavailable", err)
+	}
+
+	if n := srv.Requests(); n != fastRetries.MaxAttempts {
+		t.Errorf("server got %d requests, want %d", n, fastRetries.MaxAttempts)
+	}
+
+	stats := client.Stats()
+	if stats.Calls != 1 || stats.Attempts != int64(fastRetries.MaxAttempts) || stats.Failures != 1 {
+		t.Errorf("stats = %+v, want 1 failed call with %d attempts", stats, fastRetries.MaxAttempts)
+	}
+}
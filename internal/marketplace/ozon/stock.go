@@ -0,0 +1,122 @@
+package ozon
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+// UpdateStock sets a product's stock at one of the seller's FBS warehouses.
+// Stock at Ozon's own (FBO) warehouses is managed by Ozon and cannot be set.
+func (c *Client) UpdateStock(ctx context.Context, productExternalID, warehouseID string, quantity int) error {
+	if quantity < 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidStock, quantity)
+	}
+
+	warehouse, err := strconv.ParseInt(warehouseID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: warehouse ID %q is not numeric", ErrInvalidStock, warehouseID)
+	}
+
+	payload := map[string]interface{}{
+		"stocks": []map[string]interface{}{
+			{
+				"offer_id":     productExternalID,
+				"stock":        quantity,
+				"warehouse_id": warehouse,
+			},
+		},
+	}
+
+	var response struct {
+		Result []struct {
+			OfferID string `json:"offer_id"`
+			Updated bool   `json:"updated"`
+			Errors  []struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"errors"`
+		} `json:"result"`
+	}
+
+	// Setting an absolute quantity is idempotent: a repeat sets the same value
+	if err := c.post(ctx, "/v2/products/stocks", payload, &response, true); err != nil {
+		return err
+	}
+
+	for _, r := range response.Result {
+		if r.Updated {
+			continue
+		}
+
+		// Like price imports, per-item errors come back inside a 200 response
+		messages := make([]string, 0, len(r.Errors))
+		for _, e := range r.Errors {
+			if e.Code == "PRODUCT_IS_NOT_CREATED" || e.Code == "NOT_FOUND" {
+				return fmt.Errorf("%w: %s", ErrProductNotFound, productExternalID)
+			}
+			messages = append(messages, e.Code+": "+e.Message)
+		}
+		return fmt.Errorf("failed to update stock for %s: %s", r.OfferID, strings.Join(messages, "; "))
+	}
+
+	return nil
+}
+
+// FBOWarehouseID stands for all of Ozon's own warehouses in per-warehouse stock: Ozon
+// reports FBO stock as one number, and FBO orders are not tied to a seller warehouse
+const FBOWarehouseID = "fbo"
+
+// warehouseStocks splits each product's stock into the seller's FBS warehouses plus one
+// FBO entry, keyed by Ozon product ID
+func (c *Client) warehouseStocks(ctx context.Context, infos []productInfo) (map[int64][]marketplace.WarehouseStock, error) {
+	skus := make([]int64, 0, len(infos))
+	for _, info := range infos {
+		for _, s := range info.Sources {
+			skus = append(skus, s.SKU)
+		}
+	}
+
+	var response struct {
+		Result []struct {
+			ProductID     int64  `json:"product_id"`
+			Present       int    `json:"present"`
+			Reserved      int    `json:"reserved"`
+			WarehouseID   int64  `json:"warehouse_id"`
+			WarehouseName string `json:"warehouse_name"`
+		} `json:"result"`
+	}
+
+	if len(skus) > 0 {
+		payload := map[string]interface{}{"sku": skus}
+		if err := c.post(ctx, "/v1/product/info/stocks-by-warehouse/fbs", payload, &response, true); err != nil {
+			return nil, err
+		}
+	}
+
+	stocks := make(map[int64][]marketplace.WarehouseStock, len(infos))
+	for _, r := range response.Result {
+		stocks[r.ProductID] = append(stocks[r.ProductID], marketplace.WarehouseStock{
+			WarehouseID: strconv.FormatInt(r.WarehouseID, 10),
+			Name:        r.WarehouseName,
+			Quantity:    max(r.Present-r.Reserved, 0),
+		})
+	}
+
+	for _, info := range infos {
+		for _, s := range info.Stocks.Stocks {
+			if s.Type == "fbo" {
+				stocks[info.ID] = append(stocks[info.ID], marketplace.WarehouseStock{
+					WarehouseID: FBOWarehouseID,
+					Name:        "Ozon FBO",
+					Quantity:    max(s.Present-s.Reserved, 0),
+				})
+			}
+		}
+	}
+
+	return stocks, nil
+}
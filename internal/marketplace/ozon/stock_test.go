@@ -0,0 +1,67 @@
+package ozon_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace/ozon"
+)
+
+func TestUpdateStock(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+	ctx := context.Background()
+
+	if err := client.UpdateStock(ctx, "KTL-STEEL-17", "22000002", 7); err != nil {
+		t.Fatalf("UpdateStock: %v", err)
+	}
+
+	updates := srv.StockUpdates()
+	if len(updates) != 1 || updates[0].OfferID != "KTL-STEEL-17" || updates[0].WarehouseID != 22000002 || updates[0].Stock != 7 {
+		t.Fatalf("stock updates = %+v", updates)
+	}
+
+	// 18 at FBO, 5 at the main warehouse and now 7 in Kazan
+	stock, err := client.GetProductStock(ctx, "KTL-STEEL-17")
+	if err != nil {
+		t.Fatalf("GetProductStock: %v", err)
+	}
+	if stock != 30 {
+		t.Errorf("stock after update = %d, want 30", stock)
+	}
+}
+
+func TestUpdateStockRejectsInvalidInput(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+	ctx := context.Background()
+
+	if err := client.UpdateStock(ctx, "KTL-STEEL-17", "22000001", -1); !errors.Is(err, ozon.ErrInvalidStock) {
+		t.Errorf("negative stock: err = %v, want ErrInvalidStock", err)
+	}
+	if err := client.UpdateStock(ctx, "KTL-STEEL-17", ozon.FBOWarehouseID, 5); !errors.Is(err, ozon.ErrInvalidStock) {
+		t.Errorf("FBO warehouse: err = %v, want ErrInvalidStock", err)
+	}
+	if n := srv.Requests(); n != 0 {
+		t.Errorf("invalid stock updates sent %d requests, want 0", n)
+	}
+}
+
+func TestUpdateStockItemErrors(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+	ctx := context.Background()
+
+	err := client.UpdateStock(ctx, "NO-SUCH-OFFER", "22000001", 5)
+	if !errors.Is(err, ozon.ErrProductNotFound) {
+		t.Errorf("unknown offer: err = %v, want ErrProductNotFound", err)
+	}
+
+	err = client.UpdateStock(ctx, "KTL-STEEL-17", "22009999", 5)
+	if err == nil || errors.Is(err, ozon.ErrProductNotFound) || !strings.Contains(err.Error(), "WAREHOUSE_NOT_FOUND") {
+		t.Errorf("unknown warehouse: err = %v, want the WAREHOUSE_NOT_FOUND item error", err)
+	}
+
+	if updates := srv.StockUpdates(); len(updates) != 0 {
+		t.Errorf("got %d stock updates, want 0", len(updates))
+	}
+}
@@ -0,0 +1,62 @@
+package marketplace
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Type identifies a marketplace
+type Type string
+
+const (
+	TypeKaspi       Type = "kaspi"
+	TypeWildberries Type = "wildberries"
+	TypeOzon        Type = "ozon"
+)
+
+// ErrUnsupportedMarketplace is returned when no factory is registered for a marketplace type
+var ErrUnsupportedMarketplace = errors.New("marketplace: unsupported marketplace type")
+
+// Credentials are the decrypted credentials needed to build a client
+type Credentials struct {
+	APIKey     string
+	APISecret  string
+	MerchantID string
+}
+
+// Factory builds a client for one marketplace from decrypted credentials
+type Factory func(creds Credentials) (MarketplaceClient, error)
+
+// Registry maps marketplace types to client factories
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[Type]Factory
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		factories: make(map[Type]Factory),
+	}
+}
+
+// Register adds or replaces the factory for a marketplace type
+func (r *Registry) Register(t Type, factory Factory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.factories[t] = factory
+}
+
+// NewClient builds a client for the given marketplace type
+func (r *Registry) NewClient(t Type, creds Credentials) (MarketplaceClient, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[t]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedMarketplace, t)
+	}
+
+	return factory(creds)
+}
@@ -0,0 +1,205 @@
+package marketplace
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. Idempotent calls are retried
+// on network errors and 429/5xx responses; other calls only on 429, since the
+// marketplace rejected the request unprocessed.
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts including the first one
+	BaseDelay   time.Duration // delay before the first retry, doubled on each further retry
+	MaxDelay    time.Duration // upper bound for a single delay, including the server's retry hint
+}
+
+// Backoff returns the delay before retry number n (1-based): exponential with equal jitter
+func (p RetryPolicy) Backoff(n int) time.Duration {
+	d := p.BaseDelay << uint(n-1)
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// IsIdempotent reports whether requests with the HTTP method are safe to repeat
+func IsIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// IsRetryableStatus reports whether a response with the status may succeed on a retry
+func IsRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// ParseRetryAfter reads a Retry-After value given in seconds or as an HTTP date.
+// It returns 0 when the value is missing or malformed.
+func ParseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
+
+// SleepContext waits for d, returning early with the context's error if it is done first
+func SleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// RetryRequest is one logical API call for DoWithRetry
+type RetryRequest struct {
+	Marketplace string // name put on the *APIError of a failed response
+	Policy      RetryPolicy
+	Idempotent  bool
+
+	// NewRequest builds the HTTP request of each attempt, so its body can be sent again
+	NewRequest func(ctx context.Context) (*http.Request, error)
+
+	// RetryAfterHeaders names the headers holding the server's wait hint, in order of
+	// preference. Retry-After is used when empty.
+	RetryAfterHeaders []string
+
+	// BeforeAttempt, if set, runs before every attempt, e.g. to wait on a rate limiter.
+	// Its error ends the call and is returned as is.
+	BeforeAttempt func(ctx context.Context) error
+
+	// AfterAttempt, if set, runs after every attempt with the response status, 0 on a network error
+	AfterAttempt func(status int)
+}
+
+// DoWithRetry sends the request until it succeeds or the policy gives up.
+// Non-2xx responses are returned as *APIError, network failures as ErrUnavailable.
+func DoWithRetry(ctx context.Context, client *http.Client, r RetryRequest) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		if r.BeforeAttempt != nil {
+			if err := r.BeforeAttempt(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		req, err := r.NewRequest(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(req)
+
+		var (
+			lastErr    error
+			retryAfter time.Duration
+			retryable  bool
+		)
+
+		switch {
+		case err != nil:
+			r.afterAttempt(0)
+			if ctx.Err() != nil {
+				lastErr = fmt.Errorf("request failed: %w", err)
+			} else {
+				lastErr = fmt.Errorf("%w: request failed: %v", ErrUnavailable, err)
+			}
+			// Network errors are only safe to retry when the call is idempotent
+			retryable = ctx.Err() == nil && r.Idempotent
+
+		case resp.StatusCode < 200 || resp.StatusCode >= 300:
+			r.afterAttempt(resp.StatusCode)
+			respBody, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			lastErr = &APIError{Marketplace: r.Marketplace, StatusCode: resp.StatusCode, Body: string(respBody)}
+
+			if resp.StatusCode == http.StatusTooManyRequests {
+				retryable = true
+			} else {
+				retryable = IsRetryableStatus(resp.StatusCode) && r.Idempotent
+			}
+			retryAfter = r.retryAfter(resp.Header)
+
+		default:
+			r.afterAttempt(resp.StatusCode)
+			return resp, nil
+		}
+
+		if !retryable || attempt >= r.Policy.MaxAttempts {
+			return nil, lastErr
+		}
+
+		delay := r.Policy.Backoff(attempt)
+		if retryAfter > 0 {
+			// Do not sleep longer than the policy allows; let the caller retry on its next cycle
+			if retryAfter > r.Policy.MaxDelay {
+				return nil, lastErr
+			}
+			if retryAfter > delay {
+				delay = retryAfter
+			}
+		}
+
+		if err := SleepContext(ctx, delay); err != nil {
+			return nil, lastErr
+		}
+	}
+}
+
+func (r RetryRequest) afterAttempt(status int) {
+	if r.AfterAttempt != nil {
+		r.AfterAttempt(status)
+	}
+}
+
+// retryAfter reads the first wait hint present in the response headers
+func (r RetryRequest) retryAfter(h http.Header) time.Duration {
+	names := r.RetryAfterHeaders
+	if len(names) == 0 {
+		names = []string{"Retry-After"}
+	}
+
+	for _, name := range names {
+		if d := ParseRetryAfter(h.Get(name)); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
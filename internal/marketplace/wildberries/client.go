@@ -0,0 +1,571 @@
+package wildberries
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+// Wildberries splits its seller API across several hosts; one token works for all of them
+const (
+	DefaultContentURL    = "https://content-api.wildberries.ru"
+	DefaultStatisticsURL = "https://statistics-api.wildberries.ru"
+	DefaultFeedbacksURL  = "https://feedbacks-api.wildberries.ru"
+	DefaultPricesURL     = "https://discounts-prices-api.wildberries.ru"
+
+	// DefaultPageSize is the number of items requested per page (cards, feedbacks, prices)
+	DefaultPageSize = 100
+
+	// DefaultMaxPages caps how many pages a single list call will follow
+	DefaultMaxPages = 500
+)
+
+// Client implements marketplace.MarketplaceClient for Wildberries
+type Client struct {
+	token      string
+	httpClient *http.Client
+
+	contentURL    string
+	statisticsURL string
+	feedbacksURL  string
+	pricesURL     string
+
+	pageSize int
+	maxPages int
+	retry    RetryPolicy
+
+	calls    atomic.Int64
+	attempts atomic.Int64
+	throttle atomic.Int64
+	failures atomic.Int64
+}
+
+var (
+	_ marketplace.MarketplaceClient = (*Client)(nil)
+	_ marketplace.StatsReporter     = (*Client)(nil)
+)
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithBaseURL points every API host at one base URL (e.g. a local fake server)
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		if baseURL == "" {
+			return
+		}
+		baseURL = strings.TrimRight(baseURL, "/")
+		c.contentURL = baseURL
+		c.statisticsURL = baseURL
+		c.feedbacksURL = baseURL
+		c.pricesURL = baseURL
+	}
+}
+
+// WithHTTPClient replaces the default HTTP client (30s timeout)
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithPageSize sets the page size for list calls
+func WithPageSize(size int) Option {
+	return func(c *Client) {
+		if size > 0 {
+			c.pageSize = size
+		}
+	}
+}
+
+// WithMaxPages caps the number of pages followed by a single list call
+func WithMaxPages(maxPages int) Option {
+	return func(c *Client) {
+		if maxPages > 0 {
+			c.maxPages = maxPages
+		}
+	}
+}
+
+func NewClient(token string, opts ...Option) *Client {
+	c := &Client{
+		token: token,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		contentURL:    DefaultContentURL,
+		statisticsURL: DefaultStatisticsURL,
+		feedbacksURL:  DefaultFeedbacksURL,
+		pricesURL:     DefaultPricesURL,
+		pageSize:      DefaultPageSize,
+		maxPages:      DefaultMaxPages,
+		retry:         DefaultRetryPolicy,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *Client) GetProducts(ctx context.Context) ([]marketplace.ProductData, error) {
+	var products []marketplace.ProductData
+	err := c.ForEachProductPage(ctx, func(page []marketplace.ProductData) error {
+		products = append(products, page...)
+		return nil
+	})
+	return products, err
+}
+
+// ForEachProductPage streams product cards to fn one page at a time.
+// Cards carry no stock or price, so both are loaded up front and joined by nmID.
+func (c *Client) ForEachProductPage(ctx context.Context, fn func([]marketplace.ProductData) error) error {
+	stocks, err := c.stockByNmID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get stocks: %w", err)
+	}
+
+	prices, err := c.pricesByNmID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get prices: %w", err)
+	}
+
+	type card struct {
+		NmID       int64  `json:"nmID"`
+		VendorCode string `json:"vendorCode"`
+		Title      string `json:"title"`
+	}
+
+	// The cards list is paged with a cursor made of the last card's updatedAt and nmID
+	var cursor struct {
+		UpdatedAt string
+		NmID      int64
+	}
+
+	for page := 0; ; page++ {
+		if page >= c.maxPages {
+			return fmt.Errorf("%w: stopped after %d pages of cards", ErrPageLimitReached, page)
+		}
+
+		payload := map[string]interface{}{
+			"settings": map[string]interface{}{
+				"cursor": cursorPayload(c.pageSize, cursor.UpdatedAt, cursor.NmID),
+				"filter": map[string]interface{}{
+					"withPhoto": -1,
+				},
+			},
+		}
+
+		var response struct {
+			Cards  []card `json:"cards"`
+			Cursor struct {
+				UpdatedAt string `json:"updatedAt"`
+				NmID      int64  `json:"nmID"`
+			} `json:"cursor"`
+		}
+
+		if err := c.doJSON(ctx, "POST", c.contentURL+"/content/v2/get/cards/list", payload, &response); err != nil {
+			return err
+		}
+
+		if len(response.Cards) == 0 {
+			return nil
+		}
+
+		products := make([]marketplace.ProductData, 0, len(response.Cards))
+		for _, card := range response.Cards {
+			price := prices[card.NmID]
+			products = append(products, marketplace.ProductData{
+				ExternalID:   strconv.FormatInt(card.NmID, 10),
+				SKU:          card.VendorCode,
+				Name:         card.Title,
+				CurrentStock: totalStock(stocks[card.NmID]),
+				Stocks:       stocks[card.NmID],
+				Price:        price.price,
+				Currency:     price.currency,
+			})
+		}
+
+		if err := fn(products); err != nil {
+			return err
+		}
+
+		// A short page means the catalog is exhausted
+		if len(response.Cards) < c.pageSize {
+			return nil
+		}
+
+		cursor.UpdatedAt = response.Cursor.UpdatedAt
+		cursor.NmID = response.Cursor.NmID
+	}
+}
+
+// cursorPayload builds the cards list cursor; the first page is requested without a position
+func cursorPayload(limit int, updatedAt string, nmID int64) map[string]interface{} {
+	cursor := map[string]interface{}{"limit": limit}
+	if updatedAt != "" {
+		cursor["updatedAt"] = updatedAt
+		cursor["nmID"] = nmID
+	}
+	return cursor
+}
+
+func (c *Client) GetProductStock(ctx context.Context, externalID string) (int, error) {
+	nmID, err := parseNmID(externalID)
+	if err != nil {
+		return 0, err
+	}
+
+	stocks, err := c.stockByNmID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return totalStock(stocks[nmID]), nil
+}
+
+// stockByNmID lists available stock per nmID and Wildberries warehouse. The report
+// has no warehouse IDs, so the warehouse name serves as the ID.
+func (c *Client) stockByNmID(ctx context.Context) (map[int64][]marketplace.WarehouseStock, error) {
+	type stockRow struct {
+		NmID          int64  `json:"nmId"`
+		WarehouseName string `json:"warehouseName"`
+		Quantity      int    `json:"quantity"`
+	}
+
+	query := url.Values{}
+	// The oldest date the API accepts; returns the current stock of every item
+	query.Set("dateFrom", "2019-06-20")
+
+	var rows []stockRow
+	if err := c.doJSON(ctx, "GET", c.statisticsURL+"/api/v1/supplier/stocks?"+query.Encode(), nil, &rows); err != nil {
+		return nil, err
+	}
+
+	stocks := make(map[int64][]marketplace.WarehouseStock, len(rows))
+	for _, r := range rows {
+		stocks[r.NmID] = append(stocks[r.NmID], marketplace.WarehouseStock{
+			WarehouseID: r.WarehouseName,
+			Name:        r.WarehouseName,
+			Quantity:    r.Quantity,
+		})
+	}
+
+	return stocks, nil
+}
+
+// totalStock sums stock across warehouses
+func totalStock(stocks []marketplace.WarehouseStock) int {
+	total := 0
+	for _, s := range stocks {
+		total += s.Quantity
+	}
+	return total
+}
+
+func (c *Client) GetSalesData(ctx context.Context, startDate, endDate time.Time) ([]marketplace.SalesData, error) {
+	var salesData []marketplace.SalesData
+	err := c.ForEachSalesPage(ctx, startDate, endDate, func(page []marketplace.SalesData) error {
+		salesData = append(salesData, page...)
+		return nil
+	})
+	return salesData, err
+}
+
+// ForEachSalesPage streams sales for a date range to fn one page at a time.
+// Wildberries reports one row per unit sold or returned, so each row is emitted
+// with QuantitySold of 1 (sale) or -1 (return); callers aggregate per day.
+func (c *Client) ForEachSalesPage(ctx context.Context, startDate, endDate time.Time, fn func([]marketplace.SalesData) error) error {
+	type saleRow struct {
+		Date           string  `json:"date"`
+		LastChangeDate string  `json:"lastChangeDate"`
+		NmID           int64   `json:"nmId"`
+		SaleID         string  `json:"saleID"`
+		ForPay         float64 `json:"forPay"`
+	}
+
+	start := startDate.Truncate(24 * time.Hour)
+	end := endDate.Truncate(24 * time.Hour).Add(24 * time.Hour)
+	dateFrom := startDate.Format("2006-01-02")
+
+	// The report is paged by lastChangeDate: the next page starts at the last row's
+	// change time, so rows at the boundary come back twice and are skipped by saleID
+	seen := make(map[string]bool)
+
+	for page := 0; ; page++ {
+		if page >= c.maxPages {
+			return fmt.Errorf("%w: stopped after %d pages of sales", ErrPageLimitReached, page)
+		}
+
+		query := url.Values{}
+		query.Set("dateFrom", dateFrom)
+
+		var rows []saleRow
+		if err := c.doJSON(ctx, "GET", c.statisticsURL+"/api/v1/supplier/sales?"+query.Encode(), nil, &rows); err != nil {
+			return err
+		}
+
+		salesData := make([]marketplace.SalesData, 0, len(rows))
+		fresh := 0
+		for _, r := range rows {
+			if seen[r.SaleID] {
+				continue
+			}
+			seen[r.SaleID] = true
+			fresh++
+
+			date, err := parseTime(r.Date)
+			if err != nil {
+				return decodeError(err)
+			}
+			if date.Before(start) || !date.Before(end) {
+				continue
+			}
+
+			quantity := 1
+			if strings.HasPrefix(r.SaleID, "R") {
+				quantity = -1
+			}
+
+			salesData = append(salesData, marketplace.SalesData{
+				ProductExternalID: strconv.FormatInt(r.NmID, 10),
+				Date:              date,
+				QuantitySold:      quantity,
+				Revenue:           r.ForPay,
+			})
+		}
+
+		if len(salesData) > 0 {
+			if err := fn(salesData); err != nil {
+				return err
+			}
+		}
+
+		if fresh == 0 {
+			return nil
+		}
+		dateFrom = rows[len(rows)-1].LastChangeDate
+	}
+}
+
+func (c *Client) GetReviews(ctx context.Context) ([]marketplace.ReviewData, error) {
+	var reviews []marketplace.ReviewData
+	err := c.ForEachReviewPage(ctx, func(page []marketplace.ReviewData) error {
+		reviews = append(reviews, page...)
+		return nil
+	})
+	return reviews, err
+}
+
+// ForEachReviewPage streams unanswered feedbacks to fn one page at a time
+func (c *Client) ForEachReviewPage(ctx context.Context, fn func([]marketplace.ReviewData) error) error {
+	type feedback struct {
+		ID               string `json:"id"`
+		Text             string `json:"text"`
+		ProductValuation int    `json:"productValuation"`
+		CreatedDate      string `json:"createdDate"`
+		UserName         string `json:"userName"`
+		ProductDetails   struct {
+			NmID int64 `json:"nmId"`
+		} `json:"productDetails"`
+	}
+
+	for page := 0; ; page++ {
+		if page >= c.maxPages {
+			return fmt.Errorf("%w: stopped after %d pages of feedbacks", ErrPageLimitReached, page)
+		}
+
+		query := url.Values{}
+		query.Set("isAnswered", "false")
+		query.Set("take", strconv.Itoa(c.pageSize))
+		query.Set("skip", strconv.Itoa(page*c.pageSize))
+		query.Set("order", "dateDesc")
+
+		var response struct {
+			Data struct {
+				Feedbacks []feedback `json:"feedbacks"`
+			} `json:"data"`
+			envelope
+		}
+
+		if err := c.doJSON(ctx, "GET", c.feedbacksURL+"/api/v1/feedbacks?"+query.Encode(), nil, &response); err != nil {
+			return err
+		}
+		if err := response.err(); err != nil {
+			return err
+		}
+
+		if len(response.Data.Feedbacks) == 0 {
+			return nil
+		}
+
+		reviews := make([]marketplace.ReviewData, 0, len(response.Data.Feedbacks))
+		for _, f := range response.Data.Feedbacks {
+			createdAt, err := parseTime(f.CreatedDate)
+			if err != nil {
+				return decodeError(err)
+			}
+
+			reviews = append(reviews, marketplace.ReviewData{
+				ExternalID: f.ID,
+				ProductID:  strconv.FormatInt(f.ProductDetails.NmID, 10),
+				AuthorName: f.UserName,
+				Rating:     f.ProductValuation,
+				Comment:    f.Text,
+				Language:   "ru",
+				CreatedAt:  createdAt,
+			})
+		}
+
+		if err := fn(reviews); err != nil {
+			return err
+		}
+
+		if len(response.Data.Feedbacks) < c.pageSize {
+			return nil
+		}
+	}
+}
+
+func (c *Client) PostReviewResponse(ctx context.Context, reviewID, response string) error {
+	payload := map[string]string{
+		"id":   reviewID,
+		"text": response,
+	}
+
+	return c.doJSON(ctx, "POST", c.feedbacksURL+"/api/v1/feedbacks/answer", payload, nil)
+}
+
+// envelope is the error block Wildberries adds to some 200 responses
+type envelope struct {
+	Error     bool   `json:"error"`
+	ErrorText string `json:"errorText"`
+}
+
+func (e envelope) err() error {
+	if !e.Error {
+		return nil
+	}
+	return fmt.Errorf("wildberries: %s", e.ErrorText)
+}
+
+// doJSON sends payload (if any) as JSON and decodes the response into out (if not nil)
+func (c *Client) doJSON(ctx context.Context, method, url string, payload, out interface{}) error {
+	var body []byte
+	if payload != nil {
+		var err error
+		body, err = json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+	}
+
+	resp, err := c.makeRequest(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return decodeError(err)
+	}
+
+	return nil
+}
+
+// makeRequest sends a request and retries it according to the retry policy.
+// Non-2xx responses are returned as *APIError.
+func (c *Client) makeRequest(ctx context.Context, method, url string, body []byte) (*http.Response, error) {
+	c.calls.Add(1)
+
+	resp, err := c.doWithRetry(ctx, method, url, body)
+	if err != nil {
+		c.failures.Add(1)
+	}
+
+	return resp, err
+}
+
+func (c *Client) doWithRetry(ctx context.Context, method, url string, body []byte) (*http.Response, error) {
+	return marketplace.DoWithRetry(ctx, c.httpClient, marketplace.RetryRequest{
+		Marketplace: marketplaceName,
+		Policy:      c.retry,
+		Idempotent:  marketplace.IsIdempotent(method),
+		NewRequest: func(ctx context.Context) (*http.Request, error) {
+			var bodyReader io.Reader
+			if body != nil {
+				bodyReader = bytes.NewReader(body)
+			}
+
+			req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("Authorization", c.token)
+			req.Header.Set("Content-Type", "application/json")
+			return req, nil
+		},
+		// Wildberries sends its wait hint as X-Ratelimit-Retry (seconds)
+		RetryAfterHeaders: []string{"X-Ratelimit-Retry", "Retry-After"},
+		AfterAttempt:      c.countAttempt,
+	})
+}
+
+// countAttempt counts one HTTP request and whether it was throttled
+func (c *Client) countAttempt(status int) {
+	c.attempts.Add(1)
+	if status == http.StatusTooManyRequests {
+		c.throttle.Add(1)
+	}
+}
+
+// Stats returns cumulative call counters for this client
+func (c *Client) Stats() marketplace.ClientStats {
+	calls := c.calls.Load()
+	attempts := c.attempts.Load()
+
+	return marketplace.ClientStats{
+		Calls:     calls,
+		Attempts:  attempts,
+		Retries:   attempts - calls,
+		Throttles: c.throttle.Load(),
+		Failures:  c.failures.Load(),
+	}
+}
+
+func parseNmID(externalID string) (int64, error) {
+	nmID, err := strconv.ParseInt(externalID, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not a Wildberries nmID", ErrProductNotFound, externalID)
+	}
+	return nmID, nil
+}
+
+// parseTime accepts the timestamp formats used across Wildberries APIs
+// (RFC 3339 with or without a zone; statistics dates are Moscow time without one)
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02T15:04:05", value, moscow)
+}
+
+var moscow = time.FixedZone("MSK", 3*60*60)
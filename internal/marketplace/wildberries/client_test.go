@@ -0,0 +1,243 @@
+package wildberries_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/internal/marketplace/wildberries"
+	"github.com/yourusername/seller-assistant/internal/marketplace/wildberries/wbtest"
+)
+
+// newTestServer starts a fake Wildberries server for the test. Its clients make
+// a single attempt unless opts say otherwise.
+func newTestServer(t *testing.T, fixtures *wbtest.Fixtures, opts ...wildberries.Option) (*wbtest.Server, *wildberries.Client) {
+	t.Helper()
+
+	srv := wbtest.NewServer(fixtures)
+	t.Cleanup(srv.Close)
+
+	opts = append([]wildberries.Option{
+		wildberries.WithRetryPolicy(wildberries.RetryPolicy{MaxAttempts: 1}),
+	}, opts...)
+
+	return srv, srv.NewClient(opts...)
+}
+
+func TestGetProducts(t *testing.T) {
+	_, client := newTestServer(t, nil)
+
+	products, err := client.GetProducts(context.Background())
+	if err != nil {
+		t.Fatalf("GetProducts: %v", err)
+	}
+	if len(products) != 3 {
+		t.Fatalf("got %d products, want 3", len(products))
+	}
+
+	// Cards are joined with stocks and prices by nmID; the price is the discounted one
+	p := products[0]
+	if p.ExternalID != "150001" || p.SKU != "TSH-WHT-M" || p.Price != 1200 || p.Currency != "RUB" {
+		t.Errorf("first product = %+v", p)
+	}
+	if p.CurrentStock != 55 || len(p.Stocks) != 2 || p.Stocks[1] != (marketplace.WarehouseStock{WarehouseID: "Казань", Name: "Казань", Quantity: 15}) {
+		t.Errorf("first product stock = %d %+v", p.CurrentStock, p.Stocks)
+	}
+	if p := products[2]; p.ExternalID != "150003" || p.Price != 810 || p.CurrentStock != 0 {
+		t.Errorf("last product = %+v", p)
+	}
+}
+
+func TestProductPagesByCursor(t *testing.T) {
+	_, client := newTestServer(t, nil, wildberries.WithPageSize(1))
+
+	var pages [][]marketplace.ProductData
+	err := client.ForEachProductPage(context.Background(), func(page []marketplace.ProductData) error {
+		pages = append(pages, page)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachProductPage: %v", err)
+	}
+
+	want := []string{"150001", "150002", "150003"}
+	if len(pages) != len(want) {
+		t.Fatalf("got %d pages, want %d", len(pages), len(want))
+	}
+	for i, page := range pages {
+		if len(page) != 1 || page[0].ExternalID != want[i] {
+			t.Errorf("page %d = %+v, want product %s", i+1, page, want[i])
+		}
+	}
+
+	// Prices are paged too, so every product still gets its price
+	if pages[1][0].Price != 4200 {
+		t.Errorf("second product price = %v, want 4200", pages[1][0].Price)
+	}
+}
+
+func TestPageLimitReached(t *testing.T) {
+	_, client := newTestServer(t, nil, wildberries.WithPageSize(1), wildberries.WithMaxPages(1))
+
+	var got []string
+	err := client.ForEachReviewPage(context.Background(), func(page []marketplace.ReviewData) error {
+		for _, r := range page {
+			got = append(got, r.ExternalID)
+		}
+		return nil
+	})
+	if !errors.Is(err, wildberries.ErrPageLimitReached) {
+		t.Fatalf("err = %v, want ErrPageLimitReached", err)
+	}
+
+	// Pages before the limit are delivered
+	if len(got) != 1 || got[0] != "fb-2" {
+		t.Errorf("delivered %v, want the newest feedback", got)
+	}
+
+	_, err = client.GetProducts(context.Background())
+	if !errors.Is(err, wildberries.ErrPageLimitReached) {
+		t.Errorf("GetProducts: err = %v, want ErrPageLimitReached", err)
+	}
+}
+
+func TestPagesStopOnCallbackError(t *testing.T) {
+	srv, client := newTestServer(t, nil, wildberries.WithPageSize(1))
+	stop := errors.New("stop")
+
+	err := client.ForEachReviewPage(context.Background(), func([]marketplace.ReviewData) error {
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("err = %v, want the callback's error", err)
+	}
+	if n := srv.Requests(); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+}
+
+func TestGetProductStock(t *testing.T) {
+	_, client := newTestServer(t, nil)
+	ctx := context.Background()
+
+	stock, err := client.GetProductStock(ctx, "150001")
+	if err != nil {
+		t.Fatalf("GetProductStock: %v", err)
+	}
+	if stock != 55 {
+		t.Errorf("stock = %d, want 55 across both warehouses", stock)
+	}
+
+	if _, err := client.GetProductStock(ctx, "TSH-WHT-M"); !errors.Is(err, wildberries.ErrProductNotFound) {
+		t.Errorf("non-numeric ID: err = %v, want ErrProductNotFound", err)
+	}
+}
+
+func TestReviews(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+	ctx := context.Background()
+
+	reviews, err := client.GetReviews(ctx)
+	if err != nil {
+		t.Fatalf("GetReviews: %v", err)
+	}
+	if len(reviews) != 2 {
+		t.Fatalf("got %d reviews, want 2", len(reviews))
+	}
+
+	// Newest first
+	r := reviews[0]
+	if r.ExternalID != "fb-2" || r.ProductID != "150002" || r.AuthorName != "Игорь" || r.Rating != 2 || r.Language != "ru" {
+		t.Errorf("first review = %+v", r)
+	}
+
+	if err := client.PostReviewResponse(ctx, "fb-1", "Спасибо за отзыв!"); err != nil {
+		t.Fatalf("PostReviewResponse: %v", err)
+	}
+	if answer := srv.Answers()["fb-1"]; answer != "Спасибо за отзыв!" {
+		t.Errorf("answer to fb-1 = %q", answer)
+	}
+
+	// Answered feedbacks are no longer listed
+	reviews, err = client.GetReviews(ctx)
+	if err != nil {
+		t.Fatalf("GetReviews: %v", err)
+	}
+	if len(reviews) != 1 || reviews[0].ExternalID != "fb-2" {
+		t.Errorf("reviews after answer = %+v, want only fb-2", reviews)
+	}
+
+	err = client.PostReviewResponse(ctx, "fb-404", "Спасибо!")
+	if !errors.Is(err, marketplace.ErrNotFound) {
+		t.Errorf("unknown feedback: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestNotSupported(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+	ctx := context.Background()
+
+	_, err := client.GetCompetitorPrices(ctx, "150001", "")
+	if !errors.Is(err, marketplace.ErrNotSupported) {
+		t.Errorf("GetCompetitorPrices: err = %v, want ErrNotSupported", err)
+	}
+	if err := client.UpdateCityPrice(ctx, "150001", "Москва", 1000); !errors.Is(err, marketplace.ErrNotSupported) {
+		t.Errorf("UpdateCityPrice: err = %v, want ErrNotSupported", err)
+	}
+	if err := client.UpdateStock(ctx, "150001", "Коледино", 10); !errors.Is(err, marketplace.ErrNotSupported) {
+		t.Errorf("UpdateStock: err = %v, want ErrNotSupported", err)
+	}
+	if err := client.AcceptOrder(ctx, "srid-00001"); !errors.Is(err, marketplace.ErrNotSupported) {
+		t.Errorf("AcceptOrder: err = %v, want ErrNotSupported", err)
+	}
+
+	if n := srv.Requests(); n != 0 {
+		t.Errorf("unsupported calls sent %d requests, want 0", n)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"feedbacks": [{"id": 1`))
+	}))
+	defer srv.Close()
+
+	client := wildberries.NewClient("token", wildberries.WithBaseURL(srv.URL))
+
+	_, err := client.GetReviews(context.Background())
+	if !errors.Is(err, marketplace.ErrDecode) {
+		t.Errorf("GetReviews: err = %v, want ErrDecode", err)
+	}
+}
+
+func TestAuthErrors(t *testing.T) {
+	fixtures := wbtest.DefaultFixtures()
+	fixtures.Token = "secret"
+	srv := wbtest.NewServer(fixtures)
+	defer srv.Close()
+
+	ctx := context.Background()
+
+	_, err := wildberries.NewClient("wrong-token", wildberries.WithBaseURL(srv.URL)).GetReviews(ctx)
+	if !errors.Is(err, marketplace.ErrUnauthorized) || !marketplace.IsAuthError(err) {
+		t.Errorf("wrong token: err = %v, want an auth error", err)
+	}
+
+	var apiErr *wildberries.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.Marketplace != "wildberries" {
+		t.Errorf("wrong token: err = %v, want a wildberries APIError with status 401", err)
+	}
+
+	// Auth errors are final, so they are not retried
+	if n := srv.Requests(); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+
+	if _, err := wildberries.NewClient("secret", wildberries.WithBaseURL(srv.URL)).GetReviews(ctx); err != nil {
+		t.Errorf("right token: %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package wildberries
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+var (
+	// ErrProductNotFound is returned when Wildberries does not know the requested product.
+	// Errors wrapping it also match marketplace.ErrNotFound.
+	ErrProductNotFound = fmt.Errorf("wildberries: product not found: %w", marketplace.ErrNotFound)
+
+	// ErrInvalidPrice is returned when a price update is attempted with a non-positive price
+	ErrInvalidPrice = errors.New("wildberries: invalid price")
+
+	// ErrPageLimitReached is returned when a list call still had pages left after MaxPages.
+	// Pages fetched before the limit have already been delivered to the caller.
+	ErrPageLimitReached = errors.New("wildberries: page limit reached")
+)
+
+// APIError is returned for any non-2xx response from the Wildberries API
+type APIError = marketplace.APIError
+
+const marketplaceName = "wildberries"
+
+func decodeError(err error) error {
+	return fmt.Errorf("failed to decode response: %w: %v", marketplace.ErrDecode, err)
+}
@@ -0,0 +1,13 @@
+package wildberries
+
+import (
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+// NewFactory returns a marketplace.Factory that builds Wildberries clients with the given options.
+// Wildberries identifies the seller by token alone, so Credentials.MerchantID is not used.
+func NewFactory(opts ...Option) marketplace.Factory {
+	return func(creds marketplace.Credentials) (marketplace.MarketplaceClient, error) {
+		return NewClient(creds.APIKey, opts...), nil
+	}
+}
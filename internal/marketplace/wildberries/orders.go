@@ -0,0 +1,131 @@
+package wildberries
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+func (c *Client) GetOrders(ctx context.Context, since time.Time) ([]marketplace.OrderData, error) {
+	var orders []marketplace.OrderData
+	err := c.ForEachOrderPage(ctx, since, func(page []marketplace.OrderData) error {
+		orders = append(orders, page...)
+		return nil
+	})
+	return orders, err
+}
+
+// ForEachOrderPage streams orders changed since the given time to fn one page at a time.
+// The statistics API reports one row per ordered unit (keyed by srid), so every order has a
+// single line of quantity 1. Wildberries confirms orders itself and the report only knows
+// about cancellations, so rows are either accepted or cancelled; deliveries and returns show
+// up in the sales report instead.
+func (c *Client) ForEachOrderPage(ctx context.Context, since time.Time, fn func([]marketplace.OrderData) error) error {
+	type orderRow struct {
+		Date            string  `json:"date"`
+		LastChangeDate  string  `json:"lastChangeDate"`
+		SRID            string  `json:"srid"`
+		NmID            int64   `json:"nmId"`
+		SupplierArticle string  `json:"supplierArticle"`
+		PriceWithDisc   float64 `json:"priceWithDisc"`
+		RegionName      string  `json:"regionName"`
+		WarehouseName   string  `json:"warehouseName"`
+		WarehouseType   string  `json:"warehouseType"`
+		IsCancel        bool    `json:"isCancel"`
+	}
+
+	dateFrom := since.In(moscow).Format("2006-01-02T15:04:05")
+
+	// Paged by lastChangeDate like the sales report; boundary rows repeat and are skipped by srid
+	seen := make(map[string]bool)
+
+	for page := 0; ; page++ {
+		if page >= c.maxPages {
+			return fmt.Errorf("%w: stopped after %d pages of orders", ErrPageLimitReached, page)
+		}
+
+		query := url.Values{}
+		query.Set("dateFrom", dateFrom)
+
+		var rows []orderRow
+		if err := c.doJSON(ctx, "GET", c.statisticsURL+"/api/v1/supplier/orders?"+query.Encode(), nil, &rows); err != nil {
+			return err
+		}
+
+		orders := make([]marketplace.OrderData, 0, len(rows))
+		for _, r := range rows {
+			if seen[r.SRID] {
+				continue
+			}
+			seen[r.SRID] = true
+
+			createdAt, err := parseTime(r.Date)
+			if err != nil {
+				return decodeError(err)
+			}
+			updatedAt, err := parseTime(r.LastChangeDate)
+			if err != nil {
+				return decodeError(err)
+			}
+
+			status := marketplace.OrderStatusAccepted
+			if r.IsCancel {
+				status = marketplace.OrderStatusCancelled
+			}
+
+			orders = append(orders, marketplace.OrderData{
+				ExternalID:   r.SRID,
+				Status:       status,
+				CustomerCity: r.RegionName,
+				DeliveryType: deliveryType(r.WarehouseType),
+				WarehouseID:  r.WarehouseName,
+				TotalPrice:   r.PriceWithDisc,
+				CreatedAt:    createdAt,
+				UpdatedAt:    updatedAt,
+				Lines: []marketplace.OrderLineData{{
+					ProductExternalID: strconv.FormatInt(r.NmID, 10),
+					Name:              r.SupplierArticle,
+					Quantity:          1,
+					Price:             r.PriceWithDisc,
+				}},
+			})
+		}
+
+		if len(orders) == 0 {
+			return nil
+		}
+		if err := fn(orders); err != nil {
+			return err
+		}
+
+		dateFrom = rows[len(rows)-1].LastChangeDate
+	}
+}
+
+// deliveryType maps the report's warehouse type to a fulfilment scheme
+func deliveryType(warehouseType string) string {
+	if warehouseType == "Склад продавца" {
+		return "fbs"
+	}
+	return "fbo"
+}
+
+// AcceptOrder is not supported: Wildberries confirms orders itself
+func (c *Client) AcceptOrder(ctx context.Context, externalID string) error {
+	return fmt.Errorf("wildberries: accept order: %w", marketplace.ErrNotSupported)
+}
+
+// MarkOrderReady is not supported: FBS assembly tasks live in the Marketplace API under
+// their own IDs, while orders are synced from the statistics report keyed by srid
+func (c *Client) MarkOrderReady(ctx context.Context, externalID string) error {
+	return fmt.Errorf("wildberries: mark order ready: %w", marketplace.ErrNotSupported)
+}
+
+// CancelOrder is not supported for the same reason as MarkOrderReady
+func (c *Client) CancelOrder(ctx context.Context, externalID, reason string) error {
+	return fmt.Errorf("wildberries: cancel order: %w", marketplace.ErrNotSupported)
+}
@@ -0,0 +1,91 @@
+package wildberries_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+func TestGetSalesData(t *testing.T) {
+	_, client := newTestServer(t, nil)
+
+	now := time.Now()
+	sales, err := client.GetSalesData(context.Background(), now.AddDate(0, 0, -30), now)
+	if err != nil {
+		t.Fatalf("GetSalesData: %v", err)
+	}
+
+	// 31 units sold and one returned, each reported as its own row
+	if len(sales) != 32 {
+		t.Fatalf("got %d sales rows, want 32", len(sales))
+	}
+
+	units, returns := 0, 0
+	for _, s := range sales {
+		units += s.QuantitySold
+		if s.QuantitySold < 0 {
+			returns++
+			if s.ProductExternalID != "150001" || s.Revenue != -1080 {
+				t.Errorf("return = %+v", s)
+			}
+		}
+	}
+	if units != 30 || returns != 1 {
+		t.Errorf("units = %d with %d returns, want 30 with 1", units, returns)
+	}
+}
+
+func TestGetSalesDataInRange(t *testing.T) {
+	_, client := newTestServer(t, nil)
+
+	// The fixtures sell three units of 150001 five days ago and nothing else that day
+	day := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -5)
+	sales, err := client.GetSalesData(context.Background(), day, day)
+	if err != nil {
+		t.Fatalf("GetSalesData: %v", err)
+	}
+
+	if len(sales) != 3 {
+		t.Fatalf("got %d sales rows, want 3", len(sales))
+	}
+	for _, s := range sales {
+		if s.ProductExternalID != "150001" || s.QuantitySold != 1 || !s.Date.Equal(day.Add(12*time.Hour)) {
+			t.Errorf("sale = %+v", s)
+		}
+	}
+}
+
+func TestGetOrders(t *testing.T) {
+	_, client := newTestServer(t, nil)
+
+	orders, err := client.GetOrders(context.Background(), time.Now().AddDate(0, 0, -30))
+	if err != nil {
+		t.Fatalf("GetOrders: %v", err)
+	}
+	if len(orders) != 32 {
+		t.Fatalf("got %d orders, want 32", len(orders))
+	}
+
+	byID := make(map[string]marketplace.OrderData, len(orders))
+	for _, o := range orders {
+		byID[o.ExternalID] = o
+	}
+
+	o := byID["srid-00001"]
+	if o.Status != marketplace.OrderStatusAccepted || o.DeliveryType != "fbs" || o.CustomerCity != "Московская" ||
+		o.WarehouseID != "Коледино" || o.TotalPrice != 1080 {
+		t.Errorf("srid-00001 = %+v", o)
+	}
+	if len(o.Lines) != 1 || o.Lines[0] != (marketplace.OrderLineData{ProductExternalID: "150001", Name: "TSH-WHT-M", Quantity: 1, Price: 1080}) {
+		t.Errorf("srid-00001 lines = %+v", o.Lines)
+	}
+	if byID["srid-00002"].DeliveryType != "fbo" {
+		t.Errorf("srid-00002 delivery = %q, want fbo", byID["srid-00002"].DeliveryType)
+	}
+
+	if c := byID["srid-cancel-1"]; c.Status != marketplace.OrderStatusCancelled || !c.UpdatedAt.After(c.CreatedAt) {
+		t.Errorf("srid-cancel-1 = %+v", c)
+	}
+}
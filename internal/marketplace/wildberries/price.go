@@ -0,0 +1,153 @@
+package wildberries
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+// goodsPrice описывает цену товара в ответе discounts-prices API
+type goodsPrice struct {
+	NmID     int64  `json:"nmID"`
+	Currency string `json:"currencyIsoCode4217"`
+	Discount int    `json:"discount"` // скидка продавца, %
+	Sizes    []struct {
+		Price           float64 `json:"price"`
+		DiscountedPrice float64 `json:"discountedPrice"`
+	} `json:"sizes"`
+}
+
+// productPrice — цена для покупателя (с учётом скидки продавца)
+type productPrice struct {
+	price    float64
+	currency string
+	discount int
+}
+
+func (g goodsPrice) toProductPrice() productPrice {
+	p := productPrice{currency: g.Currency, discount: g.Discount}
+	if p.currency == "" {
+		p.currency = "RUB"
+	}
+	if len(g.Sizes) > 0 {
+		p.price = g.Sizes[0].DiscountedPrice
+	}
+	return p
+}
+
+// pricesByNmID загружает цены всех товаров продавца постранично (limit/offset)
+func (c *Client) pricesByNmID(ctx context.Context) (map[int64]productPrice, error) {
+	prices := make(map[int64]productPrice)
+
+	for page := 0; ; page++ {
+		if page >= c.maxPages {
+			return prices, fmt.Errorf("%w: stopped after %d pages of prices", ErrPageLimitReached, page)
+		}
+
+		query := url.Values{}
+		query.Set("limit", strconv.Itoa(c.pageSize))
+		query.Set("offset", strconv.Itoa(page*c.pageSize))
+
+		goods, err := c.listGoods(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, g := range goods {
+			prices[g.NmID] = g.toProductPrice()
+		}
+
+		if len(goods) < c.pageSize {
+			return prices, nil
+		}
+	}
+}
+
+func (c *Client) listGoods(ctx context.Context, query url.Values) ([]goodsPrice, error) {
+	var response struct {
+		Data struct {
+			ListGoods []goodsPrice `json:"listGoods"`
+		} `json:"data"`
+		envelope
+	}
+
+	if err := c.doJSON(ctx, "GET", c.pricesURL+"/api/v2/list/goods/filter?"+query.Encode(), nil, &response); err != nil {
+		return nil, err
+	}
+	if err := response.err(); err != nil {
+		return nil, err
+	}
+
+	return response.Data.ListGoods, nil
+}
+
+// GetCompetitorPrices не поддерживается: API продавца Wildberries не отдаёт
+// предложения других продавцов
+func (c *Client) GetCompetitorPrices(ctx context.Context, productExternalID, city string) ([]marketplace.CompetitorOffer, error) {
+	return nil, fmt.Errorf("wildberries: competitor offers: %w", marketplace.ErrNotSupported)
+}
+
+// UpdateCityPrice не поддерживается: цена на Wildberries одна для всех регионов
+func (c *Client) UpdateCityPrice(ctx context.Context, productExternalID, city string, newPrice float64) error {
+	return fmt.Errorf("wildberries: city prices: %w", marketplace.ErrNotSupported)
+}
+
+// UpdateProductPrice устанавливает цену для покупателя. Wildberries хранит цену
+// до скидки и скидку продавца отдельно, поэтому текущая скидка сохраняется,
+// а базовая цена пересчитывается так, чтобы цена со скидкой была равна newPrice.
+func (c *Client) UpdateProductPrice(ctx context.Context, productExternalID string, newPrice float64) error {
+	if newPrice <= 0 {
+		return fmt.Errorf("%w: %.2f", ErrInvalidPrice, newPrice)
+	}
+
+	nmID, err := parseNmID(productExternalID)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("limit", "1")
+	query.Set("filterNmID", productExternalID)
+
+	goods, err := c.listGoods(ctx, query)
+	if err != nil {
+		return err
+	}
+	if len(goods) == 0 {
+		return fmt.Errorf("%w: %s", ErrProductNotFound, productExternalID)
+	}
+
+	discount := goods[0].Discount
+	basePrice := newPrice
+	if discount > 0 && discount < 100 {
+		basePrice = newPrice * 100 / float64(100-discount)
+	}
+
+	// Цены на Wildberries — целые рубли
+	payload := map[string]interface{}{
+		"data": []map[string]interface{}{
+			{
+				"nmID":     nmID,
+				"price":    int(math.Round(basePrice)),
+				"discount": discount,
+			},
+		},
+	}
+
+	var response envelope
+	if err := c.doJSON(ctx, "POST", c.pricesURL+"/api/v2/upload/task", payload, &response); err != nil {
+		return err
+	}
+
+	return response.err()
+}
+
+// UpdateStock не поддерживается: остатки на складах продавца задаются через
+// Marketplace API по баркодам размеров, а карточки синхронизируются без баркодов
+func (c *Client) UpdateStock(ctx context.Context, productExternalID, warehouseID string, quantity int) error {
+	return fmt.Errorf("wildberries: update stock: %w", marketplace.ErrNotSupported)
+}
@@ -0,0 +1,91 @@
+package wildberries_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/internal/marketplace/wildberries"
+)
+
+func TestUpdateProductPriceKeepsDiscount(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+	ctx := context.Background()
+
+	// 150001 has a 20% seller discount: a buyer price of 1000 means 1250 before it
+	if err := client.UpdateProductPrice(ctx, "150001", 1000); err != nil {
+		t.Fatalf("UpdateProductPrice: %v", err)
+	}
+	if err := client.UpdateProductPrice(ctx, "150002", 3990); err != nil {
+		t.Fatalf("UpdateProductPrice: %v", err)
+	}
+
+	updates := srv.PriceUpdates()
+	if len(updates) != 2 {
+		t.Fatalf("got %d price updates, want 2", len(updates))
+	}
+	if u := updates[0]; u.NmID != 150001 || u.Price != 1250 || u.Discount != 20 {
+		t.Errorf("discounted product update = %+v", u)
+	}
+	if u := updates[1]; u.NmID != 150002 || u.Price != 3990 || u.Discount != 0 {
+		t.Errorf("undiscounted product update = %+v", u)
+	}
+
+	// The new price is what the products list reports afterwards
+	products, err := client.GetProducts(ctx)
+	if err != nil {
+		t.Fatalf("GetProducts: %v", err)
+	}
+	if products[0].Price != 1000 {
+		t.Errorf("price after update = %v, want 1000", products[0].Price)
+	}
+}
+
+func TestUpdateProductPriceRoundsToRubles(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+
+	// 150003 has a 10% discount: 999 / 0.9 = 1110
+	if err := client.UpdateProductPrice(context.Background(), "150003", 999); err != nil {
+		t.Fatalf("UpdateProductPrice: %v", err)
+	}
+	if u := srv.PriceUpdates()[0]; u.Price != 1110 || u.Discount != 10 {
+		t.Errorf("update = %+v, want 1110 with a 10%% discount", u)
+	}
+}
+
+func TestUpdateProductPriceRejectsInvalidPrice(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+
+	for _, price := range []float64{0, -100} {
+		if err := client.UpdateProductPrice(context.Background(), "150001", price); !errors.Is(err, wildberries.ErrInvalidPrice) {
+			t.Errorf("price %v: err = %v, want ErrInvalidPrice", price, err)
+		}
+	}
+	if n := srv.Requests(); n != 0 {
+		t.Errorf("invalid prices sent %d requests, want 0", n)
+	}
+}
+
+func TestUpdateProductPriceNotFound(t *testing.T) {
+	srv, client := newTestServer(t, nil)
+	ctx := context.Background()
+
+	err := client.UpdateProductPrice(ctx, "999999", 1000)
+	if !errors.Is(err, wildberries.ErrProductNotFound) || !errors.Is(err, marketplace.ErrNotFound) {
+		t.Errorf("unknown nmID: err = %v, want ErrProductNotFound and ErrNotFound", err)
+	}
+
+	err = client.UpdateProductPrice(ctx, "TSH-WHT-M", 1000)
+	if !errors.Is(err, wildberries.ErrProductNotFound) {
+		t.Errorf("non-numeric ID: err = %v, want ErrProductNotFound", err)
+	}
+
+	// Only the lookup for the unknown nmID reached the server; nothing was uploaded
+	if n := srv.Requests(); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+	if updates := srv.PriceUpdates(); len(updates) != 0 {
+		t.Errorf("got %d price updates, want 0", len(updates))
+	}
+}
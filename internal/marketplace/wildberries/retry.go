@@ -0,0 +1,28 @@
+package wildberries
+
+import (
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+// RetryPolicy controls how failed requests are retried; see marketplace.RetryPolicy
+type RetryPolicy = marketplace.RetryPolicy
+
+// DefaultRetryPolicy is used unless overridden with WithRetryPolicy.
+// Wildberries limits some methods to one call per minute, hence the long MaxDelay.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   time.Second,
+	MaxDelay:    65 * time.Second,
+}
+
+// WithRetryPolicy overrides the retry policy. MaxAttempts of 1 disables retries.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		if policy.MaxAttempts < 1 {
+			policy.MaxAttempts = 1
+		}
+		c.retry = policy
+	}
+}
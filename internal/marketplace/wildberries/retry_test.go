@@ -0,0 +1,137 @@
+package wildberries_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/internal/marketplace/wildberries"
+)
+
+// fastRetries retries quickly but still honors a retry hint of a second
+var fastRetries = wildberries.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Second}
+
+func TestRetryHintOn429(t *testing.T) {
+	srv, client := newTestServer(t, nil, wildberries.WithRetryPolicy(fastRetries))
+	srv.FailNext(1, http.StatusTooManyRequests, time.Second)
+
+	start := time.Now()
+	if _, err := client.GetReviews(context.Background()); err != nil {
+		t.Fatalf("GetReviews: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least the 1s X-Ratelimit-Retry", elapsed)
+	}
+	if n := srv.Requests(); n != 2 {
+		t.Errorf("server got %d requests, want 2", n)
+	}
+
+	stats := client.Stats()
+	if stats.Calls != 1 || stats.Retries != 1 || stats.Throttles != 1 || stats.Failures != 0 {
+		t.Errorf("stats = %+v, want 1 call with 1 retry and 1 throttle", stats)
+	}
+}
+
+func TestRetryHintLongerThanMaxDelay(t *testing.T) {
+	srv, client := newTestServer(t, nil, wildberries.WithRetryPolicy(fastRetries))
+	srv.FailNext(1, http.StatusTooManyRequests, time.Minute)
+
+	start := time.Now()
+	_, err := client.GetReviews(context.Background())
+	if !errors.Is(err, marketplace.ErrRateLimited) {
+		t.Fatalf("err = %v, want ErrRateLimited", err)
+	}
+
+	// The client gives up instead of sleeping past MaxDelay
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("gave up after %v, want right away", elapsed)
+	}
+	if n := srv.Requests(); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+}
+
+func TestRetryOn5xx(t *testing.T) {
+	srv, client := newTestServer(t, nil, wildberries.WithRetryPolicy(fastRetries))
+	srv.FailNext(1, http.StatusServiceUnavailable, 0)
+	srv.FailNext(1, http.StatusBadGateway, 0)
+
+	stock, err := client.GetProductStock(context.Background(), "150002")
+	if err != nil {
+		t.Fatalf("GetProductStock: %v", err)
+	}
+	if stock != 3 {
+		t.Errorf("stock = %d, want 3", stock)
+	}
+	if n := srv.Requests(); n != 3 {
+		t.Errorf("server got %d requests, want 3", n)
+	}
+}
+
+func TestNoRetryOn4xx(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			srv, client := newTestServer(t, nil, wildberries.WithRetryPolicy(fastRetries))
+			srv.FailNext(1, status, 0)
+
+			_, err := client.GetReviews(context.Background())
+
+			var apiErr *wildberries.APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != status {
+				t.Fatalf("err = %v, want an APIError with status %d", err, status)
+			}
+			if n := srv.Requests(); n != 1 {
+				t.Errorf("server got %d requests, want 1", n)
+			}
+		})
+	}
+}
+
+func TestNoRetryOn5xxForPost(t *testing.T) {
+	srv, client := newTestServer(t, nil, wildberries.WithRetryPolicy(fastRetries))
+	ctx := context.Background()
+	srv.FailNext(1, http.StatusServiceUnavailable, 0)
+
+	err := client.PostReviewResponse(ctx, "fb-1", "Спасибо за отзыв!")
+	if !errors.Is(err, marketplace.ErrUnavailable) {
+		t.Fatalf("err = %v, want ErrUnavailable", err)
+	}
+	if n := srv.Requests(); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+	if len(srv.Answers()) != 0 {
+		t.Errorf("answers = %v, want none", srv.Answers())
+	}
+
+	// 429 means the request was not processed, so even a POST is retried
+	srv.FailNext(1, http.StatusTooManyRequests, 0)
+	if err := client.PostReviewResponse(ctx, "fb-1", "Спасибо за отзыв!"); err != nil {
+		t.Fatalf("PostReviewResponse after 429: %v", err)
+	}
+	if n := srv.Requests(); n != 3 {
+		t.Errorf("server got %d requests, want 3", n)
+	}
+}
+
+func TestRetryAttemptCap(t *testing.T) {
+	srv, client := newTestServer(t, nil, wildberries.WithRetryPolicy(fastRetries))
+	srv.FailNext(10, http.StatusInternalServerError, 0)
+
+	_, err := client.GetReviews(context.Background())
+	if !errors.Is(err, marketplace.ErrUnavailable) {
+		t.Fatalf("err = %v, want ErrUnavailable", err)
+	}
+
+	if n := srv.Requests(); n != fastRetries.MaxAttempts {
+		t.Errorf("server got %d requests, want %d", n, fastRetries.MaxAttempts)
+	}
+
+	stats := client.Stats()
+	if stats.Calls != 1 || stats.Attempts != int64(fastRetries.MaxAttempts) || stats.Failures != 1 {
+		t.Errorf("stats = %+v, want 1 failed call with %d attempts", stats, fastRetries.MaxAttempts)
+	}
+}
@@ -0,0 +1,153 @@
+package wbtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Card is a product card served by the fake content API
+type Card struct {
+	NmID       int64     `json:"nm_id"`
+	VendorCode string    `json:"vendor_code"`
+	Title      string    `json:"title"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Stock is the quantity of a product at one Wildberries warehouse
+type Stock struct {
+	NmID      int64  `json:"nm_id"`
+	Warehouse string `json:"warehouse"`
+	Quantity  int    `json:"quantity"`
+}
+
+// Price is a product's pre-discount price and the seller's discount in percent
+type Price struct {
+	NmID     int64   `json:"nm_id"`
+	Price    float64 `json:"price"`
+	Discount int     `json:"discount"`
+}
+
+// Sale is one unit sold (SaleID starting with "S") or returned ("R")
+type Sale struct {
+	SaleID         string    `json:"sale_id"`
+	NmID           int64     `json:"nm_id"`
+	Date           time.Time `json:"date"`
+	LastChangeDate time.Time `json:"last_change_date"`
+	ForPay         float64   `json:"for_pay"`
+}
+
+// Order is one ordered unit, identified by its SRID
+type Order struct {
+	SRID           string    `json:"srid"`
+	NmID           int64     `json:"nm_id"`
+	Date           time.Time `json:"date"`
+	LastChangeDate time.Time `json:"last_change_date"`
+	PriceWithDisc  float64   `json:"price_with_disc"`
+	Region         string    `json:"region"`
+	Warehouse      string    `json:"warehouse"`    // warehouse the unit ships from
+	SellerStock    bool      `json:"seller_stock"` // shipped from the seller's warehouse (FBS)
+	IsCancel       bool      `json:"is_cancel"`
+}
+
+// Feedback is a buyer's feedback on a product
+type Feedback struct {
+	ID          string    `json:"id"`
+	NmID        int64     `json:"nm_id"`
+	UserName    string    `json:"user_name"`
+	Text        string    `json:"text"`
+	Valuation   int       `json:"valuation"`
+	CreatedDate time.Time `json:"created_date"`
+	Answer      string    `json:"answer,omitempty"`
+}
+
+// Fixtures is the seed data for a fake Wildberries seller
+type Fixtures struct {
+	Token     string     `json:"token"` // empty accepts any token
+	Cards     []Card     `json:"cards"`
+	Stocks    []Stock    `json:"stocks"`
+	Prices    []Price    `json:"prices"`
+	Sales     []Sale     `json:"sales"`
+	Orders    []Order    `json:"orders"`
+	Feedbacks []Feedback `json:"feedbacks"`
+}
+
+// LoadFixtures reads fixtures from a JSON file
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+
+	var f Fixtures
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures: %w", err)
+	}
+
+	return &f, nil
+}
+
+// DefaultFixtures returns a small demo catalog with stocks, prices, sales and feedbacks
+func DefaultFixtures() *Fixtures {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	f := &Fixtures{
+		Cards: []Card{
+			{NmID: 150001, VendorCode: "TSH-WHT-M", Title: "Футболка хлопковая белая M", UpdatedAt: today.Add(-72 * time.Hour)},
+			{NmID: 150002, VendorCode: "HDY-GRY-L", Title: "Худи оверсайз серое L", UpdatedAt: today.Add(-48 * time.Hour)},
+			{NmID: 150003, VendorCode: "SCK-BLK-5", Title: "Носки черные, 5 пар", UpdatedAt: today.Add(-24 * time.Hour)},
+		},
+		Stocks: []Stock{
+			{NmID: 150001, Warehouse: "Коледино", Quantity: 40},
+			{NmID: 150001, Warehouse: "Казань", Quantity: 15},
+			{NmID: 150002, Warehouse: "Коледино", Quantity: 3},
+			{NmID: 150003, Warehouse: "Электросталь", Quantity: 0},
+		},
+		Prices: []Price{
+			{NmID: 150001, Price: 1500, Discount: 20},
+			{NmID: 150002, Price: 4200, Discount: 0},
+			{NmID: 150003, Price: 900, Discount: 10},
+		},
+		Feedbacks: []Feedback{
+			{ID: "fb-1", NmID: 150001, UserName: "Ольга", Text: "Хорошая ткань, размер в размер", Valuation: 5, CreatedDate: today.Add(-30 * time.Hour)},
+			{ID: "fb-2", NmID: 150002, UserName: "Игорь", Text: "Пришло с затяжкой на рукаве", Valuation: 2, CreatedDate: today.Add(-6 * time.Hour)},
+		},
+	}
+
+	n := 0
+	for i := 0; i < 14; i++ {
+		day := today.AddDate(0, 0, -i).Add(12 * time.Hour)
+		for j := 0; j < 1+i%3; j++ {
+			n++
+			f.Sales = append(f.Sales, Sale{
+				SaleID: fmt.Sprintf("S%08d", n), NmID: 150001, Date: day, LastChangeDate: day.Add(time.Duration(j) * time.Minute), ForPay: 1080,
+			})
+		}
+		if i%4 == 0 {
+			n++
+			f.Sales = append(f.Sales, Sale{
+				SaleID: fmt.Sprintf("S%08d", n), NmID: 150002, Date: day, LastChangeDate: day.Add(10 * time.Minute), ForPay: 3780,
+			})
+		}
+	}
+	regions := []string{"Московская", "Татарстан", "Санкт-Петербург"}
+	warehouses := []string{"Коледино", "Казань", "Коледино"}
+	for i, s := range f.Sales {
+		f.Orders = append(f.Orders, Order{
+			SRID: fmt.Sprintf("srid-%05d", i+1), NmID: s.NmID, Date: s.Date.Add(-36 * time.Hour), LastChangeDate: s.Date.Add(-36 * time.Hour),
+			PriceWithDisc: s.ForPay, Region: regions[i%3], Warehouse: warehouses[i%3], SellerStock: i%5 == 0,
+		})
+	}
+	f.Orders = append(f.Orders, Order{
+		SRID: "srid-cancel-1", NmID: 150003, Date: today.Add(-40 * time.Hour), LastChangeDate: today.Add(-20 * time.Hour),
+		PriceWithDisc: 810, Region: "Московская", Warehouse: "Коледино", IsCancel: true,
+	})
+
+	n++
+	f.Sales = append(f.Sales, Sale{
+		SaleID: fmt.Sprintf("R%08d", n), NmID: 150001, Date: today.Add(-12 * time.Hour), LastChangeDate: today.Add(-12 * time.Hour), ForPay: -1080,
+	})
+
+	return f
+}
@@ -0,0 +1,536 @@
+package wbtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// salesPageLimit caps rows per sales report response (80000 on the real API)
+	salesPageLimit = 100
+
+	// statisticsTimeLayout is how the statistics API formats dates (Moscow time, no zone)
+	statisticsTimeLayout = "2006-01-02T15:04:05"
+)
+
+var moscow = time.FixedZone("MSK", 3*60*60)
+
+// PriceUpdate records a price upload received by the fake server
+type PriceUpdate struct {
+	NmID     int64     `json:"nm_id"`
+	Price    float64   `json:"price"`
+	Discount int       `json:"discount"`
+	At       time.Time `json:"at"`
+}
+
+// Handler serves the Wildberries seller APIs (content, statistics, prices, feedbacks)
+// from in-memory fixtures on a single host. It is safe for concurrent use.
+type Handler struct {
+	mu           sync.Mutex
+	fixtures     *Fixtures
+	priceUpdates []PriceUpdate
+	faults       []fault
+	requests     int
+}
+
+// fault is a canned error response returned instead of the real one
+type fault struct {
+	status     int
+	retryAfter time.Duration
+}
+
+// NewHandler creates a handler seeded with the given fixtures (DefaultFixtures if nil)
+func NewHandler(fixtures *Fixtures) *Handler {
+	if fixtures == nil {
+		fixtures = DefaultFixtures()
+	}
+
+	return &Handler{fixtures: fixtures}
+}
+
+// Token returns the token the handler accepts (empty accepts any)
+func (h *Handler) Token() string {
+	return h.fixtures.Token
+}
+
+// PriceUpdates returns all price uploads received so far
+func (h *Handler) PriceUpdates() []PriceUpdate {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	updates := make([]PriceUpdate, len(h.priceUpdates))
+	copy(updates, h.priceUpdates)
+	return updates
+}
+
+// Answers returns posted feedback answers keyed by feedback ID
+func (h *Handler) Answers() map[string]string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	answers := make(map[string]string)
+	for _, f := range h.fixtures.Feedbacks {
+		if f.Answer != "" {
+			answers[f.ID] = f.Answer
+		}
+	}
+	return answers
+}
+
+// FailNext makes the next count requests fail with status (e.g. 429 or 503).
+// A positive retryAfter is sent as an X-Ratelimit-Retry header in whole seconds.
+func (h *Handler) FailNext(count, status int, retryAfter time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i := 0; i < count; i++ {
+		h.faults = append(h.faults, fault{status: status, retryAfter: retryAfter})
+	}
+}
+
+// Requests returns the number of requests received, including failed ones
+func (h *Handler) Requests() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.requests
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.injectFault(w) {
+		return
+	}
+
+	token := r.Header.Get("Authorization")
+	if token == "" || (h.fixtures.Token != "" && token != h.fixtures.Token) {
+		writeError(w, http.StatusUnauthorized, "invalid or missing token")
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch route := r.Method + " " + r.URL.Path; route {
+	case "POST /content/v2/get/cards/list":
+		h.listCards(w, r)
+	case "GET /api/v1/supplier/stocks":
+		h.listStocks(w)
+	case "GET /api/v1/supplier/sales":
+		h.listSales(w, r)
+	case "GET /api/v1/supplier/orders":
+		h.listOrders(w, r)
+	case "GET /api/v2/list/goods/filter":
+		h.listGoods(w, r)
+	case "POST /api/v2/upload/task":
+		h.uploadPrices(w, r)
+	case "GET /api/v1/feedbacks":
+		h.listFeedbacks(w, r)
+	case "POST /api/v1/feedbacks/answer":
+		h.answerFeedback(w, r)
+	default:
+		writeError(w, http.StatusNotFound, "unknown endpoint")
+	}
+}
+
+func (h *Handler) injectFault(w http.ResponseWriter) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.requests++
+	if len(h.faults) == 0 {
+		return false
+	}
+
+	f := h.faults[0]
+	h.faults = h.faults[1:]
+
+	if f.retryAfter > 0 {
+		w.Header().Set("X-Ratelimit-Retry", strconv.Itoa(int(f.retryAfter.Seconds())))
+	}
+	writeError(w, f.status, http.StatusText(f.status))
+	return true
+}
+
+func (h *Handler) listCards(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Settings struct {
+			Cursor struct {
+				Limit     int    `json:"limit"`
+				UpdatedAt string `json:"updatedAt"`
+				NmID      int64  `json:"nmID"`
+			} `json:"cursor"`
+		} `json:"settings"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	cursor := req.Settings.Cursor
+	if cursor.Limit < 1 || cursor.Limit > 100 {
+		writeError(w, http.StatusBadRequest, "limit must be between 1 and 100")
+		return
+	}
+
+	cards := make([]Card, len(h.fixtures.Cards))
+	copy(cards, h.fixtures.Cards)
+	sort.Slice(cards, func(i, j int) bool {
+		if !cards[i].UpdatedAt.Equal(cards[j].UpdatedAt) {
+			return cards[i].UpdatedAt.Before(cards[j].UpdatedAt)
+		}
+		return cards[i].NmID < cards[j].NmID
+	})
+
+	// Skip everything up to and including the cursor position
+	if cursor.UpdatedAt != "" {
+		after, err := time.Parse(time.RFC3339Nano, cursor.UpdatedAt)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid cursor")
+			return
+		}
+		i := sort.Search(len(cards), func(i int) bool {
+			c := cards[i]
+			return c.UpdatedAt.After(after) || (c.UpdatedAt.Equal(after) && c.NmID > cursor.NmID)
+		})
+		cards = cards[i:]
+	}
+
+	if len(cards) > cursor.Limit {
+		cards = cards[:cursor.Limit]
+	}
+
+	type cardJSON struct {
+		NmID       int64  `json:"nmID"`
+		VendorCode string `json:"vendorCode"`
+		Title      string `json:"title"`
+		UpdatedAt  string `json:"updatedAt"`
+	}
+
+	out := make([]cardJSON, 0, len(cards))
+	for _, c := range cards {
+		out = append(out, cardJSON{
+			NmID:       c.NmID,
+			VendorCode: c.VendorCode,
+			Title:      c.Title,
+			UpdatedAt:  c.UpdatedAt.UTC().Format(time.RFC3339Nano),
+		})
+	}
+
+	next := map[string]interface{}{"total": len(out)}
+	if len(out) > 0 {
+		next["updatedAt"] = out[len(out)-1].UpdatedAt
+		next["nmID"] = out[len(out)-1].NmID
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"cards": out, "cursor": next})
+}
+
+func (h *Handler) listStocks(w http.ResponseWriter) {
+	vendorCodes := h.vendorCodes()
+
+	rows := make([]map[string]interface{}, 0, len(h.fixtures.Stocks))
+	for _, s := range h.fixtures.Stocks {
+		rows = append(rows, map[string]interface{}{
+			"nmId":            s.NmID,
+			"supplierArticle": vendorCodes[s.NmID],
+			"warehouseName":   s.Warehouse,
+			"quantity":        s.Quantity,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, rows)
+}
+
+func (h *Handler) listSales(w http.ResponseWriter, r *http.Request) {
+	from, err := parseDateFrom(r.URL.Query().Get("dateFrom"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "dateFrom is required (YYYY-MM-DD or YYYY-MM-DDTHH:MM:SS)")
+		return
+	}
+
+	sales := make([]Sale, 0, len(h.fixtures.Sales))
+	for _, s := range h.fixtures.Sales {
+		if !s.LastChangeDate.Before(from) {
+			sales = append(sales, s)
+		}
+	}
+	sort.SliceStable(sales, func(i, j int) bool {
+		return sales[i].LastChangeDate.Before(sales[j].LastChangeDate)
+	})
+	if len(sales) > salesPageLimit {
+		sales = sales[:salesPageLimit]
+	}
+
+	vendorCodes := h.vendorCodes()
+
+	rows := make([]map[string]interface{}, 0, len(sales))
+	for _, s := range sales {
+		rows = append(rows, map[string]interface{}{
+			"saleID":          s.SaleID,
+			"nmId":            s.NmID,
+			"supplierArticle": vendorCodes[s.NmID],
+			"date":            s.Date.In(moscow).Format(statisticsTimeLayout),
+			"lastChangeDate":  s.LastChangeDate.In(moscow).Format(statisticsTimeLayout),
+			"forPay":          s.ForPay,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, rows)
+}
+
+func (h *Handler) listOrders(w http.ResponseWriter, r *http.Request) {
+	from, err := parseDateFrom(r.URL.Query().Get("dateFrom"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "dateFrom is required (YYYY-MM-DD or YYYY-MM-DDTHH:MM:SS)")
+		return
+	}
+
+	orders := make([]Order, 0, len(h.fixtures.Orders))
+	for _, o := range h.fixtures.Orders {
+		if !o.LastChangeDate.Before(from) {
+			orders = append(orders, o)
+		}
+	}
+	sort.SliceStable(orders, func(i, j int) bool {
+		return orders[i].LastChangeDate.Before(orders[j].LastChangeDate)
+	})
+	if len(orders) > salesPageLimit {
+		orders = orders[:salesPageLimit]
+	}
+
+	vendorCodes := h.vendorCodes()
+
+	rows := make([]map[string]interface{}, 0, len(orders))
+	for _, o := range orders {
+		warehouseType := "Склад WB"
+		if o.SellerStock {
+			warehouseType = "Склад продавца"
+		}
+
+		rows = append(rows, map[string]interface{}{
+			"srid":            o.SRID,
+			"nmId":            o.NmID,
+			"supplierArticle": vendorCodes[o.NmID],
+			"date":            o.Date.In(moscow).Format(statisticsTimeLayout),
+			"lastChangeDate":  o.LastChangeDate.In(moscow).Format(statisticsTimeLayout),
+			"priceWithDisc":   o.PriceWithDisc,
+			"regionName":      o.Region,
+			"warehouseName":   o.Warehouse,
+			"warehouseType":   warehouseType,
+			"isCancel":        o.IsCancel,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, rows)
+}
+
+func (h *Handler) listGoods(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	if limit < 1 {
+		limit = 10
+	}
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	filterNmID, _ := strconv.ParseInt(query.Get("filterNmID"), 10, 64)
+
+	prices := make([]Price, 0, len(h.fixtures.Prices))
+	for _, p := range h.fixtures.Prices {
+		if filterNmID == 0 || p.NmID == filterNmID {
+			prices = append(prices, p)
+		}
+	}
+
+	from, to := bounds(offset, limit, len(prices))
+	vendorCodes := h.vendorCodes()
+
+	goods := make([]map[string]interface{}, 0, to-from)
+	for _, p := range prices[from:to] {
+		goods = append(goods, map[string]interface{}{
+			"nmID":                p.NmID,
+			"vendorCode":          vendorCodes[p.NmID],
+			"currencyIsoCode4217": "RUB",
+			"discount":            p.Discount,
+			"sizes": []map[string]interface{}{
+				{"price": p.Price, "discountedPrice": discounted(p.Price, p.Discount)},
+			},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  map[string]interface{}{"listGoods": goods},
+		"error": false,
+	})
+}
+
+func (h *Handler) uploadPrices(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Data []struct {
+			NmID     int64   `json:"nmID"`
+			Price    float64 `json:"price"`
+			Discount *int    `json:"discount"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Data) == 0 {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	for _, item := range req.Data {
+		if item.Price <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid price")
+			return
+		}
+		if h.findPrice(item.NmID) == nil {
+			writeError(w, http.StatusBadRequest, "unknown nmID "+strconv.FormatInt(item.NmID, 10))
+			return
+		}
+	}
+
+	for _, item := range req.Data {
+		p := h.findPrice(item.NmID)
+		p.Price = item.Price
+		if item.Discount != nil {
+			p.Discount = *item.Discount
+		}
+
+		h.priceUpdates = append(h.priceUpdates, PriceUpdate{
+			NmID:     item.NmID,
+			Price:    p.Price,
+			Discount: p.Discount,
+			At:       time.Now(),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":  map[string]interface{}{"id": len(h.priceUpdates), "alreadyExists": false},
+		"error": false,
+	})
+}
+
+func (h *Handler) listFeedbacks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	answered, err := strconv.ParseBool(query.Get("isAnswered"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "isAnswered is required")
+		return
+	}
+	take, _ := strconv.Atoi(query.Get("take"))
+	if take < 1 || take > 5000 {
+		writeError(w, http.StatusBadRequest, "take must be between 1 and 5000")
+		return
+	}
+	skip, _ := strconv.Atoi(query.Get("skip"))
+
+	feedbacks := make([]Feedback, 0, len(h.fixtures.Feedbacks))
+	for _, f := range h.fixtures.Feedbacks {
+		if (f.Answer != "") == answered {
+			feedbacks = append(feedbacks, f)
+		}
+	}
+	sort.SliceStable(feedbacks, func(i, j int) bool {
+		return feedbacks[i].CreatedDate.After(feedbacks[j].CreatedDate)
+	})
+
+	from, to := bounds(skip, take, len(feedbacks))
+	vendorCodes := h.vendorCodes()
+
+	out := make([]map[string]interface{}, 0, to-from)
+	for _, f := range feedbacks[from:to] {
+		out = append(out, map[string]interface{}{
+			"id":               f.ID,
+			"text":             f.Text,
+			"productValuation": f.Valuation,
+			"createdDate":      f.CreatedDate.UTC().Format(time.RFC3339),
+			"userName":         f.UserName,
+			"productDetails": map[string]interface{}{
+				"nmId":            f.NmID,
+				"supplierArticle": vendorCodes[f.NmID],
+			},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":      map[string]interface{}{"feedbacks": out, "countUnanswered": len(feedbacks)},
+		"error":     false,
+		"errorText": "",
+	})
+}
+
+func (h *Handler) answerFeedback(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID   string `json:"id"`
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Text == "" {
+		writeError(w, http.StatusBadRequest, "id and text are required")
+		return
+	}
+
+	for i := range h.fixtures.Feedbacks {
+		if h.fixtures.Feedbacks[i].ID == req.ID {
+			h.fixtures.Feedbacks[i].Answer = req.Text
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "feedback not found")
+}
+
+func (h *Handler) findPrice(nmID int64) *Price {
+	for i := range h.fixtures.Prices {
+		if h.fixtures.Prices[i].NmID == nmID {
+			return &h.fixtures.Prices[i]
+		}
+	}
+	return nil
+}
+
+func (h *Handler) vendorCodes() map[int64]string {
+	codes := make(map[int64]string, len(h.fixtures.Cards))
+	for _, c := range h.fixtures.Cards {
+		codes[c.NmID] = c.VendorCode
+	}
+	return codes
+}
+
+func parseDateFrom(value string) (time.Time, error) {
+	if strings.Contains(value, "T") {
+		return time.ParseInLocation(statisticsTimeLayout, value, moscow)
+	}
+	return time.ParseInLocation("2006-01-02", value, moscow)
+}
+
+func discounted(price float64, discount int) float64 {
+	return float64(int(price * float64(100-discount) / 100))
+}
+
+// bounds returns the slice bounds for offset/limit paging
+func bounds(offset, limit, total int) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > total {
+		offset = total
+	}
+	to := offset + limit
+	if to > total {
+		to = total
+	}
+	return offset, to
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{"title": http.StatusText(status), "detail": message})
+}
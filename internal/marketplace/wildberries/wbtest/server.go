@@ -0,0 +1,50 @@
+// Package wbtest provides an in-memory fake of the Wildberries seller APIs
+// for integration tests and local demos.
+package wbtest
+
+import (
+	"net/http/httptest"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace/wildberries"
+)
+
+// Server is a fake Wildberries API running on a local httptest listener
+type Server struct {
+	*Handler
+	URL string
+
+	srv *httptest.Server
+}
+
+// NewServer starts a fake Wildberries server seeded with fixtures (DefaultFixtures if nil).
+// Callers must Close it when done.
+func NewServer(fixtures *Fixtures) *Server {
+	h := NewHandler(fixtures)
+	srv := httptest.NewServer(h)
+
+	return &Server{
+		Handler: h,
+		URL:     srv.URL,
+		srv:     srv,
+	}
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// NewClient returns a wildberries.Client pointed at this server
+func (s *Server) NewClient(opts ...wildberries.Option) *wildberries.Client {
+	token := s.Token()
+	if token == "" {
+		token = "test-token"
+	}
+
+	opts = append([]wildberries.Option{
+		wildberries.WithBaseURL(s.URL),
+		wildberries.WithHTTPClient(s.srv.Client()),
+	}, opts...)
+
+	return wildberries.NewClient(token, opts...)
+}
@@ -0,0 +1,221 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ConnectionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewConnectionRepository(db *Database) *ConnectionRepository {
+	return &ConnectionRepository{
+		collection: db.DB.Collection("marketplace_connections"),
+	}
+}
+
+func (r *ConnectionRepository) Create(ctx context.Context, conn *domain.MarketplaceConnection) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	conn.CreatedAt = time.Now()
+	conn.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("failed to create connection: %w", err)
+	}
+
+	conn.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return nil
+}
+
+func (r *ConnectionRepository) GetByID(ctx context.Context, id string) (*domain.MarketplaceConnection, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection ID: %w", err)
+	}
+
+	var conn domain.MarketplaceConnection
+	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&conn)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	return &conn, nil
+}
+
+func (r *ConnectionRepository) GetByUserID(ctx context.Context, userID string) ([]domain.MarketplaceConnection, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connections: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var conns []domain.MarketplaceConnection
+	if err := cursor.All(ctx, &conns); err != nil {
+		return nil, fmt.Errorf("failed to decode connections: %w", err)
+	}
+
+	return conns, nil
+}
+
+func (r *ConnectionRepository) GetAllActive(ctx context.Context) ([]domain.MarketplaceConnection, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, bson.M{"is_active": true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active connections: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var conns []domain.MarketplaceConnection
+	if err := cursor.All(ctx, &conns); err != nil {
+		return nil, fmt.Errorf("failed to decode active connections: %w", err)
+	}
+
+	return conns, nil
+}
+
+func (r *ConnectionRepository) Update(ctx context.Context, conn *domain.MarketplaceConnection) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	conn.UpdatedAt = time.Now()
+
+	oid, err := primitive.ObjectIDFromHex(conn.ID)
+	if err != nil {
+		return fmt.Errorf("invalid connection ID: %w", err)
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"name":                 conn.Name,
+			"api_key_encrypted":    conn.APIKeyEncrypted,
+			"api_secret_encrypted": conn.APISecretEncrypted,
+			"merchant_id":          conn.MerchantID,
+			"pickup_points":        conn.PickupPoints,
+			"is_active":            conn.IsActive,
+			"auth_failures":        conn.AuthFailures,
+			"deactivated_reason":   conn.DeactivatedReason,
+			"deactivated_at":       conn.DeactivatedAt,
+			"last_sync_at":         conn.LastSyncAt,
+			"updated_at":           conn.UpdatedAt,
+		},
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": oid}, update)
+	return err
+}
+
+// UpdateSyncState records when the connection was last synced. It leaves
+// updated_at alone, which tracks changes made by the user.
+func (r *ConnectionRepository) UpdateSyncState(ctx context.Context, id string, lastSyncAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid connection ID: %w", err)
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{"last_sync_at": lastSyncAt}})
+	return err
+}
+
+// IncrementAuthFailures counts one more consecutive auth failure and returns the new total
+func (r *ConnectionRepository) IncrementAuthFailures(ctx context.Context, id string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, fmt.Errorf("invalid connection ID: %w", err)
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"auth_failures": 1})
+
+	var conn domain.MarketplaceConnection
+	err = r.collection.FindOneAndUpdate(ctx, bson.M{"_id": oid}, bson.M{"$inc": bson.M{"auth_failures": 1}}, opts).Decode(&conn)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count auth failure: %w", err)
+	}
+
+	return conn.AuthFailures, nil
+}
+
+// ResetAuthFailures clears the consecutive auth failures after a successful call
+func (r *ConnectionRepository) ResetAuthFailures(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid connection ID: %w", err)
+	}
+
+	filter := bson.M{"_id": oid, "auth_failures": bson.M{"$gt": 0}}
+	_, err = r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"auth_failures": 0}})
+	return err
+}
+
+// Deactivate switches an active connection off. It reports false when the connection
+// was already inactive, so only the first of several concurrent callers acts on it.
+func (r *ConnectionRepository) Deactivate(ctx context.Context, id, reason string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid connection ID: %w", err)
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"is_active":          false,
+			"deactivated_reason": reason,
+			"deactivated_at":     time.Now(),
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": oid, "is_active": true}, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to deactivate connection: %w", err)
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+func (r *ConnectionRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid connection ID: %w", err)
+	}
+
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
@@ -17,7 +17,29 @@ type Database struct {
 	Database string
 }
 
+// NewDB connects to MongoDB, migrates legacy data and creates the indexes
 func NewDB(mongoURI, dbName string) (*Database, error) {
+	database, err := Connect(mongoURI, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Move data written before multi-connection support; must run before the new unique indexes
+	if err := database.MigrateLegacyKaspiKeys(); err != nil {
+		return nil, fmt.Errorf("failed to migrate kaspi keys: %w", err)
+	}
+
+	// Create indexes
+	if err := database.CreateIndexes(); err != nil {
+		return nil, fmt.Errorf("failed to create indexes: %w", err)
+	}
+
+	return database, nil
+}
+
+// Connect connects to MongoDB without migrating or creating indexes, for tools that must
+// not change the schema of the database the API server runs on
+func Connect(mongoURI, dbName string) (*Database, error) {
 	// Increase timeout for cloud MongoDB (like Atlas)
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -46,18 +68,11 @@ func NewDB(mongoURI, dbName string) (*Database, error) {
 
 	db := client.Database(dbName)
 
-	database := &Database{
+	return &Database{
 		Client:   client,
 		DB:       db,
 		Database: dbName,
-	}
-
-	// Create indexes
-	if err := database.CreateIndexes(); err != nil {
-		return nil, fmt.Errorf("failed to create indexes: %w", err)
-	}
-
-	return database, nil
+	}, nil
 }
 
 func (d *Database) Close() error {
@@ -74,18 +89,25 @@ func (d *Database) CreateIndexes() error {
 	// Users indexes - email is already indexed in user repository
 	// No additional indexes needed here as email index is created in EnsureIndexes()
 
-	// Kaspi keys indexes
-	kaspiIndexes := []mongo.IndexModel{
+	// Marketplace connections indexes (many per user)
+	connectionIndexes := []mongo.IndexModel{
 		{
-			Keys:    bson.D{{Key: "user_id", Value: 1}},
-			Options: options.Index().SetUnique(true),
+			Keys: bson.D{{Key: "user_id", Value: 1}},
 		},
 		{
 			Keys: bson.D{{Key: "is_active", Value: 1}},
 		},
 	}
-	if _, err := d.DB.Collection("kaspi_keys").Indexes().CreateMany(ctx, kaspiIndexes); err != nil {
-		return fmt.Errorf("failed to create kaspi_keys indexes: %w", err)
+	if _, err := d.DB.Collection("marketplace_connections").Indexes().CreateMany(ctx, connectionIndexes); err != nil {
+		return fmt.Errorf("failed to create marketplace_connections indexes: %w", err)
+	}
+
+	// External IDs used to be unique per user; they are now unique per connection,
+	// since two shops of one user can list the same marketplace product
+	for _, collection := range []string{"products", "reviews"} {
+		if err := d.dropIndexIfExists(ctx, collection, "user_id_1_external_id_1"); err != nil {
+			return err
+		}
 	}
 
 	// Products indexes
@@ -97,7 +119,7 @@ func (d *Database) CreateIndexes() error {
 			Keys: bson.D{{Key: "days_of_stock", Value: 1}},
 		},
 		{
-			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "external_id", Value: 1}},
+			Keys:    bson.D{{Key: "connection_id", Value: 1}, {Key: "external_id", Value: 1}},
 			Options: options.Index().SetUnique(true),
 		},
 	}
@@ -117,6 +139,9 @@ func (d *Database) CreateIndexes() error {
 			Keys:    bson.D{{Key: "product_id", Value: 1}, {Key: "date", Value: 1}},
 			Options: options.Index().SetUnique(true),
 		},
+		{
+			Keys: bson.D{{Key: "connection_id", Value: 1}},
+		},
 	}
 	if _, err := d.DB.Collection("sales_history").Indexes().CreateMany(ctx, salesIndexes); err != nil {
 		return fmt.Errorf("failed to create sales_history indexes: %w", err)
@@ -131,7 +156,7 @@ func (d *Database) CreateIndexes() error {
 			Keys: bson.D{{Key: "ai_response_sent", Value: 1}},
 		},
 		{
-			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "external_id", Value: 1}},
+			Keys:    bson.D{{Key: "connection_id", Value: 1}, {Key: "external_id", Value: 1}},
 			Options: options.Index().SetUnique(true),
 		},
 	}
@@ -139,6 +164,41 @@ func (d *Database) CreateIndexes() error {
 		return fmt.Errorf("failed to create reviews indexes: %w", err)
 	}
 
+	// Orders indexes
+	ordersIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "connection_id", Value: 1}, {Key: "external_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "ordered_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "connection_id", Value: 1}, {Key: "ordered_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "lines.product_id", Value: 1}, {Key: "ordered_at", Value: -1}},
+		},
+	}
+	if _, err := d.DB.Collection("orders").Indexes().CreateMany(ctx, ordersIndexes); err != nil {
+		return fmt.Errorf("failed to create orders indexes: %w", err)
+	}
+
+	// Price feeds indexes
+	feedIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "connection_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "token", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+	if _, err := d.DB.Collection("price_feeds").Indexes().CreateMany(ctx, feedIndexes); err != nil {
+		return fmt.Errorf("failed to create price_feeds indexes: %w", err)
+	}
+
 	// Low stock alerts indexes
 	alertsIndexes := []mongo.IndexModel{
 		{
@@ -155,5 +215,100 @@ func (d *Database) CreateIndexes() error {
 		return fmt.Errorf("failed to create low_stock_alerts indexes: %w", err)
 	}
 
+	// Tag pricing strategies indexes (one per user and tag)
+	tagStrategyIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "tag", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+	if _, err := d.DB.Collection("tag_pricing_strategies").Indexes().CreateMany(ctx, tagStrategyIndexes); err != nil {
+		return fmt.Errorf("failed to create tag_pricing_strategies indexes: %w", err)
+	}
+
+	// Competitor filters indexes
+	competitorFilterIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+	if _, err := d.DB.Collection("competitor_filters").Indexes().CreateMany(ctx, competitorFilterIndexes); err != nil {
+		return fmt.Errorf("failed to create competitor_filters indexes: %w", err)
+	}
+
+	// Price guards indexes
+	priceGuardIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+	if _, err := d.DB.Collection("price_guards").Indexes().CreateMany(ctx, priceGuardIndexes); err != nil {
+		return fmt.Errorf("failed to create price_guards indexes: %w", err)
+	}
+
+	// Price changes indexes
+	priceChangeIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "product_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "connection_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "created_at", Value: 1}},
+		},
+	}
+	if _, err := d.DB.Collection("price_changes").Indexes().CreateMany(ctx, priceChangeIndexes); err != nil {
+		return fmt.Errorf("failed to create price_changes indexes: %w", err)
+	}
+
+	// Dry-run price proposals indexes
+	priceProposalIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "product_id", Value: 1}, {Key: "city", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "proposed_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "connection_id", Value: 1}},
+		},
+	}
+	if _, err := d.DB.Collection("price_proposals").Indexes().CreateMany(ctx, priceProposalIndexes); err != nil {
+		return fmt.Errorf("failed to create price_proposals indexes: %w", err)
+	}
+
+	// Price guard alerts indexes
+	priceGuardAlertIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "connection_id", Value: 1}},
+		},
+	}
+	if _, err := d.DB.Collection("price_guard_alerts").Indexes().CreateMany(ctx, priceGuardAlertIndexes); err != nil {
+		return fmt.Errorf("failed to create price_guard_alerts indexes: %w", err)
+	}
+
+	// Competitor offer snapshots indexes
+	offerSnapshotIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "product_id", Value: 1}, {Key: "city", Value: 1}, {Key: "observed_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "connection_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "observed_at", Value: 1}},
+		},
+	}
+	if _, err := d.DB.Collection("competitor_offers").Indexes().CreateMany(ctx, offerSnapshotIndexes); err != nil {
+		return fmt.Errorf("failed to create competitor_offers indexes: %w", err)
+	}
+
 	return nil
 }
@@ -0,0 +1,116 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MigrateLegacyKaspiKeys copies keys from the old one-per-user kaspi_keys collection
+// into marketplace_connections, keeping their IDs, and tags each owner's existing
+// products, reviews and sales history with that connection. It is safe to run on
+// every start: already migrated keys and tagged documents are skipped.
+func (d *Database) MigrateLegacyKaspiKeys() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	cursor, err := d.DB.Collection("kaspi_keys").Find(ctx, bson.M{})
+	if err != nil {
+		return fmt.Errorf("failed to read kaspi keys: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var keys []bson.M
+	if err := cursor.All(ctx, &keys); err != nil {
+		return fmt.Errorf("failed to decode kaspi keys: %w", err)
+	}
+
+	connections := d.DB.Collection("marketplace_connections")
+
+	for _, key := range keys {
+		id, ok := key["_id"].(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+		userID, _ := key["user_id"].(string)
+
+		key["marketplace"] = domain.MarketplaceKaspi
+		if _, ok := key["name"]; !ok {
+			key["name"] = "Kaspi"
+		}
+
+		_, err := connections.InsertOne(ctx, key)
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("failed to migrate kaspi key %s: %w", id.Hex(), err)
+		}
+
+		if err := d.tagLegacyDocuments(ctx, userID, id.Hex()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tagLegacyDocuments sets connection_id on a user's documents that predate connections
+func (d *Database) tagLegacyDocuments(ctx context.Context, userID, connectionID string) error {
+	untagged := bson.M{"user_id": userID, "connection_id": bson.M{"$exists": false}}
+	tag := bson.M{"$set": bson.M{"connection_id": connectionID}}
+
+	products := d.DB.Collection("products")
+
+	cursor, err := products.Find(ctx, untagged)
+	if err != nil {
+		return fmt.Errorf("failed to find legacy products: %w", err)
+	}
+
+	var legacy []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &legacy); err != nil {
+		return fmt.Errorf("failed to decode legacy products: %w", err)
+	}
+
+	if len(legacy) > 0 {
+		productIDs := make([]string, 0, len(legacy))
+		for _, p := range legacy {
+			productIDs = append(productIDs, p.ID.Hex())
+		}
+
+		salesFilter := bson.M{"product_id": bson.M{"$in": productIDs}, "connection_id": bson.M{"$exists": false}}
+		if _, err := d.DB.Collection("sales_history").UpdateMany(ctx, salesFilter, tag); err != nil {
+			return fmt.Errorf("failed to tag legacy sales history: %w", err)
+		}
+
+		if _, err := products.UpdateMany(ctx, untagged, tag); err != nil {
+			return fmt.Errorf("failed to tag legacy products: %w", err)
+		}
+	}
+
+	if _, err := d.DB.Collection("reviews").UpdateMany(ctx, untagged, tag); err != nil {
+		return fmt.Errorf("failed to tag legacy reviews: %w", err)
+	}
+
+	return nil
+}
+
+// dropIndexIfExists drops a named index, ignoring a missing index or collection
+func (d *Database) dropIndexIfExists(ctx context.Context, collection, name string) error {
+	_, err := d.DB.Collection(collection).Indexes().DropOne(ctx, name)
+
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && (cmdErr.Code == 26 || cmdErr.Code == 27) { // NamespaceNotFound, IndexNotFound
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to drop index %s.%s: %w", collection, name, err)
+	}
+
+	return nil
+}
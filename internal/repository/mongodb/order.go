@@ -0,0 +1,208 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OrderRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOrderRepository(db *Database) *OrderRepository {
+	return &OrderRepository{
+		collection: db.DB.Collection("orders"),
+	}
+}
+
+func (r *OrderRepository) GetByID(ctx context.Context, id string) (*domain.Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order ID: %w", err)
+	}
+
+	var order domain.Order
+	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&order)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	return &order, nil
+}
+
+// GetByUserID returns the user's orders, newest first
+func (r *OrderRepository) GetByUserID(ctx context.Context, userID string, filter domain.OrderFilter) ([]domain.Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := bson.M{"user_id": userID}
+	if filter.ConnectionID != "" {
+		query["connection_id"] = filter.ConnectionID
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if !filter.Since.IsZero() {
+		query["ordered_at"] = bson.M{"$gte": filter.Since}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "ordered_at", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orders: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var orders []domain.Order
+	if err := cursor.All(ctx, &orders); err != nil {
+		return nil, fmt.Errorf("failed to decode orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// GetByConnectionID returns the connection's orders placed at or after since
+func (r *OrderRepository) GetByConnectionID(ctx context.Context, connectionID string, since time.Time) ([]domain.Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"connection_id": connectionID,
+		"ordered_at":    bson.M{"$gte": since},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orders: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var orders []domain.Order
+	if err := cursor.All(ctx, &orders); err != nil {
+		return nil, fmt.Errorf("failed to decode orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// GetByProductID returns orders placed at or after since that contain the product
+func (r *OrderRepository) GetByProductID(ctx context.Context, productID string, since time.Time) ([]domain.Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"lines.product_id": productID,
+		"ordered_at":       bson.M{"$gte": since},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orders: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var orders []domain.Order
+	if err := cursor.All(ctx, &orders); err != nil {
+		return nil, fmt.Errorf("failed to decode orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+func (r *OrderRepository) UpsertOrder(ctx context.Context, order *domain.Order) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	order.UpdatedAt = now
+	if order.CreatedAt.IsZero() {
+		order.CreatedAt = now
+	}
+
+	filter := bson.M{
+		"connection_id": order.ConnectionID,
+		"external_id":   order.ExternalID,
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"user_id":       order.UserID,
+			"marketplace":   order.Marketplace,
+			"status":        order.Status,
+			"customer_city": order.CustomerCity,
+			"delivery_type": order.DeliveryType,
+			"warehouse_id":  order.WarehouseID,
+			"total_price":   order.TotalPrice,
+			"lines":         order.Lines,
+			"ordered_at":    order.OrderedAt,
+			"changed_at":    order.ChangedAt,
+			"last_sync_at":  order.LastSyncAt,
+			"updated_at":    order.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{
+			"created_at": order.CreatedAt,
+		},
+	}
+
+	opts := options.Update().SetUpsert(true)
+	if _, err := r.collection.UpdateOne(ctx, filter, update, opts); err != nil {
+		return fmt.Errorf("failed to upsert order: %w", err)
+	}
+
+	return nil
+}
+
+// RecordStatusChange sets the order's status and appends the change to its history
+func (r *OrderRepository) RecordStatusChange(ctx context.Context, id string, change domain.OrderStatusChange) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid order ID: %w", err)
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":     change.To,
+			"updated_at": time.Now(),
+		},
+		"$push": bson.M{
+			"history": change,
+		},
+	}
+
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": oid}, update); err != nil {
+		return fmt.Errorf("failed to record order status change: %w", err)
+	}
+
+	return nil
+}
+
+func (r *OrderRepository) DeleteByConnectionID(ctx context.Context, connectionID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"connection_id": connectionID}); err != nil {
+		return fmt.Errorf("failed to delete orders: %w", err)
+	}
+
+	return nil
+}
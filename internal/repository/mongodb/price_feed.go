@@ -0,0 +1,128 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PriceFeedRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPriceFeedRepository(db *Database) *PriceFeedRepository {
+	return &PriceFeedRepository{
+		collection: db.DB.Collection("price_feeds"),
+	}
+}
+
+// Save upserts the content of the feed of feed.ConnectionID. The token is written only
+// when the feed is created, so a regeneration never undoes a concurrent UpdateToken;
+// feed is refreshed from the stored document and carries the token in use.
+func (r *PriceFeedRepository) Save(ctx context.Context, feed *domain.PriceFeed) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	feed.UpdatedAt = now
+	if feed.CreatedAt.IsZero() {
+		feed.CreatedAt = now
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"content":       feed.Content,
+			"offer_count":   feed.OfferCount,
+			"skipped_count": feed.SkippedCount,
+			"generated_at":  feed.GeneratedAt,
+			"updated_at":    feed.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{
+			"user_id":    feed.UserID,
+			"token":      feed.Token,
+			"created_at": feed.CreatedAt,
+		},
+	}
+
+	if err := r.upsert(ctx, feed, update); err != nil {
+		return fmt.Errorf("failed to save price feed: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateToken replaces the token of the feed of feed.ConnectionID, creating an empty
+// feed if there is none yet. The content is left as is.
+func (r *PriceFeedRepository) UpdateToken(ctx context.Context, feed *domain.PriceFeed) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	feed.UpdatedAt = now
+	if feed.CreatedAt.IsZero() {
+		feed.CreatedAt = now
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"token":      feed.Token,
+			"updated_at": feed.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{
+			"user_id":    feed.UserID,
+			"created_at": feed.CreatedAt,
+		},
+	}
+
+	if err := r.upsert(ctx, feed, update); err != nil {
+		return fmt.Errorf("failed to update price feed token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PriceFeedRepository) GetByConnectionID(ctx context.Context, connectionID string) (*domain.PriceFeed, error) {
+	return r.findOne(ctx, bson.M{"connection_id": connectionID})
+}
+
+func (r *PriceFeedRepository) GetByToken(ctx context.Context, token string) (*domain.PriceFeed, error) {
+	return r.findOne(ctx, bson.M{"token": token})
+}
+
+func (r *PriceFeedRepository) DeleteByConnectionID(ctx context.Context, connectionID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"connection_id": connectionID}); err != nil {
+		return fmt.Errorf("failed to delete price feed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PriceFeedRepository) findOne(ctx context.Context, filter bson.M) (*domain.PriceFeed, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var feed domain.PriceFeed
+	err := r.collection.FindOne(ctx, filter).Decode(&feed)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price feed: %w", err)
+	}
+
+	return &feed, nil
+}
+
+// upsert applies update to the feed of feed.ConnectionID and decodes the result into feed
+func (r *PriceFeedRepository) upsert(ctx context.Context, feed *domain.PriceFeed, update bson.M) error {
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	return r.collection.FindOneAndUpdate(ctx, bson.M{"connection_id": feed.ConnectionID}, update, opts).Decode(feed)
+}
@@ -0,0 +1,555 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TagPricingStrategyRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTagPricingStrategyRepository(db *Database) *TagPricingStrategyRepository {
+	return &TagPricingStrategyRepository{
+		collection: db.DB.Collection("tag_pricing_strategies"),
+	}
+}
+
+// Save upserts the strategy of strategy.UserID and strategy.Tag
+func (r *TagPricingStrategyRepository) Save(ctx context.Context, strategy *domain.TagPricingStrategy) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	strategy.UpdatedAt = now
+	if strategy.CreatedAt.IsZero() {
+		strategy.CreatedAt = now
+	}
+
+	filter := bson.M{
+		"user_id": strategy.UserID,
+		"tag":     strategy.Tag,
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"strategy":   strategy.Strategy,
+			"updated_at": strategy.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{
+			"created_at": strategy.CreatedAt,
+		},
+	}
+
+	opts := options.Update().SetUpsert(true)
+	if _, err := r.collection.UpdateOne(ctx, filter, update, opts); err != nil {
+		return fmt.Errorf("failed to save tag pricing strategy: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TagPricingStrategyRepository) GetByUserID(ctx context.Context, userID string) ([]domain.TagPricingStrategy, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "tag", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag pricing strategies: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var strategies []domain.TagPricingStrategy
+	if err := cursor.All(ctx, &strategies); err != nil {
+		return nil, fmt.Errorf("failed to decode tag pricing strategies: %w", err)
+	}
+
+	return strategies, nil
+}
+
+func (r *TagPricingStrategyRepository) GetByTag(ctx context.Context, userID, tag string) (*domain.TagPricingStrategy, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var strategy domain.TagPricingStrategy
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID, "tag": tag}).Decode(&strategy)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag pricing strategy: %w", err)
+	}
+
+	return &strategy, nil
+}
+
+func (r *TagPricingStrategyRepository) Delete(ctx context.Context, userID, tag string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"user_id": userID, "tag": tag}); err != nil {
+		return fmt.Errorf("failed to delete tag pricing strategy: %w", err)
+	}
+
+	return nil
+}
+
+type CompetitorFilterRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCompetitorFilterRepository(db *Database) *CompetitorFilterRepository {
+	return &CompetitorFilterRepository{
+		collection: db.DB.Collection("competitor_filters"),
+	}
+}
+
+// Save upserts the competitor filter of filter.UserID
+func (r *CompetitorFilterRepository) Save(ctx context.Context, filter *domain.UserCompetitorFilter) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	filter.UpdatedAt = now
+	if filter.CreatedAt.IsZero() {
+		filter.CreatedAt = now
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"filter":     filter.Filter,
+			"updated_at": filter.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{
+			"created_at": filter.CreatedAt,
+		},
+	}
+
+	opts := options.Update().SetUpsert(true)
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"user_id": filter.UserID}, update, opts); err != nil {
+		return fmt.Errorf("failed to save competitor filter: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CompetitorFilterRepository) GetByUserID(ctx context.Context, userID string) (*domain.UserCompetitorFilter, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var filter domain.UserCompetitorFilter
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID}).Decode(&filter)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get competitor filter: %w", err)
+	}
+
+	return &filter, nil
+}
+
+func (r *CompetitorFilterRepository) Delete(ctx context.Context, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"user_id": userID}); err != nil {
+		return fmt.Errorf("failed to delete competitor filter: %w", err)
+	}
+
+	return nil
+}
+
+type PriceGuardRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPriceGuardRepository(db *Database) *PriceGuardRepository {
+	return &PriceGuardRepository{
+		collection: db.DB.Collection("price_guards"),
+	}
+}
+
+// Save upserts the price guard of guard.UserID
+func (r *PriceGuardRepository) Save(ctx context.Context, guard *domain.UserPriceGuard) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	guard.UpdatedAt = now
+	if guard.CreatedAt.IsZero() {
+		guard.CreatedAt = now
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"guard":      guard.Guard,
+			"updated_at": guard.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{
+			"created_at": guard.CreatedAt,
+		},
+	}
+
+	opts := options.Update().SetUpsert(true)
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"user_id": guard.UserID}, update, opts); err != nil {
+		return fmt.Errorf("failed to save price guard: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PriceGuardRepository) GetByUserID(ctx context.Context, userID string) (*domain.UserPriceGuard, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var guard domain.UserPriceGuard
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID}).Decode(&guard)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price guard: %w", err)
+	}
+
+	return &guard, nil
+}
+
+func (r *PriceGuardRepository) Delete(ctx context.Context, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"user_id": userID}); err != nil {
+		return fmt.Errorf("failed to delete price guard: %w", err)
+	}
+
+	return nil
+}
+
+type PriceChangeRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPriceChangeRepository(db *Database) *PriceChangeRepository {
+	return &PriceChangeRepository{
+		collection: db.DB.Collection("price_changes"),
+	}
+}
+
+func (r *PriceChangeRepository) Create(ctx context.Context, change *domain.PriceChange) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if change.CreatedAt.IsZero() {
+		change.CreatedAt = time.Now()
+	}
+
+	result, err := r.collection.InsertOne(ctx, change)
+	if err != nil {
+		return fmt.Errorf("failed to create price change: %w", err)
+	}
+
+	change.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return nil
+}
+
+// GetByProductID returns the product's price changes since the given time, newest first
+func (r *PriceChangeRepository) GetByProductID(ctx context.Context, productID string, since time.Time, limit int) ([]domain.PriceChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"product_id": productID,
+		"created_at": bson.M{"$gte": since},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price changes: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var changes []domain.PriceChange
+	if err := cursor.All(ctx, &changes); err != nil {
+		return nil, fmt.Errorf("failed to decode price changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// DeleteOlderThan removes price changes made before the given time and returns how many were removed
+func (r *PriceChangeRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteMany(ctx, bson.M{"created_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old price changes: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
+func (r *PriceChangeRepository) DeleteByConnectionID(ctx context.Context, connectionID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"connection_id": connectionID}); err != nil {
+		return fmt.Errorf("failed to delete price changes: %w", err)
+	}
+
+	return nil
+}
+
+type OfferSnapshotRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOfferSnapshotRepository(db *Database) *OfferSnapshotRepository {
+	return &OfferSnapshotRepository{
+		collection: db.DB.Collection("competitor_offers"),
+	}
+}
+
+func (r *OfferSnapshotRepository) Create(ctx context.Context, snapshot *domain.OfferSnapshot) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if snapshot.ObservedAt.IsZero() {
+		snapshot.ObservedAt = time.Now()
+	}
+
+	result, err := r.collection.InsertOne(ctx, snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to create offer snapshot: %w", err)
+	}
+
+	snapshot.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return nil
+}
+
+// GetLatest returns the product's most recent snapshot in a city ("" for all cities), or nil if there is none
+func (r *OfferSnapshotRepository) GetLatest(ctx context.Context, productID, city string) (*domain.OfferSnapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"product_id": productID, "city": cityFilter(city)}
+	opts := options.FindOne().SetSort(bson.D{{Key: "observed_at", Value: -1}})
+
+	var snapshot domain.OfferSnapshot
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&snapshot)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest offer snapshot: %w", err)
+	}
+
+	return &snapshot, nil
+}
+
+// GetByProductID returns the product's snapshots in a city ("" for all cities) since the given time, oldest first
+func (r *OfferSnapshotRepository) GetByProductID(ctx context.Context, productID, city string, since time.Time) ([]domain.OfferSnapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"product_id":  productID,
+		"city":        cityFilter(city),
+		"observed_at": bson.M{"$gte": since},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "observed_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get offer snapshots: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var snapshots []domain.OfferSnapshot
+	if err := cursor.All(ctx, &snapshots); err != nil {
+		return nil, fmt.Errorf("failed to decode offer snapshots: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// DeleteOlderThan removes snapshots observed before the given time and returns how many were removed
+func (r *OfferSnapshotRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	result, err := r.collection.DeleteMany(ctx, bson.M{"observed_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old offer snapshots: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
+func (r *OfferSnapshotRepository) DeleteByConnectionID(ctx context.Context, connectionID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"connection_id": connectionID}); err != nil {
+		return fmt.Errorf("failed to delete offer snapshots: %w", err)
+	}
+
+	return nil
+}
+
+type PriceProposalRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPriceProposalRepository(db *Database) *PriceProposalRepository {
+	return &PriceProposalRepository{
+		collection: db.DB.Collection("price_proposals"),
+	}
+}
+
+// Save replaces the proposal of proposal.ProductID in proposal.City
+func (r *PriceProposalRepository) Save(ctx context.Context, proposal *domain.PriceProposal) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if proposal.ProposedAt.IsZero() {
+		proposal.ProposedAt = time.Now()
+	}
+
+	filter := bson.M{
+		"product_id": proposal.ProductID,
+		"city":       cityFilter(proposal.City),
+	}
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.collection.ReplaceOne(ctx, filter, proposal, opts); err != nil {
+		return fmt.Errorf("failed to save price proposal: %w", err)
+	}
+
+	return nil
+}
+
+// GetByUserID returns the user's proposals made since the given time, optionally of one
+// connection, ordered by product and city
+func (r *PriceProposalRepository) GetByUserID(ctx context.Context, userID, connectionID string, since time.Time) ([]domain.PriceProposal, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"user_id":     userID,
+		"proposed_at": bson.M{"$gte": since},
+	}
+	if connectionID != "" {
+		filter["connection_id"] = connectionID
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "product_name", Value: 1}, {Key: "city", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price proposals: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var proposals []domain.PriceProposal
+	if err := cursor.All(ctx, &proposals); err != nil {
+		return nil, fmt.Errorf("failed to decode price proposals: %w", err)
+	}
+
+	return proposals, nil
+}
+
+func (r *PriceProposalRepository) DeleteByConnectionID(ctx context.Context, connectionID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"connection_id": connectionID}); err != nil {
+		return fmt.Errorf("failed to delete price proposals: %w", err)
+	}
+
+	return nil
+}
+
+type PriceGuardAlertRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPriceGuardAlertRepository(db *Database) *PriceGuardAlertRepository {
+	return &PriceGuardAlertRepository{
+		collection: db.DB.Collection("price_guard_alerts"),
+	}
+}
+
+func (r *PriceGuardAlertRepository) Create(ctx context.Context, alert *domain.PriceGuardAlert) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if alert.CreatedAt.IsZero() {
+		alert.CreatedAt = time.Now()
+	}
+
+	result, err := r.collection.InsertOne(ctx, alert)
+	if err != nil {
+		return fmt.Errorf("failed to create price guard alert: %w", err)
+	}
+
+	alert.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return nil
+}
+
+// GetByUserID returns the user's alerts created since a time, newest first
+func (r *PriceGuardAlertRepository) GetByUserID(ctx context.Context, userID string, since time.Time) ([]domain.PriceGuardAlert, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"user_id":    userID,
+		"created_at": bson.M{"$gte": since},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price guard alerts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var alerts []domain.PriceGuardAlert
+	if err := cursor.All(ctx, &alerts); err != nil {
+		return nil, fmt.Errorf("failed to decode price guard alerts: %w", err)
+	}
+
+	return alerts, nil
+}
+
+func (r *PriceGuardAlertRepository) DeleteByConnectionID(ctx context.Context, connectionID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"connection_id": connectionID}); err != nil {
+		return fmt.Errorf("failed to delete price guard alerts: %w", err)
+	}
+
+	return nil
+}
+
+// cityFilter matches documents of one city; those of all cities or the base price have no city field
+func cityFilter(city string) interface{} {
+	if city == "" {
+		return bson.M{"$exists": false}
+	}
+	return city
+}
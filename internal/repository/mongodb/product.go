@@ -22,8 +22,8 @@ func NewProductRepository(db *Database) *ProductRepository {
 	}
 }
 
-func (r *ProductRepository) Create(product *domain.Product) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *ProductRepository) Create(ctx context.Context, product *domain.Product) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	product.CreatedAt = time.Now()
@@ -38,8 +38,8 @@ func (r *ProductRepository) Create(product *domain.Product) error {
 	return nil
 }
 
-func (r *ProductRepository) Update(product *domain.Product) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *ProductRepository) Update(ctx context.Context, product *domain.Product) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	product.UpdatedAt = time.Now()
@@ -52,10 +52,18 @@ func (r *ProductRepository) Update(product *domain.Product) error {
 	update := bson.M{
 		"$set": bson.M{
 			"current_stock":        product.CurrentStock,
+			"stocks":               product.Stocks,
 			"price":                product.Price,
 			"min_price":            product.MinPrice,
+			"max_price":            product.MaxPrice,
 			"competitor_min_price": product.CompetitorMinPrice,
+			"city_prices":          product.CityPrices,
 			"auto_dumping_enabled": product.AutoDumpingEnabled,
+			"dumping_dry_run":      product.DumpingDryRun,
+			"pricing_strategy":     product.Strategy,
+			"costs":                product.Costs,
+			"tags":                 product.Tags,
+			"competitor_filter":    product.CompetitorFilter,
 			"sales_velocity":       product.SalesVelocity,
 			"days_of_stock":        product.DaysOfStock,
 			"last_price_check_at":  product.LastPriceCheckAt,
@@ -68,8 +76,8 @@ func (r *ProductRepository) Update(product *domain.Product) error {
 	return err
 }
 
-func (r *ProductRepository) UpdatePrice(id string, newPrice float64, competitorMinPrice float64) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *ProductRepository) UpdatePrice(ctx context.Context, id string, newPrice float64, competitorMinPrice float64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	oid, err := primitive.ObjectIDFromHex(id)
@@ -90,16 +98,131 @@ func (r *ProductRepository) UpdatePrice(id string, newPrice float64, competitorM
 	return err
 }
 
-func (r *ProductRepository) GetProductsForDumping(userID string) ([]domain.Product, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *ProductRepository) UpdateCityPrice(ctx context.Context, id, city string, newPrice float64, competitorMinPrice float64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid product ID: %w", err)
+	}
+
+	filter := bson.M{
+		"_id":              oid,
+		"city_prices.city": city,
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"city_prices.$.price":                newPrice,
+			"city_prices.$.competitor_min_price": competitorMinPrice,
+			"city_prices.$.last_price_check_at":  time.Now(),
+			"updated_at":                         time.Now(),
+		},
+	}
+
+	if _, err := r.collection.UpdateOne(ctx, filter, update); err != nil {
+		return fmt.Errorf("failed to update city price: %w", err)
+	}
+	return nil
+}
+
+// UpsertCityPrice stores the price the marketplace reports for a city. The city's
+// min price and competitor data set on our side are kept.
+func (r *ProductRepository) UpsertCityPrice(ctx context.Context, connectionID, externalID, city string, price float64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"connection_id":    connectionID,
+		"external_id":      externalID,
+		"city_prices.city": city,
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{
+		"$set": bson.M{"city_prices.$.price": price},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update city price: %w", err)
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	// The city is new for this product
+	filter = bson.M{
+		"connection_id":    connectionID,
+		"external_id":      externalID,
+		"city_prices.city": bson.M{"$ne": city},
+	}
+
+	_, err = r.collection.UpdateOne(ctx, filter, bson.M{
+		"$push": bson.M{"city_prices": domain.CityPrice{City: city, Price: price}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add city price: %w", err)
+	}
+	return nil
+}
+
+// SetCityPrice sets the price and limits of the product in one city, adding the city if the
+// product has no price there yet. The other cities are left alone.
+func (r *ProductRepository) SetCityPrice(ctx context.Context, id string, cityPrice domain.CityPrice) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid product ID: %w", err)
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"city_prices.$[c].price":     cityPrice.Price,
+			"city_prices.$[c].min_price": cityPrice.MinPrice,
+			"city_prices.$[c].max_price": cityPrice.MaxPrice,
+			"updated_at":                 time.Now(),
+		},
+	}
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{
+		Filters: []interface{}{bson.M{"c.city": cityPrice.City}},
+	})
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": oid, "city_prices.city": cityPrice.City}, update, opts)
+	if err != nil {
+		return fmt.Errorf("failed to update city price: %w", err)
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	// The city is new for this product
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": oid, "city_prices.city": bson.M{"$ne": cityPrice.City}}, bson.M{
+		"$push": bson.M{"city_prices": cityPrice},
+		"$set":  bson.M{"updated_at": time.Now()},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add city price: %w", err)
+	}
+	return nil
+}
+
+func (r *ProductRepository) GetProductsForDumping(ctx context.Context, connectionID string) ([]domain.Product, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	filter := bson.M{
-		"user_id":              userID,
-		"auto_dumping_enabled": true,
+		"connection_id": connectionID,
+		"$or": bson.A{
+			bson.M{"auto_dumping_enabled": true},
+			bson.M{"dumping_dry_run": true}, // Товары в режиме dry-run тоже обрабатываются
+		},
 		"current_stock": bson.M{
 			"$gt": 0, // Только товары в наличии
 		},
+		"dumping_paused_until": bson.M{
+			"$not": bson.M{"$gt": time.Now()}, // Кроме приостановленных защитой от ценовой войны
+		},
 	}
 
 	cursor, err := r.collection.Find(ctx, filter)
@@ -116,8 +239,140 @@ func (r *ProductRepository) GetProductsForDumping(userID string) ([]domain.Produ
 	return products, nil
 }
 
-func (r *ProductRepository) UpsertProduct(product *domain.Product) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// SetDumpingPause pauses dumping of a product until a time, or resumes it with the current time
+func (r *ProductRepository) SetDumpingPause(ctx context.Context, id string, until time.Time, reason string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid product ID: %w", err)
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"dumping_paused_until": until,
+			"dumping_pause_reason": reason,
+			"updated_at":           time.Now(),
+		},
+	}
+
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": oid}, update); err != nil {
+		return fmt.Errorf("failed to set dumping pause: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePriceLimits sets the product's min and max price, leaving the rest of it alone
+func (r *ProductRepository) UpdatePriceLimits(ctx context.Context, id string, minPrice, maxPrice float64) error {
+	if err := r.setFields(ctx, id, bson.M{"min_price": minPrice, "max_price": maxPrice}); err != nil {
+		return fmt.Errorf("failed to update price limits: %w", err)
+	}
+	return nil
+}
+
+// UpdateStrategy sets the product's own pricing strategy; nil clears it
+func (r *ProductRepository) UpdateStrategy(ctx context.Context, id string, strategy *domain.PricingStrategyConfig) error {
+	if err := r.setFields(ctx, id, bson.M{"pricing_strategy": strategy}); err != nil {
+		return fmt.Errorf("failed to update pricing strategy: %w", err)
+	}
+	return nil
+}
+
+// UpdateTags replaces the product's tags
+func (r *ProductRepository) UpdateTags(ctx context.Context, id string, tags []string) error {
+	if err := r.setFields(ctx, id, bson.M{"tags": tags}); err != nil {
+		return fmt.Errorf("failed to update tags: %w", err)
+	}
+	return nil
+}
+
+// UpdateCompetitorFilter sets the product's own competitor filter; nil clears it
+func (r *ProductRepository) UpdateCompetitorFilter(ctx context.Context, id string, filter *domain.CompetitorFilter) error {
+	if err := r.setFields(ctx, id, bson.M{"competitor_filter": filter}); err != nil {
+		return fmt.Errorf("failed to update competitor filter: %w", err)
+	}
+	return nil
+}
+
+// UpdateCosts sets the product's costs; nil clears them
+func (r *ProductRepository) UpdateCosts(ctx context.Context, id string, costs *domain.ProductCosts) error {
+	if err := r.setFields(ctx, id, bson.M{"costs": costs}); err != nil {
+		return fmt.Errorf("failed to update costs: %w", err)
+	}
+	return nil
+}
+
+// SetDumpingDryRun turns the product's dry-run mode on or off
+func (r *ProductRepository) SetDumpingDryRun(ctx context.Context, id string, enabled bool) error {
+	if err := r.setFields(ctx, id, bson.M{"dumping_dry_run": enabled}); err != nil {
+		return fmt.Errorf("failed to update dry-run mode: %w", err)
+	}
+	return nil
+}
+
+// UpdateStock sets the product's per-warehouse stock and its total
+func (r *ProductRepository) UpdateStock(ctx context.Context, id string, stocks []domain.WarehouseStock, currentStock int) error {
+	if err := r.setFields(ctx, id, bson.M{"stocks": stocks, "current_stock": currentStock}); err != nil {
+		return fmt.Errorf("failed to update stock: %w", err)
+	}
+	return nil
+}
+
+// UpdateStockMetrics sets the product's sales velocity and days of stock, in total and at each
+// of the given warehouses. Stock quantities are left alone, so a concurrent sync keeps its own.
+func (r *ProductRepository) UpdateStockMetrics(ctx context.Context, id string, salesVelocity float64, daysOfStock int, stocks []domain.WarehouseStock) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid product ID: %w", err)
+	}
+
+	fields := bson.M{
+		"sales_velocity": salesVelocity,
+		"days_of_stock":  daysOfStock,
+		"last_sync_at":   time.Now(),
+	}
+	filters := make([]interface{}, 0, len(stocks))
+	for i, stock := range stocks {
+		name := fmt.Sprintf("w%d", i)
+		fields["stocks.$["+name+"].sales_velocity"] = stock.SalesVelocity
+		fields["stocks.$["+name+"].days_of_stock"] = stock.DaysOfStock
+		filters = append(filters, bson.M{name + ".warehouse_id": stock.WarehouseID})
+	}
+
+	opts := options.Update()
+	if len(filters) > 0 {
+		opts.SetArrayFilters(options.ArrayFilters{Filters: filters})
+	}
+
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": fields}, opts); err != nil {
+		return fmt.Errorf("failed to update stock metrics: %w", err)
+	}
+	return nil
+}
+
+// setFields sets only the given fields of a product, so concurrent changes to its other
+// fields are not overwritten the way a full Update would
+func (r *ProductRepository) setFields(ctx context.Context, id string, fields bson.M) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid product ID: %w", err)
+	}
+
+	fields["updated_at"] = time.Now()
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": fields})
+	return err
+}
+
+func (r *ProductRepository) UpsertProduct(ctx context.Context, product *domain.Product) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	now := time.Now()
@@ -127,17 +382,19 @@ func (r *ProductRepository) UpsertProduct(product *domain.Product) error {
 	}
 
 	filter := bson.M{
-		"user_id":     product.UserID,
-		"external_id": product.ExternalID,
+		"connection_id": product.ConnectionID,
+		"external_id":   product.ExternalID,
 	}
 
 	update := bson.M{
 		"$set": bson.M{
 			"user_id":        product.UserID,
+			"connection_id":  product.ConnectionID,
 			"external_id":    product.ExternalID,
 			"sku":            product.SKU,
 			"name":           product.Name,
 			"current_stock":  product.CurrentStock,
+			"stocks":         product.Stocks,
 			"price":          product.Price,
 			"currency":       product.Currency,
 			"sales_velocity": product.SalesVelocity,
@@ -163,8 +420,8 @@ func (r *ProductRepository) UpsertProduct(product *domain.Product) error {
 	return nil
 }
 
-func (r *ProductRepository) GetByID(id string) (*domain.Product, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *ProductRepository) GetByID(ctx context.Context, id string) (*domain.Product, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	oid, err := primitive.ObjectIDFromHex(id)
@@ -184,11 +441,11 @@ func (r *ProductRepository) GetByID(id string) (*domain.Product, error) {
 	return &product, nil
 }
 
-func (r *ProductRepository) GetByUserID(userID string) ([]domain.Product, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *ProductRepository) GetByUserID(ctx context.Context, userID string) ([]domain.Product, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	opts := options.Find().SetSort(bson.D{{"days_of_stock", 1}})
+	opts := options.Find().SetSort(bson.D{{Key: "days_of_stock", Value: 1}})
 	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get products: %w", err)
@@ -203,19 +460,52 @@ func (r *ProductRepository) GetByUserID(userID string) ([]domain.Product, error)
 	return products, nil
 }
 
-func (r *ProductRepository) GetLowStockProducts(userID string, thresholdDays int) ([]domain.Product, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *ProductRepository) GetByConnectionID(ctx context.Context, connectionID string) ([]domain.Product, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
+	cursor, err := r.collection.Find(ctx, bson.M{"connection_id": connectionID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var products []domain.Product
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, fmt.Errorf("failed to decode products: %w", err)
+	}
+
+	return products, nil
+}
+
+func (r *ProductRepository) DeleteByConnectionID(ctx context.Context, connectionID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"connection_id": connectionID}); err != nil {
+		return fmt.Errorf("failed to delete products: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ProductRepository) GetLowStockProducts(ctx context.Context, userID string, thresholdDays int) ([]domain.Product, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// Low in total, or sold out / running low at a location that is selling
 	filter := bson.M{
 		"user_id": userID,
-		"days_of_stock": bson.M{
-			"$lte": thresholdDays,
-			"$gt":  0,
+		"$or": bson.A{
+			bson.M{"days_of_stock": bson.M{"$lte": thresholdDays, "$gt": 0}},
+			bson.M{"stocks": bson.M{"$elemMatch": bson.M{
+				"days_of_stock":  bson.M{"$lte": thresholdDays},
+				"sales_velocity": bson.M{"$gt": 0},
+			}}},
 		},
 	}
 
-	opts := options.Find().SetSort(bson.D{{"days_of_stock", 1}})
+	opts := options.Find().SetSort(bson.D{{Key: "days_of_stock", Value: 1}})
 	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get low stock products: %w", err)
@@ -241,8 +531,8 @@ func NewSalesHistoryRepository(db *Database) *SalesHistoryRepository {
 	}
 }
 
-func (r *SalesHistoryRepository) Create(history *domain.SalesHistory) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *SalesHistoryRepository) Create(ctx context.Context, history *domain.SalesHistory) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	history.CreatedAt = time.Now()
@@ -256,8 +546,8 @@ func (r *SalesHistoryRepository) Create(history *domain.SalesHistory) error {
 	return nil
 }
 
-func (r *SalesHistoryRepository) UpsertSalesHistory(history *domain.SalesHistory) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *SalesHistoryRepository) UpsertSalesHistory(ctx context.Context, history *domain.SalesHistory) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	now := time.Now()
@@ -273,6 +563,7 @@ func (r *SalesHistoryRepository) UpsertSalesHistory(history *domain.SalesHistory
 	update := bson.M{
 		"$set": bson.M{
 			"product_id":    history.ProductID,
+			"connection_id": history.ConnectionID,
 			"date":          history.Date,
 			"quantity_sold": history.QuantitySold,
 			"revenue":       history.Revenue,
@@ -295,8 +586,19 @@ func (r *SalesHistoryRepository) UpsertSalesHistory(history *domain.SalesHistory
 	return nil
 }
 
-func (r *SalesHistoryRepository) GetByProductID(productID string, days int) ([]domain.SalesHistory, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *SalesHistoryRepository) DeleteByConnectionID(ctx context.Context, connectionID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"connection_id": connectionID}); err != nil {
+		return fmt.Errorf("failed to delete sales history: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SalesHistoryRepository) GetByProductID(ctx context.Context, productID string, days int) ([]domain.SalesHistory, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	filter := bson.M{
@@ -306,7 +608,7 @@ func (r *SalesHistoryRepository) GetByProductID(productID string, days int) ([]d
 		},
 	}
 
-	opts := options.Find().SetSort(bson.D{{"date", -1}})
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: -1}})
 	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sales history: %w", err)
@@ -332,8 +634,8 @@ func NewLowStockAlertRepository(db *Database) *LowStockAlertRepository {
 	}
 }
 
-func (r *LowStockAlertRepository) Create(alert *domain.LowStockAlert) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *LowStockAlertRepository) Create(ctx context.Context, alert *domain.LowStockAlert) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	alert.NotifiedAt = time.Now()
@@ -348,8 +650,8 @@ func (r *LowStockAlertRepository) Create(alert *domain.LowStockAlert) error {
 	return nil
 }
 
-func (r *LowStockAlertRepository) GetRecentAlerts(userID string, hours int) ([]domain.LowStockAlert, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *LowStockAlertRepository) GetRecentAlerts(ctx context.Context, userID string, hours int) ([]domain.LowStockAlert, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	filter := bson.M{
@@ -359,7 +661,7 @@ func (r *LowStockAlertRepository) GetRecentAlerts(userID string, hours int) ([]d
 		},
 	}
 
-	opts := options.Find().SetSort(bson.D{{"notified_at", -1}})
+	opts := options.Find().SetSort(bson.D{{Key: "notified_at", Value: -1}})
 	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get recent alerts: %w", err)
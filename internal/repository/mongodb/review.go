@@ -22,8 +22,8 @@ func NewReviewRepository(db *Database) *ReviewRepository {
 	}
 }
 
-func (r *ReviewRepository) Create(review *domain.Review) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *ReviewRepository) Create(ctx context.Context, review *domain.Review) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	review.CreatedAt = time.Now()
@@ -38,8 +38,8 @@ func (r *ReviewRepository) Create(review *domain.Review) error {
 	return nil
 }
 
-func (r *ReviewRepository) Update(review *domain.Review) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *ReviewRepository) Update(ctx context.Context, review *domain.Review) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	review.UpdatedAt = time.Now()
@@ -61,8 +61,8 @@ func (r *ReviewRepository) Update(review *domain.Review) error {
 	return err
 }
 
-func (r *ReviewRepository) UpsertReview(review *domain.Review) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *ReviewRepository) UpsertReview(ctx context.Context, review *domain.Review) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	now := time.Now()
@@ -72,20 +72,21 @@ func (r *ReviewRepository) UpsertReview(review *domain.Review) error {
 	}
 
 	filter := bson.M{
-		"user_id":     review.UserID,
-		"external_id": review.ExternalID,
+		"connection_id": review.ConnectionID,
+		"external_id":   review.ExternalID,
 	}
 
 	update := bson.M{
 		"$set": bson.M{
-			"user_id":     review.UserID,
-			"product_id":  review.ProductID,
-			"external_id": review.ExternalID,
-			"author_name": review.AuthorName,
-			"rating":      review.Rating,
-			"comment":     review.Comment,
-			"language":    review.Language,
-			"updated_at":  review.UpdatedAt,
+			"user_id":       review.UserID,
+			"connection_id": review.ConnectionID,
+			"product_id":    review.ProductID,
+			"external_id":   review.ExternalID,
+			"author_name":   review.AuthorName,
+			"rating":        review.Rating,
+			"comment":       review.Comment,
+			"language":      review.Language,
+			"updated_at":    review.UpdatedAt,
 		},
 		"$setOnInsert": bson.M{
 			"ai_response":      review.AIResponse,
@@ -107,8 +108,8 @@ func (r *ReviewRepository) UpsertReview(review *domain.Review) error {
 	return nil
 }
 
-func (r *ReviewRepository) GetByID(id string) (*domain.Review, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *ReviewRepository) GetByID(ctx context.Context, id string) (*domain.Review, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	oid, err := primitive.ObjectIDFromHex(id)
@@ -128,8 +129,8 @@ func (r *ReviewRepository) GetByID(id string) (*domain.Review, error) {
 	return &review, nil
 }
 
-func (r *ReviewRepository) GetPendingReviews(userID string) ([]domain.Review, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *ReviewRepository) GetPendingReviews(ctx context.Context, userID string) ([]domain.Review, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	filter := bson.M{
@@ -137,7 +138,7 @@ func (r *ReviewRepository) GetPendingReviews(userID string) ([]domain.Review, er
 		"ai_response_sent": false,
 	}
 
-	opts := options.Find().SetSort(bson.D{{"created_at", -1}}).SetLimit(50)
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(50)
 	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pending reviews: %w", err)
@@ -152,11 +153,11 @@ func (r *ReviewRepository) GetPendingReviews(userID string) ([]domain.Review, er
 	return reviews, nil
 }
 
-func (r *ReviewRepository) GetByUserID(userID string, limit int) ([]domain.Review, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *ReviewRepository) GetByUserID(ctx context.Context, userID string, limit int) ([]domain.Review, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	opts := options.Find().SetSort(bson.D{{"created_at", -1}}).SetLimit(int64(limit))
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(int64(limit))
 	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get reviews: %w", err)
@@ -170,3 +171,14 @@ func (r *ReviewRepository) GetByUserID(userID string, limit int) ([]domain.Revie
 
 	return reviews, nil
 }
+
+func (r *ReviewRepository) DeleteByConnectionID(ctx context.Context, connectionID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"connection_id": connectionID}); err != nil {
+		return fmt.Errorf("failed to delete reviews: %w", err)
+	}
+
+	return nil
+}
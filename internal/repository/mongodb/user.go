@@ -22,8 +22,8 @@ func NewUserRepository(db *Database) *UserRepository {
 	}
 }
 
-func (r *UserRepository) Create(user *domain.User) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	user.CreatedAt = time.Now()
@@ -38,8 +38,8 @@ func (r *UserRepository) Create(user *domain.User) error {
 	return nil
 }
 
-func (r *UserRepository) GetByEmail(email string) (*domain.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	var user domain.User
@@ -54,8 +54,8 @@ func (r *UserRepository) GetByEmail(email string) (*domain.User, error) {
 	return &user, nil
 }
 
-func (r *UserRepository) GetByID(id string) (*domain.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	oid, err := primitive.ObjectIDFromHex(id)
@@ -75,8 +75,8 @@ func (r *UserRepository) GetByID(id string) (*domain.User, error) {
 	return &user, nil
 }
 
-func (r *UserRepository) Update(user *domain.User) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	user.UpdatedAt = time.Now()
@@ -101,8 +101,8 @@ func (r *UserRepository) Update(user *domain.User) error {
 	return err
 }
 
-func (r *UserRepository) ToggleAutoReply(userID string, enabled bool) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *UserRepository) ToggleAutoReply(ctx context.Context, userID string, enabled bool) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	oid, err := primitive.ObjectIDFromHex(userID)
@@ -121,8 +121,8 @@ func (r *UserRepository) ToggleAutoReply(userID string, enabled bool) error {
 	return err
 }
 
-func (r *UserRepository) ToggleAutoDumping(userID string, enabled bool) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (r *UserRepository) ToggleAutoDumping(ctx context.Context, userID string, enabled bool) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	oid, err := primitive.ObjectIDFromHex(userID)
@@ -141,6 +141,26 @@ func (r *UserRepository) ToggleAutoDumping(userID string, enabled bool) error {
 	return err
 }
 
+func (r *UserRepository) ToggleDumpingDryRun(ctx context.Context, userID string, enabled bool) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"dumping_dry_run": enabled,
+			"updated_at":      time.Now(),
+		},
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": oid}, update)
+	return err
+}
+
 // EnsureIndexes creates necessary indexes for the users collection
 func (r *UserRepository) EnsureIndexes() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
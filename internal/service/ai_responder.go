@@ -23,11 +23,11 @@ func NewAIResponderService(apiKey string, reviewRepo domain.ReviewRepository) *A
 }
 
 // GenerateResponse generates an AI response for a review
-func (s *AIResponderService) GenerateResponse(review *domain.Review) (string, error) {
+func (s *AIResponderService) GenerateResponse(ctx context.Context, review *domain.Review) (string, error) {
 	prompt := s.buildPrompt(review)
 
 	resp, err := s.openaiClient.CreateChatCompletion(
-		context.Background(),
+		ctx,
 		openai.ChatCompletionRequest{
 			Model: openai.GPT4,
 			Messages: []openai.ChatCompletionMessage{
@@ -57,8 +57,8 @@ func (s *AIResponderService) GenerateResponse(review *domain.Review) (string, er
 }
 
 // ProcessPendingReviews processes all pending reviews for a user
-func (s *AIResponderService) ProcessPendingReviews(userID string, autoSend bool) error {
-	reviews, err := s.reviewRepo.GetPendingReviews(userID)
+func (s *AIResponderService) ProcessPendingReviews(ctx context.Context, userID string, autoSend bool) error {
+	reviews, err := s.reviewRepo.GetPendingReviews(ctx, userID)
 	if err != nil {
 		return fmt.Errorf("failed to get pending reviews: %w", err)
 	}
@@ -69,7 +69,11 @@ func (s *AIResponderService) ProcessPendingReviews(userID string, autoSend bool)
 	)
 
 	for _, review := range reviews {
-		response, err := s.GenerateResponse(&review)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		response, err := s.GenerateResponse(ctx, &review)
 		if err != nil {
 			logger.Log.Error("Failed to generate AI response",
 				zap.String("review_id", review.ID),
@@ -83,7 +87,7 @@ func (s *AIResponderService) ProcessPendingReviews(userID string, autoSend bool)
 			review.AIResponseSent = true
 		}
 
-		if err := s.reviewRepo.Update(&review); err != nil {
+		if err := s.reviewRepo.Update(ctx, &review); err != nil {
 			logger.Log.Error("Failed to update review",
 				zap.String("review_id", review.ID),
 				zap.Error(err),
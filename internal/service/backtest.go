@@ -0,0 +1,449 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+// ErrInvalidBacktest is returned for an out-of-range period or a city the product is not priced in
+var ErrInvalidBacktest = errors.New("invalid backtest")
+
+// ErrNoOfferHistory is returned when no competitor offers were recorded for the product in the period
+var ErrNoOfferHistory = errors.New("no competitor history to replay")
+
+// DefaultBacktestDays is the period replayed when a backtest does not give one
+const DefaultBacktestDays = 30
+
+// BacktestService replays recorded competitor offers through the dumping decision logic to
+// show how a strategy would have priced a product and what it would have earned
+type BacktestService struct {
+	productRepo      domain.ProductRepository
+	tagStrategyRepo  domain.TagPricingStrategyRepository
+	filterRepo       domain.CompetitorFilterRepository
+	snapshotRepo     domain.OfferSnapshotRepository
+	salesHistoryRepo domain.SalesHistoryRepository
+	guards           *PriceGuardService
+}
+
+func NewBacktestService(
+	productRepo domain.ProductRepository,
+	tagStrategyRepo domain.TagPricingStrategyRepository,
+	filterRepo domain.CompetitorFilterRepository,
+	snapshotRepo domain.OfferSnapshotRepository,
+	salesHistoryRepo domain.SalesHistoryRepository,
+	guards *PriceGuardService,
+) *BacktestService {
+	return &BacktestService{
+		productRepo:      productRepo,
+		tagStrategyRepo:  tagStrategyRepo,
+		filterRepo:       filterRepo,
+		snapshotRepo:     snapshotRepo,
+		salesHistoryRepo: salesHistoryRepo,
+		guards:           guards,
+	}
+}
+
+// BacktestRequest selects the period, city and the two strategies to compare
+type BacktestRequest struct {
+	Days        int                           `json:"days"` // DefaultBacktestDays if 0
+	City        string                        `json:"city"` // Required for products priced by city
+	Strategy    domain.PricingStrategyConfig  `json:"strategy"`
+	CompareWith *domain.PricingStrategyConfig `json:"compare_with"` // The product's current strategy if nil
+}
+
+// BacktestReport puts the prices a product actually had next to the prices two strategies
+// would have set over the same competitor offers
+type BacktestReport struct {
+	ProductID    string             `json:"product_id"`
+	ProductName  string             `json:"product_name"`
+	City         string             `json:"city,omitempty"`
+	From         time.Time          `json:"from"`
+	To           time.Time          `json:"to"`
+	Observations int                `json:"observations"`
+	Floor        float64            `json:"floor"`   // Current floor, applied to the whole period
+	Ceiling      float64            `json:"ceiling"` // Current ceiling, 0 if none
+	Guard        domain.PriceGuard  `json:"guard"`   // Current price war guard, applied to the whole period
+	UnitsSold    int                `json:"units_sold"`
+	Actual       BacktestResult     `json:"actual"`
+	Strategy     BacktestResult     `json:"strategy"`
+	CompareWith  BacktestResult     `json:"compare_with"`
+	Comparison   BacktestComparison `json:"comparison"`
+}
+
+// BacktestResult is how one strategy, or the actual prices, played out over the period
+type BacktestResult struct {
+	Strategy         *domain.PricingStrategyConfig `json:"strategy,omitempty"` // nil for the actual prices
+	PricePath        []BacktestPoint               `json:"price_path"`         // The starting price and every change
+	Changes          int                           `json:"changes"`
+	FloorHits        int                           `json:"floor_hits"`   // Observations where the strategy aimed below the floor
+	GuardPauses      int                           `json:"guard_pauses"` // Times the price war guard paused dumping
+	FinalPrice       float64                       `json:"final_price"`
+	MinPrice         float64                       `json:"min_price"`
+	MaxPrice         float64                       `json:"max_price"`
+	AveragePrice     float64                       `json:"average_price"`
+	EstimatedRevenue float64                       `json:"estimated_revenue"`
+	EstimatedProfit  *float64                      `json:"estimated_profit,omitempty"` // Only when the product's costs are set
+	MarginPercent    *float64                      `json:"margin_percent,omitempty"`
+}
+
+// BacktestPoint is a price taking effect
+type BacktestPoint struct {
+	At     time.Time `json:"at"`
+	Price  float64   `json:"price"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// BacktestComparison is the strategy's result minus the compared strategy's
+type BacktestComparison struct {
+	RevenueDiff   float64  `json:"revenue_diff"`
+	ProfitDiff    *float64 `json:"profit_diff,omitempty"`
+	ChangesDiff   int      `json:"changes_diff"`
+	FloorHitsDiff int      `json:"floor_hits_diff"`
+	Better        string   `json:"better"` // strategy, compare_with or tie; by profit when costs are set, else by revenue
+}
+
+// RunForUser backtests one of the user's products
+func (s *BacktestService) RunForUser(ctx context.Context, userID, productID string, req BacktestRequest) (*BacktestReport, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil || product == nil || product.UserID != userID {
+		return nil, ErrProductNotFound
+	}
+	return s.Run(ctx, product, req)
+}
+
+// Run replays the competitor offers recorded for a product over the last days, oldest first.
+// Each strategy starts from the price the product had at the first observation and is
+// repriced at every observation the way price dumping would, with the product's current
+// floor, ceiling, competitor filter and price war guard. Only seller filters apply:
+// delivery and rating are not recorded with the offers.
+//
+// Revenue is estimated as the units actually sold each day times the day's average price,
+// so it assumes the same demand whatever the price. Sales are not split by city, so a city
+// backtest uses all the product's sales.
+func (s *BacktestService) Run(ctx context.Context, product *domain.Product, req BacktestRequest) (*BacktestReport, error) {
+	days := req.Days
+	if days == 0 {
+		days = DefaultBacktestDays
+	}
+	if days < 0 || days > MaxCompetitorHistoryDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", ErrInvalidBacktest, MaxCompetitorHistoryDays)
+	}
+
+	// Products priced by city are observed per city only
+	var city *domain.CityPrice
+	switch {
+	case req.City != "":
+		if city = product.CityPrice(req.City); city == nil {
+			return nil, fmt.Errorf("%w: the product has no price in %s", ErrInvalidBacktest, req.City)
+		}
+	case len(product.CityPrices) > 0:
+		return nil, fmt.Errorf("%w: city is required for products priced by city", ErrInvalidBacktest)
+	}
+
+	strategy, err := NewPricingStrategy(req.Strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	compareCfg := req.CompareWith
+	if compareCfg == nil {
+		current, err := s.currentStrategy(ctx, product)
+		if err != nil {
+			return nil, err
+		}
+		compareCfg = &current
+	}
+	compareWith, err := NewPricingStrategy(*compareCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.filterRepo.GetByUserID(ctx, product.UserID)
+	if err != nil {
+		return nil, err
+	}
+	var userFilter *domain.CompetitorFilter
+	if existing != nil {
+		userFilter = &existing.Filter
+	}
+	filter := CombineCompetitorFilters(userFilter, product.CompetitorFilter)
+
+	cityName := ""
+	if city != nil {
+		cityName = city.City
+	}
+	snapshots, err := s.snapshotRepo.GetByProductID(ctx, product.ID, cityName, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, ErrNoOfferHistory
+	}
+
+	sales, err := s.salesHistoryRepo.GetByProductID(ctx, product.ID, days)
+	if err != nil {
+		return nil, err
+	}
+	unitsByDay := make(map[string]int, len(sales))
+	for _, sale := range sales {
+		unitsByDay[dayKey(sale.Date)] += sale.QuantitySold
+	}
+
+	offers := make([][]marketplace.CompetitorOffer, len(snapshots))
+	for i, snap := range snapshots {
+		offers[i], _ = FilterOffers(observedToOffers(snap.Offers), filter)
+	}
+
+	floor := product.FloorFor(city)
+	ceiling := product.CeilingFor(city)
+
+	guard, err := s.guards.guardFor(ctx, product.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	actualPrices := make([]float64, len(snapshots))
+	for i, snap := range snapshots {
+		actualPrices[i] = snap.OurPrice
+	}
+	actual := BacktestResult{PricePath: []BacktestPoint{{At: snapshots[0].ObservedAt, Price: actualPrices[0]}}}
+	for i := 1; i < len(snapshots); i++ {
+		if actualPrices[i] != actualPrices[i-1] {
+			actual.Changes++
+			actual.PricePath = append(actual.PricePath, BacktestPoint{At: snapshots[i].ObservedAt, Price: actualPrices[i]})
+		}
+	}
+
+	report := &BacktestReport{
+		ProductID:    product.ID,
+		ProductName:  product.Name,
+		City:         cityName,
+		From:         snapshots[0].ObservedAt,
+		To:           snapshots[len(snapshots)-1].ObservedAt,
+		Observations: len(snapshots),
+		Floor:        floor,
+		Ceiling:      ceiling,
+		Guard:        guard,
+	}
+	for day := range observedDays(snapshots) {
+		report.UnitsSold += unitsByDay[day]
+	}
+
+	report.Actual = actual
+	summarizeBacktest(&report.Actual, snapshots, actualPrices, unitsByDay, product.Costs)
+
+	report.Strategy = replay(strategy, snapshots, offers, floor, ceiling, guard)
+	report.Strategy.Strategy = &req.Strategy
+	summarizeBacktest(&report.Strategy, snapshots, pricesAlongPath(snapshots, report.Strategy.PricePath), unitsByDay, product.Costs)
+
+	report.CompareWith = replay(compareWith, snapshots, offers, floor, ceiling, guard)
+	report.CompareWith.Strategy = compareCfg
+	summarizeBacktest(&report.CompareWith, snapshots, pricesAlongPath(snapshots, report.CompareWith.PricePath), unitsByDay, product.Costs)
+
+	report.Comparison = compareBacktests(report.Strategy, report.CompareWith)
+
+	return report, nil
+}
+
+// currentStrategy returns the strategy price dumping uses for the product now
+func (s *BacktestService) currentStrategy(ctx context.Context, product *domain.Product) (domain.PricingStrategyConfig, error) {
+	tagStrategies, err := s.tagStrategyRepo.GetByUserID(ctx, product.UserID)
+	if err != nil {
+		return domain.PricingStrategyConfig{}, err
+	}
+	byTag := make(map[string]domain.PricingStrategyConfig, len(tagStrategies))
+	for _, ts := range tagStrategies {
+		byTag[ts.Tag] = ts.Strategy
+	}
+
+	cfg, _, _ := StrategyConfigFor(product, byTag)
+	return cfg, nil
+}
+
+// replay reprices from the first observed price at every observation, like processProduct
+// and processCity do, and returns the price path with the changes, floor hits and guard
+// pauses counted. The price war guard sees the price changes of the replay itself, and
+// observations are skipped while it has dumping paused.
+func replay(strategy PricingStrategy, snapshots []domain.OfferSnapshot, offers [][]marketplace.CompetitorOffer, floor, ceiling float64, guard domain.PriceGuard) BacktestResult {
+	price := snapshots[0].OurPrice
+	result := BacktestResult{PricePath: []BacktestPoint{{At: snapshots[0].ObservedAt, Price: price}}}
+
+	var (
+		changes     []domain.PriceChange // Newest first, like the price history
+		pausedUntil time.Time
+	)
+
+	for i, snap := range snapshots {
+		now := snap.ObservedAt
+		if now.Before(pausedUntil) {
+			continue
+		}
+		if len(offers[i]) == 0 && ceiling == 0 {
+			continue
+		}
+
+		if target, ok := strategy.TargetPrice(offers[i]); ok && floor > 0 && target < floor {
+			result.FloorHits++
+		}
+
+		newPrice, reason, ok := decidePrice(strategy, offers[i], floor, ceiling)
+		if !ok {
+			continue
+		}
+
+		// Only the changes still in the guard's window count
+		for j, c := range changes {
+			if c.CreatedAt.Before(now.Add(-priceGuardWindow)) {
+				changes = changes[:j]
+				break
+			}
+		}
+
+		newPrice, reason, trip := guardPrice(price, newPrice, reason, func(target float64) guardCheck {
+			return checkPriceGuard(guard, price, target, offers[i], changes, pausedUntil, now)
+		})
+		if trip != nil {
+			pausedUntil = trip.until
+			result.GuardPauses++
+		}
+		if newPrice == price {
+			continue
+		}
+
+		changes = append([]domain.PriceChange{{
+			OldPrice:    price,
+			NewPrice:    newPrice,
+			Competitors: snapshotOffers(offers[i]),
+			Reason:      reason,
+			Trigger:     domain.PriceTriggerAuto,
+			CreatedAt:   now,
+		}}, changes...)
+
+		price = newPrice
+		result.Changes++
+		result.PricePath = append(result.PricePath, BacktestPoint{At: snap.ObservedAt, Price: price, Reason: reason})
+	}
+
+	return result
+}
+
+// pricesAlongPath returns the price in effect at each observation
+func pricesAlongPath(snapshots []domain.OfferSnapshot, path []BacktestPoint) []float64 {
+	prices := make([]float64, len(snapshots))
+	next := 0
+	for i, snap := range snapshots {
+		for next < len(path) && !path[next].At.After(snap.ObservedAt) {
+			next++
+		}
+		prices[i] = path[next-1].Price
+	}
+	return prices
+}
+
+// summarizeBacktest fills in the price statistics and the revenue and profit estimates.
+// prices holds the price in effect at each observation.
+func summarizeBacktest(result *BacktestResult, snapshots []domain.OfferSnapshot, prices []float64, unitsByDay map[string]int, costs *domain.ProductCosts) {
+	result.FinalPrice = prices[len(prices)-1]
+	result.MinPrice = prices[0]
+	result.MaxPrice = prices[0]
+
+	var sum float64
+	daySums := make(map[string]float64)
+	dayCounts := make(map[string]int)
+	for i, price := range prices {
+		sum += price
+		result.MinPrice = min(result.MinPrice, price)
+		result.MaxPrice = max(result.MaxPrice, price)
+
+		day := dayKey(snapshots[i].ObservedAt)
+		daySums[day] += price
+		dayCounts[day]++
+	}
+	result.AveragePrice = roundMoney(sum / float64(len(prices)))
+
+	var revenue, profit float64
+	for day, count := range dayCounts {
+		units := unitsByDay[day]
+		if units == 0 {
+			continue
+		}
+		dayPrice := daySums[day] / float64(count)
+		revenue += dayPrice * float64(units)
+		if costs != nil {
+			profit += costs.MarginAt(dayPrice).Profit * float64(units)
+		}
+	}
+	result.EstimatedRevenue = roundMoney(revenue)
+
+	if costs != nil {
+		profit = roundMoney(profit)
+		result.EstimatedProfit = &profit
+		if revenue > 0 {
+			margin := math.Round(profit/revenue*10000) / 100
+			result.MarginPercent = &margin
+		}
+	}
+}
+
+func compareBacktests(strategy, compareWith BacktestResult) BacktestComparison {
+	cmp := BacktestComparison{
+		RevenueDiff:   roundMoney(strategy.EstimatedRevenue - compareWith.EstimatedRevenue),
+		ChangesDiff:   strategy.Changes - compareWith.Changes,
+		FloorHitsDiff: strategy.FloorHits - compareWith.FloorHits,
+	}
+
+	diff := cmp.RevenueDiff
+	if strategy.EstimatedProfit != nil && compareWith.EstimatedProfit != nil {
+		profitDiff := roundMoney(*strategy.EstimatedProfit - *compareWith.EstimatedProfit)
+		cmp.ProfitDiff = &profitDiff
+		diff = profitDiff
+	}
+
+	switch {
+	case diff > 0:
+		cmp.Better = "strategy"
+	case diff < 0:
+		cmp.Better = "compare_with"
+	default:
+		cmp.Better = "tie"
+	}
+
+	return cmp
+}
+
+// observedToOffers turns recorded offers back into marketplace offers for the strategies
+func observedToOffers(observed []domain.ObservedOffer) []marketplace.CompetitorOffer {
+	offers := make([]marketplace.CompetitorOffer, len(observed))
+	for i, o := range observed {
+		offers[i] = marketplace.CompetitorOffer{
+			SellerID:   o.SellerID,
+			SellerName: o.SellerName,
+			Price:      o.Price,
+		}
+	}
+	return offers
+}
+
+// observedDays returns the days, as dayKey, that have at least one observation
+func observedDays(snapshots []domain.OfferSnapshot) map[string]bool {
+	days := make(map[string]bool)
+	for _, snap := range snapshots {
+		days[dayKey(snap.ObservedAt)] = true
+	}
+	return days
+}
+
+// dayKey is the UTC day sales history is grouped by
+func dayKey(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+// backtestHistory is five observations over two days, starting from a price of 1000
+func backtestHistory() ([]domain.OfferSnapshot, [][]marketplace.CompetitorOffer) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	at := []time.Time{
+		day.Add(10 * time.Hour),
+		day.Add(11 * time.Hour),
+		day.Add(12 * time.Hour),
+		day.Add(34 * time.Hour),
+		day.Add(35 * time.Hour),
+	}
+
+	snapshots := make([]domain.OfferSnapshot, len(at))
+	for i := range at {
+		snapshots[i] = domain.OfferSnapshot{ObservedAt: at[i], OurPrice: 1000}
+	}
+
+	offers := [][]marketplace.CompetitorOffer{
+		offersAt(1005),
+		offersAt(1005),
+		offersAt(850, 1100), // Cheapest under the floor
+		nil,                 // Nobody selling
+		offersAt(800),       // Only under the floor
+	}
+	return snapshots, offers
+}
+
+func TestReplay(t *testing.T) {
+	snapshots, offers := backtestHistory()
+	undercut := undercutAmount{amount: 1}
+
+	tests := []struct {
+		name      string
+		ceiling   float64
+		want      []BacktestPoint
+		floorHits int
+	}{
+		{
+			"with a ceiling",
+			1200,
+			[]BacktestPoint{
+				{At: snapshots[0].ObservedAt, Price: 1000},
+				{At: snapshots[0].ObservedAt, Price: 1004, Reason: PriceReasonStrategy},
+				{At: snapshots[2].ObservedAt, Price: 1099, Reason: PriceReasonAboveFloor},
+				{At: snapshots[3].ObservedAt, Price: 1200, Reason: PriceReasonNoCompetitors},
+			},
+			2,
+		},
+		{
+			"without a ceiling the price holds",
+			0,
+			[]BacktestPoint{
+				{At: snapshots[0].ObservedAt, Price: 1000},
+				{At: snapshots[0].ObservedAt, Price: 1004, Reason: PriceReasonStrategy},
+				{At: snapshots[2].ObservedAt, Price: 1099, Reason: PriceReasonAboveFloor},
+			},
+			2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := replay(undercut, snapshots, offers, 900, tt.ceiling, DefaultPriceGuard)
+
+			if !reflect.DeepEqual(result.PricePath, tt.want) {
+				t.Errorf("price path = %+v, want %+v", result.PricePath, tt.want)
+			}
+			if result.Changes != len(tt.want)-1 || result.FloorHits != tt.floorHits {
+				t.Errorf("changes %d, floor hits %d, want %d, %d", result.Changes, result.FloorHits, len(tt.want)-1, tt.floorHits)
+			}
+		})
+	}
+}
+
+func TestReplayPriceGuard(t *testing.T) {
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	at := []time.Duration{0, 10 * time.Minute, 20 * time.Minute, 30 * time.Minute, 90 * time.Minute}
+
+	snapshots := make([]domain.OfferSnapshot, len(at))
+	for i, d := range at {
+		snapshots[i] = domain.OfferSnapshot{ObservedAt: start.Add(d), OurPrice: 1000}
+	}
+	offers := [][]marketplace.CompetitorOffer{
+		offersAt(990),
+		offersAt(900),
+		offersAt(900),
+		offersAt(900), // Paused
+		offersAt(900),
+	}
+
+	guard := domain.PriceGuard{MaxDecreasePercentPerCycle: 5, MaxConsecutiveUndercuts: 2, CooldownMinutes: 60}
+	result := replay(undercutAmount{amount: 1}, snapshots, offers, 0, 0, guard)
+
+	want := []BacktestPoint{
+		{At: snapshots[0].ObservedAt, Price: 1000},
+		{At: snapshots[0].ObservedAt, Price: 989, Reason: PriceReasonStrategy},
+		{At: snapshots[1].ObservedAt, Price: 940, Reason: PriceReasonRateLimited}, // 5% under 989
+		// The third undercut in a row pauses dumping for an hour
+		{At: snapshots[4].ObservedAt, Price: 899, Reason: PriceReasonStrategy},
+	}
+	if !reflect.DeepEqual(result.PricePath, want) {
+		t.Errorf("price path = %+v, want %+v", result.PricePath, want)
+	}
+	if result.Changes != 3 || result.GuardPauses != 1 {
+		t.Errorf("changes %d, guard pauses %d, want 3, 1", result.Changes, result.GuardPauses)
+	}
+}
+
+func TestPricesAlongPath(t *testing.T) {
+	snapshots, offers := backtestHistory()
+	result := replay(undercutAmount{amount: 1}, snapshots, offers, 900, 1200, DefaultPriceGuard)
+
+	got := pricesAlongPath(snapshots, result.PricePath)
+	want := []float64{1004, 1004, 1099, 1200, 1200}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pricesAlongPath = %v, want %v", got, want)
+	}
+}
+
+func TestSummarizeBacktest(t *testing.T) {
+	snapshots, _ := backtestHistory()
+	prices := []float64{1004, 1004, 1099, 1200, 1200}
+	// Nothing sold on the second day
+	unitsByDay := map[string]int{dayKey(snapshots[0].ObservedAt): 3}
+
+	var result BacktestResult
+	summarizeBacktest(&result, snapshots, prices, unitsByDay, nil)
+
+	if result.FinalPrice != 1200 || result.MinPrice != 1004 || result.MaxPrice != 1200 || result.AveragePrice != 1101.4 {
+		t.Errorf("prices = final %v, min %v, max %v, average %v", result.FinalPrice, result.MinPrice, result.MaxPrice, result.AveragePrice)
+	}
+	// Three units at the first day's average price of 1035.67
+	if result.EstimatedRevenue != 3107 {
+		t.Errorf("revenue = %v, want 3107", result.EstimatedRevenue)
+	}
+	if result.EstimatedProfit != nil || result.MarginPercent != nil {
+		t.Errorf("profit estimated without costs: %v, %v", result.EstimatedProfit, result.MarginPercent)
+	}
+
+	costs := &domain.ProductCosts{CostPrice: 700, CommissionPercent: 10}
+	summarizeBacktest(&result, snapshots, prices, unitsByDay, costs)
+
+	if result.EstimatedProfit == nil || *result.EstimatedProfit != 696.3 {
+		t.Errorf("profit = %v, want 696.3", result.EstimatedProfit)
+	}
+	if result.MarginPercent == nil || *result.MarginPercent != 22.41 {
+		t.Errorf("margin = %v, want 22.41", result.MarginPercent)
+	}
+}
+
+func TestCompareBacktests(t *testing.T) {
+	profit := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name        string
+		strategy    BacktestResult
+		compareWith BacktestResult
+		want        string
+	}{
+		{"more revenue", BacktestResult{EstimatedRevenue: 5000}, BacktestResult{EstimatedRevenue: 4000}, "strategy"},
+		{"less revenue", BacktestResult{EstimatedRevenue: 3000}, BacktestResult{EstimatedRevenue: 4000}, "compare_with"},
+		{"same revenue", BacktestResult{EstimatedRevenue: 4000}, BacktestResult{EstimatedRevenue: 4000}, "tie"},
+		{
+			"profit wins over revenue",
+			BacktestResult{EstimatedRevenue: 5000, EstimatedProfit: profit(500)},
+			BacktestResult{EstimatedRevenue: 4000, EstimatedProfit: profit(800)},
+			"compare_with",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compareBacktests(tt.strategy, tt.compareWith); got.Better != tt.want {
+				t.Errorf("better = %q, want %q", got.Better, tt.want)
+			}
+		})
+	}
+
+	cmp := compareBacktests(
+		BacktestResult{EstimatedRevenue: 5000.1, EstimatedProfit: profit(500), Changes: 9, FloorHits: 1},
+		BacktestResult{EstimatedRevenue: 4000, EstimatedProfit: profit(800), Changes: 4, FloorHits: 3},
+	)
+	if cmp.RevenueDiff != 1000.1 || cmp.ProfitDiff == nil || *cmp.ProfitDiff != -300 || cmp.ChangesDiff != 5 || cmp.FloorHitsDiff != -2 {
+		t.Errorf("comparison = %+v", cmp)
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+// ErrInvalidCompetitorFilter is returned for negative limits and overlong seller lists
+var ErrInvalidCompetitorFilter = errors.New("invalid competitor filter")
+
+// MaxFilterSellers caps how many sellers one ignore or follow-only list may hold
+const MaxFilterSellers = 100
+
+// MaxSellerRating is the top of the marketplace seller rating scale
+const MaxSellerRating = 5.0
+
+// CombineCompetitorFilters merges the user's filter with a product's: sellers ignored by
+// either are ignored, and the product's follow-only list and limits win over the user's
+// where they are set. Either filter may be nil.
+func CombineCompetitorFilters(user, product *domain.CompetitorFilter) domain.CompetitorFilter {
+	var combined domain.CompetitorFilter
+	if user != nil {
+		combined = *user
+		combined.IgnoreSellers = append([]string(nil), user.IgnoreSellers...)
+	}
+	if product == nil {
+		return combined
+	}
+
+	combined.IgnoreSellers = append(combined.IgnoreSellers, product.IgnoreSellers...)
+	if len(product.OnlySellers) > 0 {
+		combined.OnlySellers = product.OnlySellers
+	}
+	if product.MaxDeliveryDays > 0 {
+		combined.MaxDeliveryDays = product.MaxDeliveryDays
+	}
+	if product.MinSellerRating > 0 {
+		combined.MinSellerRating = product.MinSellerRating
+	}
+
+	return combined
+}
+
+// FilterOffers splits competitor offers into those repricing follows and those the filter
+// excludes, with the reason. Sellers are matched by ID or name, ignoring case. Offers whose
+// rating the marketplace does not report are not excluded by MinSellerRating.
+func FilterOffers(offers []marketplace.CompetitorOffer, filter domain.CompetitorFilter) ([]marketplace.CompetitorOffer, []domain.ExcludedOffer) {
+	if filter.IsZero() {
+		return offers, nil
+	}
+
+	kept := make([]marketplace.CompetitorOffer, 0, len(offers))
+	var excluded []domain.ExcludedOffer
+	for _, o := range offers {
+		reason := excludeReason(o, filter)
+		if reason == "" {
+			kept = append(kept, o)
+			continue
+		}
+		excluded = append(excluded, domain.ExcludedOffer{
+			SellerID:   o.SellerID,
+			SellerName: o.SellerName,
+			Price:      o.Price,
+			Reason:     reason,
+		})
+	}
+
+	return kept, excluded
+}
+
+func excludeReason(o marketplace.CompetitorOffer, filter domain.CompetitorFilter) string {
+	switch {
+	case matchesSeller(o, filter.IgnoreSellers):
+		return domain.ExcludeIgnoredSeller
+	case len(filter.OnlySellers) > 0 && !matchesSeller(o, filter.OnlySellers):
+		return domain.ExcludeNotFollowed
+	case filter.MaxDeliveryDays > 0 && o.DeliveryDays > filter.MaxDeliveryDays:
+		return domain.ExcludeSlowDelivery
+	case filter.MinSellerRating > 0 && o.SellerRating > 0 && o.SellerRating < filter.MinSellerRating:
+		return domain.ExcludeLowRating
+	}
+	return ""
+}
+
+func matchesSeller(o marketplace.CompetitorOffer, sellers []string) bool {
+	for _, seller := range sellers {
+		if strings.EqualFold(seller, o.SellerID) || strings.EqualFold(seller, o.SellerName) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeCompetitorFilter trims and deduplicates the seller lists and checks the limits
+func normalizeCompetitorFilter(filter domain.CompetitorFilter) (domain.CompetitorFilter, error) {
+	if filter.MaxDeliveryDays < 0 {
+		return filter, fmt.Errorf("%w: max_delivery_days must not be negative", ErrInvalidCompetitorFilter)
+	}
+	if filter.MinSellerRating < 0 || filter.MinSellerRating > MaxSellerRating {
+		return filter, fmt.Errorf("%w: min_seller_rating must be between 0 and %.0f", ErrInvalidCompetitorFilter, MaxSellerRating)
+	}
+
+	var err error
+	if filter.IgnoreSellers, err = normalizeSellers(filter.IgnoreSellers); err != nil {
+		return filter, err
+	}
+	if filter.OnlySellers, err = normalizeSellers(filter.OnlySellers); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+func normalizeSellers(sellers []string) ([]string, error) {
+	normalized := make([]string, 0, len(sellers))
+	seen := make(map[string]bool, len(sellers))
+	for _, seller := range sellers {
+		seller = strings.TrimSpace(seller)
+		key := strings.ToLower(seller)
+		if seller == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, seller)
+	}
+	if len(normalized) > MaxFilterSellers {
+		return nil, fmt.Errorf("%w: at most %d sellers per list", ErrInvalidCompetitorFilter, MaxFilterSellers)
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return normalized, nil
+}
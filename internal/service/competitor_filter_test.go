@@ -0,0 +1,174 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+func TestCombineCompetitorFilters(t *testing.T) {
+	user := &domain.CompetitorFilter{
+		IgnoreSellers:   []string{"our-outlet"},
+		OnlySellers:     []string{"big-shop"},
+		MaxDeliveryDays: 5,
+		MinSellerRating: 4,
+	}
+
+	tests := []struct {
+		name    string
+		user    *domain.CompetitorFilter
+		product *domain.CompetitorFilter
+		want    domain.CompetitorFilter
+	}{
+		{"neither", nil, nil, domain.CompetitorFilter{}},
+		{"user only", user, nil, *user},
+		{
+			"product only",
+			nil,
+			&domain.CompetitorFilter{IgnoreSellers: []string{"dumper"}, MaxDeliveryDays: 2},
+			domain.CompetitorFilter{IgnoreSellers: []string{"dumper"}, MaxDeliveryDays: 2},
+		},
+		{
+			"ignore lists add up and product limits win",
+			user,
+			&domain.CompetitorFilter{IgnoreSellers: []string{"dumper"}, OnlySellers: []string{"rival"}, MaxDeliveryDays: 2},
+			domain.CompetitorFilter{
+				IgnoreSellers:   []string{"our-outlet", "dumper"},
+				OnlySellers:     []string{"rival"},
+				MaxDeliveryDays: 2,
+				MinSellerRating: 4,
+			},
+		},
+		{
+			"unset product limits keep the user's",
+			user,
+			&domain.CompetitorFilter{MinSellerRating: 4.5},
+			domain.CompetitorFilter{
+				IgnoreSellers:   []string{"our-outlet"},
+				OnlySellers:     []string{"big-shop"},
+				MaxDeliveryDays: 5,
+				MinSellerRating: 4.5,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CombineCompetitorFilters(tt.user, tt.product)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CombineCompetitorFilters = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterOffers(t *testing.T) {
+	offers := []marketplace.CompetitorOffer{
+		{SellerID: "s1", SellerName: "Our Outlet", Price: 900, DeliveryDays: 1, SellerRating: 4.9},
+		{SellerID: "s2", SellerName: "Big Shop", Price: 1000, DeliveryDays: 1, SellerRating: 4.8},
+		{SellerID: "s3", SellerName: "Far Away", Price: 950, DeliveryDays: 10, SellerRating: 4.7},
+		{SellerID: "s4", SellerName: "Shady", Price: 800, DeliveryDays: 2, SellerRating: 3.1},
+		{SellerID: "s5", SellerName: "New Seller", Price: 990, DeliveryDays: 2},
+	}
+
+	tests := []struct {
+		name   string
+		filter domain.CompetitorFilter
+		kept   []string
+		reason map[string]string
+	}{
+		{"no filter", domain.CompetitorFilter{}, []string{"s1", "s2", "s3", "s4", "s5"}, nil},
+		{
+			"ignored by name, any case",
+			domain.CompetitorFilter{IgnoreSellers: []string{"our outlet"}},
+			[]string{"s2", "s3", "s4", "s5"},
+			map[string]string{"s1": domain.ExcludeIgnoredSeller},
+		},
+		{
+			"follow only",
+			domain.CompetitorFilter{OnlySellers: []string{"S2", "s4"}},
+			[]string{"s2", "s4"},
+			map[string]string{"s1": domain.ExcludeNotFollowed, "s3": domain.ExcludeNotFollowed, "s5": domain.ExcludeNotFollowed},
+		},
+		{
+			"slow delivery",
+			domain.CompetitorFilter{MaxDeliveryDays: 3},
+			[]string{"s1", "s2", "s4", "s5"},
+			map[string]string{"s3": domain.ExcludeSlowDelivery},
+		},
+		{
+			"low rating keeps unrated sellers",
+			domain.CompetitorFilter{MinSellerRating: 4},
+			[]string{"s1", "s2", "s3", "s5"},
+			map[string]string{"s4": domain.ExcludeLowRating},
+		},
+		{
+			"ignore wins over follow only",
+			domain.CompetitorFilter{IgnoreSellers: []string{"s2"}, OnlySellers: []string{"s2"}},
+			nil,
+			map[string]string{
+				"s1": domain.ExcludeNotFollowed,
+				"s2": domain.ExcludeIgnoredSeller,
+				"s3": domain.ExcludeNotFollowed,
+				"s4": domain.ExcludeNotFollowed,
+				"s5": domain.ExcludeNotFollowed,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, excluded := FilterOffers(offers, tt.filter)
+
+			var keptIDs []string
+			for _, o := range kept {
+				keptIDs = append(keptIDs, o.SellerID)
+			}
+			if !reflect.DeepEqual(keptIDs, tt.kept) {
+				t.Errorf("kept %v, want %v", keptIDs, tt.kept)
+			}
+
+			if len(excluded) != len(tt.reason) {
+				t.Fatalf("excluded %+v, want %v", excluded, tt.reason)
+			}
+			for _, e := range excluded {
+				if e.Reason != tt.reason[e.SellerID] {
+					t.Errorf("%s excluded for %q, want %q", e.SellerID, e.Reason, tt.reason[e.SellerID])
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeCompetitorFilter(t *testing.T) {
+	got, err := normalizeCompetitorFilter(domain.CompetitorFilter{
+		IgnoreSellers: []string{" Dumper ", "dumper", "", "Our Outlet"},
+		OnlySellers:   []string{"  "},
+	})
+	if err != nil {
+		t.Fatalf("normalizeCompetitorFilter: %v", err)
+	}
+	want := domain.CompetitorFilter{IgnoreSellers: []string{"Dumper", "Our Outlet"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("normalizeCompetitorFilter = %+v, want %+v", got, want)
+	}
+
+	tooMany := make([]string, MaxFilterSellers+1)
+	for i := range tooMany {
+		tooMany[i] = string(rune('A'+i%26)) + string(rune('a'+i/26))
+	}
+
+	for name, filter := range map[string]domain.CompetitorFilter{
+		"negative delivery days": {MaxDeliveryDays: -1},
+		"rating above the scale": {MinSellerRating: MaxSellerRating + 1},
+		"negative rating":        {MinSellerRating: -1},
+		"too many sellers":       {IgnoreSellers: tooMany},
+	} {
+		if _, err := normalizeCompetitorFilter(filter); !errors.Is(err, ErrInvalidCompetitorFilter) {
+			t.Errorf("%s: err = %v, want ErrInvalidCompetitorFilter", name, err)
+		}
+	}
+}
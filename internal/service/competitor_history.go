@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// MaxCompetitorHistoryDays caps how far back competitor history can be requested
+const MaxCompetitorHistoryDays = 90
+
+// CompetitorHistoryService keeps a time series of the competitor offers seen by price dumping
+type CompetitorHistoryService struct {
+	snapshotRepo  domain.OfferSnapshotRepository
+	productRepo   domain.ProductRepository
+	retentionDays int
+}
+
+func NewCompetitorHistoryService(
+	snapshotRepo domain.OfferSnapshotRepository,
+	productRepo domain.ProductRepository,
+	retentionDays int,
+) *CompetitorHistoryService {
+	return &CompetitorHistoryService{
+		snapshotRepo:  snapshotRepo,
+		productRepo:   productRepo,
+		retentionDays: retentionDays,
+	}
+}
+
+// PricePoint is the market of a product at one observation, for charting
+type PricePoint struct {
+	ObservedAt  time.Time `json:"observed_at"`
+	OurPrice    float64   `json:"our_price"`
+	MinPrice    float64   `json:"min_price"`
+	MedianPrice float64   `json:"median_price"`
+	OfferCount  int       `json:"offer_count"`
+}
+
+// SellerHistory is how one competitor priced a product over a period
+type SellerHistory struct {
+	SellerID     string             `json:"seller_id"`
+	SellerName   string             `json:"seller_name"`
+	FirstSeen    time.Time          `json:"first_seen"`
+	LastSeen     time.Time          `json:"last_seen"`
+	Present      bool               `json:"present"`       // In the latest observation
+	PriceChanges int                `json:"price_changes"` // Times the seller repriced while listed
+	LowestPrice  float64            `json:"lowest_price"`
+	LastPrice    float64            `json:"last_price"`
+	Points       []SellerPricePoint `json:"points"` // Observations where the seller's price or position changed
+	Events       []SellerEvent      `json:"events,omitempty"`
+}
+
+// SellerPricePoint is a seller's price and position at one observation
+type SellerPricePoint struct {
+	ObservedAt time.Time `json:"observed_at"`
+	Price      float64   `json:"price"`
+	Position   int       `json:"position"`
+}
+
+// SellerEvent is a seller appearing on or disappearing from a listing
+type SellerEvent struct {
+	At   time.Time `json:"at"`
+	Type string    `json:"type"` // appeared or disappeared
+}
+
+// Record stores the competitor offers observed for a product in a city ("" for all cities),
+// noting the sellers that appeared or disappeared since the previous observation. Failures
+// are only logged so that history never stops repricing.
+func (s *CompetitorHistoryService) Record(ctx context.Context, product *domain.Product, city string, ourPrice float64, offers []marketplace.CompetitorOffer) {
+	sorted := make([]marketplace.CompetitorOffer, len(offers))
+	copy(sorted, offers)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Price < sorted[j].Price })
+
+	snapshot := &domain.OfferSnapshot{
+		ProductID:    product.ID,
+		UserID:       product.UserID,
+		ConnectionID: product.ConnectionID,
+		City:         city,
+		OurPrice:     ourPrice,
+		MinPrice:     marketplace.MinOfferPrice(offers),
+		MedianPrice:  medianOf(sortedPrices(offers)),
+		Offers:       make([]domain.ObservedOffer, 0, len(sorted)),
+		ObservedAt:   time.Now(),
+	}
+
+	sellers := make(map[string]bool, len(sorted))
+	for i, o := range sorted {
+		snapshot.Offers = append(snapshot.Offers, domain.ObservedOffer{
+			SellerID:   o.SellerID,
+			SellerName: o.SellerName,
+			Price:      o.Price,
+			Position:   i + 1,
+		})
+		sellers[o.SellerID] = true
+	}
+
+	previous, err := s.snapshotRepo.GetLatest(ctx, product.ID, city)
+	if err != nil {
+		logger.Log.Warn("Failed to get previous offer snapshot",
+			zap.String("product_id", product.ID),
+			zap.Error(err),
+		)
+	}
+	if previous != nil {
+		before := make(map[string]bool, len(previous.Offers))
+		for _, o := range previous.Offers {
+			before[o.SellerID] = true
+		}
+		for _, o := range snapshot.Offers {
+			if !before[o.SellerID] {
+				before[o.SellerID] = true // Sellers with offers in several cities are listed once
+				snapshot.Appeared = append(snapshot.Appeared, o.SellerID)
+			}
+		}
+		for _, o := range previous.Offers {
+			if !sellers[o.SellerID] {
+				sellers[o.SellerID] = true
+				snapshot.Disappeared = append(snapshot.Disappeared, o.SellerID)
+			}
+		}
+	}
+
+	if err := s.snapshotRepo.Create(ctx, snapshot); err != nil {
+		logger.Log.Error("Failed to record competitor offers",
+			zap.String("product_id", product.ID),
+			zap.String("city", city),
+			zap.Error(err),
+		)
+	}
+}
+
+// GetPriceSeries returns the cheapest and median competitor price and our price of the user's
+// product over the last days, oldest first
+func (s *CompetitorHistoryService) GetPriceSeries(ctx context.Context, userID, productID, city string, days int) ([]PricePoint, error) {
+	snapshots, err := s.snapshots(ctx, userID, productID, city, days)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]PricePoint, 0, len(snapshots))
+	for _, snap := range snapshots {
+		points = append(points, PricePoint{
+			ObservedAt:  snap.ObservedAt,
+			OurPrice:    snap.OurPrice,
+			MinPrice:    snap.MinPrice,
+			MedianPrice: snap.MedianPrice,
+			OfferCount:  len(snap.Offers),
+		})
+	}
+
+	return points, nil
+}
+
+// GetSellerHistory returns how each competitor priced the user's product over the last days,
+// the sellers that repriced most often first
+func (s *CompetitorHistoryService) GetSellerHistory(ctx context.Context, userID, productID, city string, days int) ([]SellerHistory, error) {
+	snapshots, err := s.snapshots(ctx, userID, productID, city, days)
+	if err != nil {
+		return nil, err
+	}
+
+	bySeller := make(map[string]*SellerHistory)
+	for i, snap := range snapshots {
+		// A seller's cheapest offer stands for the seller when it has several
+		seen := make(map[string]domain.ObservedOffer, len(snap.Offers))
+		for _, o := range snap.Offers {
+			if current, ok := seen[o.SellerID]; !ok || o.Price < current.Price {
+				seen[o.SellerID] = o
+			}
+		}
+
+		for sellerID, o := range seen {
+			h, ok := bySeller[sellerID]
+			if !ok {
+				h = &SellerHistory{
+					SellerID:    sellerID,
+					FirstSeen:   snap.ObservedAt,
+					LowestPrice: o.Price,
+				}
+				bySeller[sellerID] = h
+			}
+
+			h.SellerName = o.SellerName
+			h.LastSeen = snap.ObservedAt
+			h.LowestPrice = min(h.LowestPrice, o.Price)
+
+			last := len(h.Points) - 1
+			switch {
+			case last < 0:
+				h.Points = append(h.Points, SellerPricePoint{ObservedAt: snap.ObservedAt, Price: o.Price, Position: o.Position})
+			case h.Points[last].Price != o.Price:
+				h.PriceChanges++
+				h.Points = append(h.Points, SellerPricePoint{ObservedAt: snap.ObservedAt, Price: o.Price, Position: o.Position})
+			case h.Points[last].Position != o.Position:
+				h.Points = append(h.Points, SellerPricePoint{ObservedAt: snap.ObservedAt, Price: o.Price, Position: o.Position})
+			}
+			h.LastPrice = o.Price
+		}
+
+		// The first snapshot of the period has no predecessor to compare with
+		if i == 0 {
+			continue
+		}
+		for _, sellerID := range snap.Appeared {
+			if h, ok := bySeller[sellerID]; ok {
+				h.Events = append(h.Events, SellerEvent{At: snap.ObservedAt, Type: "appeared"})
+			}
+		}
+		for _, sellerID := range snap.Disappeared {
+			if h, ok := bySeller[sellerID]; ok {
+				h.Events = append(h.Events, SellerEvent{At: snap.ObservedAt, Type: "disappeared"})
+			}
+		}
+	}
+
+	var latest time.Time
+	if len(snapshots) > 0 {
+		latest = snapshots[len(snapshots)-1].ObservedAt
+	}
+
+	history := make([]SellerHistory, 0, len(bySeller))
+	for _, h := range bySeller {
+		h.Present = h.LastSeen.Equal(latest)
+		history = append(history, *h)
+	}
+	sort.Slice(history, func(i, j int) bool {
+		if history[i].PriceChanges != history[j].PriceChanges {
+			return history[i].PriceChanges > history[j].PriceChanges
+		}
+		return history[i].SellerID < history[j].SellerID
+	})
+
+	return history, nil
+}
+
+// Prune removes snapshots older than the retention period
+func (s *CompetitorHistoryService) Prune(ctx context.Context) error {
+	if s.retentionDays <= 0 {
+		return nil
+	}
+
+	before := time.Now().AddDate(0, 0, -s.retentionDays)
+	deleted, err := s.snapshotRepo.DeleteOlderThan(ctx, before)
+	if err != nil {
+		return fmt.Errorf("failed to prune competitor history: %w", err)
+	}
+
+	logger.Log.Info("Competitor history pruned",
+		zap.Int("retention_days", s.retentionDays),
+		zap.Int64("deleted", deleted),
+	)
+
+	return nil
+}
+
+func (s *CompetitorHistoryService) snapshots(ctx context.Context, userID, productID, city string, days int) ([]domain.OfferSnapshot, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil || product == nil || product.UserID != userID {
+		return nil, ErrProductNotFound
+	}
+
+	// Products priced by city are observed per city only
+	if city != "" {
+		if cp := product.CityPrice(city); cp != nil {
+			city = cp.City
+		}
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	return s.snapshotRepo.GetByProductID(ctx, product.ID, city, since)
+}
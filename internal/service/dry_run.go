@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+)
+
+// DryRunReportWindow is how old a dry-run proposal may be to appear in the report; older ones
+// belong to products that have left dry-run mode or stopped being dumped
+const DryRunReportWindow = 24 * time.Hour
+
+// DumpingReportService reports the prices dumping proposed in dry-run mode
+type DumpingReportService struct {
+	proposalRepo domain.PriceProposalRepository
+}
+
+func NewDumpingReportService(proposalRepo domain.PriceProposalRepository) *DumpingReportService {
+	return &DumpingReportService{
+		proposalRepo: proposalRepo,
+	}
+}
+
+// DryRunReport compares the prices dumping would set with the current ones
+type DryRunReport struct {
+	Items   []DryRunItem  `json:"items"`
+	Summary DryRunSummary `json:"summary"`
+}
+
+// DryRunItem is one proposal with how far it is from the current price
+type DryRunItem struct {
+	domain.PriceProposal
+	Change        float64 `json:"change"`         // Proposed minus current price
+	ChangePercent float64 `json:"change_percent"` // Change as a percent of the current price
+}
+
+// DryRunSummary sums up a dry-run report
+type DryRunSummary struct {
+	Total                int     `json:"total"`
+	Unchanged            int     `json:"unchanged"`
+	Raises               int     `json:"raises"`
+	Cuts                 int     `json:"cuts"`
+	AverageChangePercent float64 `json:"average_change_percent"` // Over the prices that would change
+	LargestCutPercent    float64 `json:"largest_cut_percent"`
+}
+
+// Report returns the user's dry-run proposals of the last DryRunReportWindow, optionally of one
+// connection, the largest changes first. With changesOnly the proposals to keep the price are left out.
+func (s *DumpingReportService) Report(ctx context.Context, userID, connectionID string, changesOnly bool) (*DryRunReport, error) {
+	proposals, err := s.proposalRepo.GetByUserID(ctx, userID, connectionID, time.Now().Add(-DryRunReportWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DryRunReport{Items: make([]DryRunItem, 0, len(proposals))}
+	var changeSum float64
+	for _, p := range proposals {
+		item := DryRunItem{PriceProposal: p, Change: p.ProposedPrice - p.CurrentPrice}
+		if p.CurrentPrice > 0 {
+			item.ChangePercent = math.Round(item.Change/p.CurrentPrice*10000) / 100
+		}
+
+		report.Summary.Total++
+		switch {
+		case item.Change > 0:
+			report.Summary.Raises++
+		case item.Change < 0:
+			report.Summary.Cuts++
+			report.Summary.LargestCutPercent = math.Min(report.Summary.LargestCutPercent, item.ChangePercent)
+		default:
+			report.Summary.Unchanged++
+			if changesOnly {
+				continue
+			}
+		}
+		changeSum += item.ChangePercent
+
+		report.Items = append(report.Items, item)
+	}
+
+	if changed := report.Summary.Raises + report.Summary.Cuts; changed > 0 {
+		report.Summary.AverageChangePercent = math.Round(changeSum/float64(changed)*100) / 100
+	}
+
+	sort.SliceStable(report.Items, func(i, j int) bool {
+		return math.Abs(report.Items[i].ChangePercent) > math.Abs(report.Items[j].ChangePercent)
+	})
+
+	return report, nil
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+)
+
+// fakeProposalRepo keeps saved proposals in memory
+type fakeProposalRepo struct {
+	saved []domain.PriceProposal
+}
+
+func (r *fakeProposalRepo) Save(ctx context.Context, proposal *domain.PriceProposal) error {
+	r.saved = append(r.saved, *proposal)
+	return nil
+}
+
+func (r *fakeProposalRepo) GetByUserID(ctx context.Context, userID, connectionID string, since time.Time) ([]domain.PriceProposal, error) {
+	var proposals []domain.PriceProposal
+	for _, p := range r.saved {
+		if p.UserID == userID && (connectionID == "" || p.ConnectionID == connectionID) && !p.ProposedAt.Before(since) {
+			proposals = append(proposals, p)
+		}
+	}
+	return proposals, nil
+}
+
+func (r *fakeProposalRepo) DeleteByConnectionID(ctx context.Context, connectionID string) error {
+	return nil
+}
+
+// fakePriceChangeRepo serves a product's recorded price changes, newest first
+type fakePriceChangeRepo struct {
+	changes []domain.PriceChange
+}
+
+func (r *fakePriceChangeRepo) Create(ctx context.Context, change *domain.PriceChange) error {
+	r.changes = append([]domain.PriceChange{*change}, r.changes...)
+	return nil
+}
+
+func (r *fakePriceChangeRepo) GetByProductID(ctx context.Context, productID string, since time.Time, limit int) ([]domain.PriceChange, error) {
+	var changes []domain.PriceChange
+	for _, c := range r.changes {
+		if c.ProductID == productID && !c.CreatedAt.Before(since) {
+			changes = append(changes, c)
+		}
+	}
+	return changes, nil
+}
+
+func (r *fakePriceChangeRepo) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *fakePriceChangeRepo) DeleteByConnectionID(ctx context.Context, connectionID string) error {
+	return nil
+}
+
+func TestRecordProposal(t *testing.T) {
+	product := &domain.Product{ID: "p1", UserID: "u1", ConnectionID: "c1", Name: "Kettle", SKU: "KTL-1"}
+
+	tests := []struct {
+		name       string
+		ok         bool
+		proposed   float64
+		changes    []domain.PriceChange
+		wantPrice  float64
+		wantReason string
+		wantGuard  string
+	}{
+		{"hold", false, 0, nil, 1000, PriceReasonHold, ""},
+		{"raise", true, 1100, nil, 1100, PriceReasonStrategy, ""},
+		{"cut within the guard", true, 980, nil, 980, PriceReasonStrategy, ""},
+		{"cut capped per cycle", true, 900, nil, 950, PriceReasonRateLimited, ""},
+		{"guard would pause", true, 999, undercutsOf(product, 12, time.Now()), 1000, PriceReasonGuardPaused, domain.GuardConsecutiveUndercuts},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeProposalRepo{}
+			history := NewPriceHistoryService(&fakePriceChangeRepo{changes: tt.changes}, nil, 0)
+			s := &PriceDumpingService{proposalRepo: repo, guards: NewPriceGuardService(nil, nil, nil, history)}
+
+			proposal := &domain.PriceProposal{ProductID: product.ID, CurrentPrice: 1000, ProposedPrice: tt.proposed, Reason: PriceReasonStrategy}
+			s.recordProposal(context.Background(), proposal, product, tt.ok, offersAt(1000), DefaultPriceGuard)
+
+			if len(repo.saved) != 1 {
+				t.Fatalf("saved %d proposals, want 1", len(repo.saved))
+			}
+			got := repo.saved[0]
+			if got.ProposedPrice != tt.wantPrice || got.Reason != tt.wantReason || got.Guard != tt.wantGuard {
+				t.Errorf("proposal = %v, %q, guard %q, want %v, %q, guard %q", got.ProposedPrice, got.Reason, got.Guard, tt.wantPrice, tt.wantReason, tt.wantGuard)
+			}
+			if got.UserID != "u1" || got.ConnectionID != "c1" || got.ProductName != "Kettle" || got.SKU != "KTL-1" || got.ProposedAt.IsZero() {
+				t.Errorf("proposal product fields = %+v", got)
+			}
+
+			// Dry-run never pauses the product
+			if product.DumpingPaused(time.Now()) {
+				t.Error("product paused by a dry-run proposal")
+			}
+		})
+	}
+}
+
+func TestDumpingReport(t *testing.T) {
+	now := time.Now()
+	repo := &fakeProposalRepo{saved: []domain.PriceProposal{
+		{ProductID: "held", UserID: "u1", CurrentPrice: 1000, ProposedPrice: 1000, ProposedAt: now},
+		{ProductID: "raised", UserID: "u1", CurrentPrice: 500, ProposedPrice: 525, ProposedAt: now},
+		{ProductID: "cut", UserID: "u1", CurrentPrice: 1000, ProposedPrice: 900, ProposedAt: now},
+		{ProductID: "stale", UserID: "u1", CurrentPrice: 1000, ProposedPrice: 500, ProposedAt: now.Add(-2 * DryRunReportWindow)},
+		{ProductID: "other user", UserID: "u2", CurrentPrice: 1000, ProposedPrice: 800, ProposedAt: now},
+	}}
+	s := NewDumpingReportService(repo)
+
+	tests := []struct {
+		name        string
+		changesOnly bool
+		want        []string
+	}{
+		{"all", false, []string{"cut", "raised", "held"}},
+		{"changes only", true, []string{"cut", "raised"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := s.Report(context.Background(), "u1", "", tt.changesOnly)
+			if err != nil {
+				t.Fatalf("Report: %v", err)
+			}
+
+			var got []string
+			for _, item := range report.Items {
+				got = append(got, item.ProductID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("items %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("items %v, want %v", got, tt.want)
+					break
+				}
+			}
+
+			want := DryRunSummary{Total: 3, Unchanged: 1, Raises: 1, Cuts: 1, AverageChangePercent: -2.5, LargestCutPercent: -10}
+			if report.Summary != want {
+				t.Errorf("summary = %+v, want %+v", report.Summary, want)
+			}
+			if cut := report.Items[0]; cut.Change != -100 || cut.ChangePercent != -10 {
+				t.Errorf("cut item = %v, %v%%, want -100, -10%%", cut.Change, cut.ChangePercent)
+			}
+		})
+	}
+}
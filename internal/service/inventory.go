@@ -1,8 +1,10 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/yourusername/seller-assistant/internal/domain"
@@ -10,27 +12,34 @@ import (
 	"go.uber.org/zap"
 )
 
+// salesWindowDays is how many days of sales the velocity is averaged over
+const salesWindowDays = 30
+
 type InventoryService struct {
 	productRepo      domain.ProductRepository
 	salesHistoryRepo domain.SalesHistoryRepository
+	orderRepo        domain.OrderRepository
 	alertRepo        domain.LowStockAlertRepository
 }
 
 func NewInventoryService(
 	productRepo domain.ProductRepository,
 	salesHistoryRepo domain.SalesHistoryRepository,
+	orderRepo domain.OrderRepository,
 	alertRepo domain.LowStockAlertRepository,
 ) *InventoryService {
 	return &InventoryService{
 		productRepo:      productRepo,
 		salesHistoryRepo: salesHistoryRepo,
+		orderRepo:        orderRepo,
 		alertRepo:        alertRepo,
 	}
 }
 
-// CalculateDaysOfStock calculates how many days of stock remain based on sales velocity
-func (s *InventoryService) CalculateDaysOfStock(productID string) (int, error) {
-	product, err := s.productRepo.GetByID(productID)
+// CalculateDaysOfStock calculates how many days of stock remain based on sales velocity,
+// in total and at each of the product's warehouses
+func (s *InventoryService) CalculateDaysOfStock(ctx context.Context, productID string) (int, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get product: %w", err)
 	}
@@ -40,7 +49,7 @@ func (s *InventoryService) CalculateDaysOfStock(productID string) (int, error) {
 	}
 
 	// Get sales history for the last 30 days
-	salesHistory, err := s.salesHistoryRepo.GetByProductID(productID, 30)
+	salesHistory, err := s.salesHistoryRepo.GetByProductID(ctx, productID, salesWindowDays)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get sales history: %w", err)
 	}
@@ -49,26 +58,95 @@ func (s *InventoryService) CalculateDaysOfStock(productID string) (int, error) {
 	salesVelocity := s.calculateSalesVelocity(salesHistory)
 
 	// Calculate days of stock
-	daysOfStock := 0
-	if salesVelocity > 0 {
-		daysOfStock = int(math.Ceil(float64(product.CurrentStock) / salesVelocity))
-	} else if product.CurrentStock > 0 {
-		// If no sales history, assume infinite stock
-		daysOfStock = 999
+	daysOfStock := daysOfStockFor(product.CurrentStock, salesVelocity)
+
+	if len(product.Stocks) > 0 {
+		if err := s.calculateLocationStock(ctx, product, salesVelocity); err != nil {
+			logger.Log.Warn("Failed to calculate days of stock per location",
+				zap.String("product_id", product.ID),
+				zap.Error(err),
+			)
+		}
 	}
 
 	// Update product with new calculations
-	product.SalesVelocity = salesVelocity
-	product.DaysOfStock = daysOfStock
-	product.LastSyncAt = time.Now()
-
-	if err := s.productRepo.Update(product); err != nil {
+	if err := s.productRepo.UpdateStockMetrics(ctx, product.ID, salesVelocity, daysOfStock, product.Stocks); err != nil {
 		return 0, fmt.Errorf("failed to update product: %w", err)
 	}
 
 	return daysOfStock, nil
 }
 
+// calculateLocationStock splits the product's sales velocity across its warehouses by where
+// the orders of the last 30 days were fulfilled, and calculates days of stock for each.
+// Orders that do not name a warehouse are matched by the customer's city; orders that match
+// no warehouse still count towards the total, so the shares never add up to more than it.
+func (s *InventoryService) calculateLocationStock(ctx context.Context, product *domain.Product, salesVelocity float64) error {
+	orders, err := s.orderRepo.GetByProductID(ctx, product.ID, time.Now().AddDate(0, 0, -salesWindowDays))
+	if err != nil {
+		return fmt.Errorf("failed to get orders: %w", err)
+	}
+
+	sold := make(map[string]int)
+	total := 0
+	for _, order := range orders {
+		if !order.CountsAsSale() {
+			continue
+		}
+
+		warehouseID := order.WarehouseID
+		if warehouseID == "" {
+			warehouseID = warehouseInCity(product.Stocks, order.CustomerCity)
+		}
+
+		for _, line := range order.Lines {
+			if line.ProductID != product.ID {
+				continue
+			}
+			total += line.Quantity
+			if warehouseID != "" {
+				sold[warehouseID] += line.Quantity
+			}
+		}
+	}
+
+	for i := range product.Stocks {
+		stock := &product.Stocks[i]
+		stock.SalesVelocity = 0
+		if total > 0 {
+			stock.SalesVelocity = salesVelocity * float64(sold[stock.WarehouseID]) / float64(total)
+		}
+		stock.DaysOfStock = daysOfStockFor(stock.Quantity, stock.SalesVelocity)
+	}
+
+	return nil
+}
+
+// warehouseInCity returns the first warehouse located in the city, or ""
+func warehouseInCity(stocks []domain.WarehouseStock, city string) string {
+	if city == "" {
+		return ""
+	}
+	for _, s := range stocks {
+		if strings.EqualFold(s.City, city) {
+			return s.WarehouseID
+		}
+	}
+	return ""
+}
+
+// daysOfStockFor converts a stock level and a daily sales velocity into days of stock
+func daysOfStockFor(stock int, salesVelocity float64) int {
+	if salesVelocity > 0 {
+		return int(math.Ceil(float64(stock) / salesVelocity))
+	}
+	if stock > 0 {
+		// If no sales history, assume infinite stock
+		return 999
+	}
+	return 0
+}
+
 // calculateSalesVelocity calculates average daily sales
 func (s *InventoryService) calculateSalesVelocity(salesHistory []domain.SalesHistory) float64 {
 	if len(salesHistory) == 0 {
@@ -89,57 +167,79 @@ func (s *InventoryService) calculateSalesVelocity(salesHistory []domain.SalesHis
 	return float64(totalSold) / float64(daysWithData)
 }
 
-// ProcessLowStockAlerts checks for low stock and creates alerts
-func (s *InventoryService) ProcessLowStockAlerts(userID string, thresholdDays int) error {
-	products, err := s.productRepo.GetLowStockProducts(userID, thresholdDays)
+// ProcessLowStockAlerts checks for low stock and creates alerts, one for a product running
+// low in total and one for each location where it is selling and running low or sold out
+func (s *InventoryService) ProcessLowStockAlerts(ctx context.Context, userID string, thresholdDays int) error {
+	products, err := s.productRepo.GetLowStockProducts(ctx, userID, thresholdDays)
 	if err != nil {
 		return fmt.Errorf("failed to get low stock products: %w", err)
 	}
 
 	// Check if we've already sent recent alerts (within last 24 hours)
-	recentAlerts, err := s.alertRepo.GetRecentAlerts(userID, 24)
+	recentAlerts, err := s.alertRepo.GetRecentAlerts(ctx, userID, 24)
 	if err != nil {
 		return fmt.Errorf("failed to get recent alerts: %w", err)
 	}
 
-	// Create map of products with recent alerts
-	alertedProducts := make(map[string]bool)
+	// Create map of products and locations with recent alerts
+	alerted := make(map[string]bool)
 	for _, alert := range recentAlerts {
-		alertedProducts[alert.ProductID] = true
+		alerted[alert.ProductID+"/"+alert.WarehouseID] = true
 	}
 
-	// Create new alerts for products without recent alerts
+	// Create new alerts for products and locations without recent alerts
 	for _, product := range products {
-		if !alertedProducts[product.ID] {
-			alert := &domain.LowStockAlert{
-				ProductID:     product.ID,
-				UserID:        userID,
-				ThresholdDays: thresholdDays,
-			}
+		if product.DaysOfStock > 0 && product.DaysOfStock <= thresholdDays {
+			s.createAlert(ctx, alerted, &product, "", product.DaysOfStock, thresholdDays)
+		}
 
-			if err := s.alertRepo.Create(alert); err != nil {
-				logger.Log.Error("Failed to create low stock alert",
-					zap.String("product_id", product.ID),
-					zap.Error(err),
-				)
-				continue
+		for _, stock := range product.Stocks {
+			if stock.SalesVelocity > 0 && stock.DaysOfStock <= thresholdDays {
+				s.createAlert(ctx, alerted, &product, stock.WarehouseID, stock.DaysOfStock, thresholdDays)
 			}
-
-			logger.Log.Info("Created low stock alert",
-				zap.String("user_id", userID),
-				zap.String("product_id", product.ID),
-				zap.String("product_name", product.Name),
-				zap.Int("days_of_stock", product.DaysOfStock),
-			)
 		}
 	}
 
 	return nil
 }
 
+// createAlert stores a low stock alert for the product at a warehouse ("" for the total)
+// unless one was sent recently
+func (s *InventoryService) createAlert(ctx context.Context, alerted map[string]bool, product *domain.Product, warehouseID string, daysOfStock, thresholdDays int) {
+	key := product.ID + "/" + warehouseID
+	if alerted[key] {
+		return
+	}
+
+	alert := &domain.LowStockAlert{
+		ProductID:     product.ID,
+		UserID:        product.UserID,
+		WarehouseID:   warehouseID,
+		ThresholdDays: thresholdDays,
+	}
+
+	if err := s.alertRepo.Create(ctx, alert); err != nil {
+		logger.Log.Error("Failed to create low stock alert",
+			zap.String("product_id", product.ID),
+			zap.String("warehouse_id", warehouseID),
+			zap.Error(err),
+		)
+		return
+	}
+	alerted[key] = true
+
+	logger.Log.Info("Created low stock alert",
+		zap.String("user_id", product.UserID),
+		zap.String("product_id", product.ID),
+		zap.String("product_name", product.Name),
+		zap.String("warehouse_id", warehouseID),
+		zap.Int("days_of_stock", daysOfStock),
+	)
+}
+
 // GetLowStockSummary returns a summary of low stock products
-func (s *InventoryService) GetLowStockSummary(userID string, thresholdDays int) ([]domain.Product, error) {
-	products, err := s.productRepo.GetLowStockProducts(userID, thresholdDays)
+func (s *InventoryService) GetLowStockSummary(ctx context.Context, userID string, thresholdDays int) ([]domain.Product, error) {
+	products, err := s.productRepo.GetLowStockProducts(ctx, userID, thresholdDays)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get low stock products: %w", err)
 	}
@@ -148,14 +248,18 @@ func (s *InventoryService) GetLowStockSummary(userID string, thresholdDays int)
 }
 
 // RecalculateAllProducts recalculates days of stock for all user products
-func (s *InventoryService) RecalculateAllProducts(userID string) error {
-	products, err := s.productRepo.GetByUserID(userID)
+func (s *InventoryService) RecalculateAllProducts(ctx context.Context, userID string) error {
+	products, err := s.productRepo.GetByUserID(ctx, userID)
 	if err != nil {
 		return fmt.Errorf("failed to get products: %w", err)
 	}
 
 	for _, product := range products {
-		if _, err := s.CalculateDaysOfStock(product.ID); err != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if _, err := s.CalculateDaysOfStock(ctx, product.ID); err != nil {
 			logger.Log.Error("Failed to calculate days of stock",
 				zap.String("product_id", product.ID),
 				zap.Error(err),
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/pkg/crypto"
+)
+
+// ClientProvider returns a ready-to-use marketplace client for a stored connection.
+// Services depend on this instead of building clients, so tests can inject fakes.
+type ClientProvider interface {
+	ClientFor(conn *domain.MarketplaceConnection) (marketplace.MarketplaceClient, error)
+}
+
+// clientIdleTimeout is how long a cached client may go unused before it is dropped, so
+// clients of connections deleted through another process do not pile up
+const clientIdleTimeout = 24 * time.Hour
+
+// MarketplaceClients builds clients through a marketplace.Registry and caches them
+// per connection, so credentials are decrypted once per connection version rather than on every run
+type MarketplaceClients struct {
+	registry  *marketplace.Registry
+	encryptor *crypto.Encryptor
+
+	mu    sync.Mutex
+	cache map[string]cachedClient
+}
+
+type cachedClient struct {
+	updatedAt time.Time
+	usedAt    time.Time
+	client    marketplace.MarketplaceClient
+}
+
+func NewMarketplaceClients(registry *marketplace.Registry, encryptor *crypto.Encryptor) *MarketplaceClients {
+	return &MarketplaceClients{
+		registry:  registry,
+		encryptor: encryptor,
+		cache:     make(map[string]cachedClient),
+	}
+}
+
+// ClientFor returns the cached client for conn, rebuilding it when the connection was updated
+func (m *MarketplaceClients) ClientFor(conn *domain.MarketplaceConnection) (marketplace.MarketplaceClient, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if cached, ok := m.cache[conn.ID]; ok && conn.ID != "" && cached.updatedAt.Equal(conn.UpdatedAt) {
+		cached.usedAt = now
+		m.cache[conn.ID] = cached
+		return cached.client, nil
+	}
+
+	creds, err := m.decrypt(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := m.registry.NewClient(marketplace.Type(conn.Marketplace), creds)
+	if err != nil {
+		return nil, err
+	}
+
+	if conn.ID != "" {
+		m.pruneIdle(now)
+		m.cache[conn.ID] = cachedClient{updatedAt: conn.UpdatedAt, usedAt: now, client: client}
+	}
+
+	return client, nil
+}
+
+// Forget drops the cached client of a connection; called when the connection is deleted
+func (m *MarketplaceClients) Forget(connectionID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.cache, connectionID)
+}
+
+// pruneIdle drops clients unused for clientIdleTimeout. Callers hold m.mu.
+func (m *MarketplaceClients) pruneIdle(now time.Time) {
+	for id, cached := range m.cache {
+		if now.Sub(cached.usedAt) > clientIdleTimeout {
+			delete(m.cache, id)
+		}
+	}
+}
+
+func (m *MarketplaceClients) decrypt(conn *domain.MarketplaceConnection) (marketplace.Credentials, error) {
+	apiKey, err := m.encryptor.Decrypt(conn.APIKeyEncrypted)
+	if err != nil {
+		return marketplace.Credentials{}, fmt.Errorf("failed to decrypt API key: %w", err)
+	}
+
+	creds := marketplace.Credentials{
+		APIKey:     apiKey,
+		MerchantID: conn.MerchantID,
+	}
+
+	if conn.APISecretEncrypted != "" {
+		secret, err := m.encryptor.Decrypt(conn.APISecretEncrypted)
+		if err != nil {
+			return marketplace.Credentials{}, fmt.Errorf("failed to decrypt API secret: %w", err)
+		}
+		creds.APISecret = secret
+	}
+
+	return creds, nil
+}
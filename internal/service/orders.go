@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/pkg/logger"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrOrderNotFound is returned for unknown orders and orders of another user
+	ErrOrderNotFound = errors.New("order not found")
+
+	// ErrInvalidCancelReason is returned when a cancellation has no or an unknown reason code
+	ErrInvalidCancelReason = errors.New("a valid cancellation reason is required")
+)
+
+// OrderService performs fulfillment actions on synced orders through the marketplace API
+type OrderService struct {
+	orderRepo      domain.OrderRepository
+	connectionRepo domain.MarketplaceConnectionRepository
+	clients        ClientProvider
+}
+
+func NewOrderService(
+	orderRepo domain.OrderRepository,
+	connectionRepo domain.MarketplaceConnectionRepository,
+	clients ClientProvider,
+) *OrderService {
+	return &OrderService{
+		orderRepo:      orderRepo,
+		connectionRepo: connectionRepo,
+		clients:        clients,
+	}
+}
+
+// OrderActionResult is the outcome of an action on one order of a bulk request
+type OrderActionResult struct {
+	OrderID string `json:"order_id"`
+	Status  string `json:"status,omitempty"` // New status on success
+	Error   string `json:"error,omitempty"`
+}
+
+// Apply takes an action (accept, ready or cancel) on the user's order. The transition is
+// checked against the order lifecycle before the marketplace is called, and recorded in
+// the order history with the acting user once the marketplace has accepted it.
+func (s *OrderService) Apply(ctx context.Context, userID, orderID, action, reason string) (*domain.Order, error) {
+	if action == domain.OrderActionCancel && !marketplace.IsCancelReason(reason) {
+		return nil, ErrInvalidCancelReason
+	}
+	if action != domain.OrderActionCancel {
+		reason = ""
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil || order == nil || order.UserID != userID {
+		return nil, ErrOrderNotFound
+	}
+
+	next, err := order.NextStatus(action)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := s.connectionRepo.GetByID(ctx, order.ConnectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	if conn == nil {
+		return nil, fmt.Errorf("connection %s of order %s no longer exists", order.ConnectionID, order.ID)
+	}
+
+	client, err := s.clients.ClientFor(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	switch action {
+	case domain.OrderActionAccept:
+		err = client.AcceptOrder(ctx, order.ExternalID)
+	case domain.OrderActionReady:
+		err = client.MarkOrderReady(ctx, order.ExternalID)
+	case domain.OrderActionCancel:
+		err = client.CancelOrder(ctx, order.ExternalID, reason)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s order on %s: %w", action, conn.Marketplace, err)
+	}
+
+	change := domain.OrderStatusChange{
+		Action: action,
+		From:   order.Status,
+		To:     next,
+		Reason: reason,
+		UserID: userID,
+		At:     time.Now(),
+	}
+
+	// The marketplace already changed the order; a failed write is corrected by the next sync
+	if err := s.orderRepo.RecordStatusChange(ctx, order.ID, change); err != nil {
+		logger.Log.Error("Failed to record order status change",
+			zap.String("order_id", order.ID),
+			zap.String("action", action),
+			zap.Error(err),
+		)
+	}
+
+	logger.Log.Info("Order status changed",
+		zap.String("user_id", userID),
+		zap.String("order_id", order.ID),
+		zap.String("marketplace", conn.Marketplace),
+		zap.String("from", change.From),
+		zap.String("to", change.To),
+		zap.String("reason", reason),
+	)
+
+	order.Status = next
+	order.History = append(order.History, change)
+
+	return order, nil
+}
+
+// ApplyBulk takes the same action on several orders one by one; a failure on one
+// order does not stop the others
+func (s *OrderService) ApplyBulk(ctx context.Context, userID string, orderIDs []string, action, reason string) []OrderActionResult {
+	results := make([]OrderActionResult, 0, len(orderIDs))
+
+	for _, id := range orderIDs {
+		if err := ctx.Err(); err != nil {
+			results = append(results, OrderActionResult{OrderID: id, Error: err.Error()})
+			continue
+		}
+
+		order, err := s.Apply(ctx, userID, id, action, reason)
+		if err != nil {
+			results = append(results, OrderActionResult{OrderID: id, Error: err.Error()})
+			continue
+		}
+
+		results = append(results, OrderActionResult{OrderID: id, Status: order.Status})
+	}
+
+	return results
+}
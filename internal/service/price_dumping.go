@@ -1,55 +1,101 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/yourusername/seller-assistant/internal/domain"
-	"github.com/yourusername/seller-assistant/internal/marketplace/kaspi"
-	"github.com/yourusername/seller-assistant/pkg/crypto"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
 	"github.com/yourusername/seller-assistant/pkg/logger"
 	"go.uber.org/zap"
 )
 
 const (
-	// PriceDumpMargin - на сколько тенге ставим цену дешевле конкурентов
+	// PriceDumpMargin is how many tenge the default strategy undercuts competitors by
 	PriceDumpMargin = 1.0
 )
 
 type PriceDumpingService struct {
-	kaspiKeyRepo domain.KaspiKeyRepository
-	productRepo  domain.ProductRepository
-	encryptor    *crypto.Encryptor
+	userRepo        domain.UserRepository
+	connectionRepo  domain.MarketplaceConnectionRepository
+	productRepo     domain.ProductRepository
+	tagStrategyRepo domain.TagPricingStrategyRepository
+	filterRepo      domain.CompetitorFilterRepository
+	clients         ClientProvider
+	feedService     *PriceFeedService
+	historyService  *PriceHistoryService
+	offerHistory    *CompetitorHistoryService
+	proposalRepo    domain.PriceProposalRepository
+	guards          *PriceGuardService
+	dryRunOnly      bool // All products run in dry-run regardless of their settings
 }
 
 func NewPriceDumpingService(
-	kaspiKeyRepo domain.KaspiKeyRepository,
+	userRepo domain.UserRepository,
+	connectionRepo domain.MarketplaceConnectionRepository,
 	productRepo domain.ProductRepository,
-	encryptor *crypto.Encryptor,
+	tagStrategyRepo domain.TagPricingStrategyRepository,
+	filterRepo domain.CompetitorFilterRepository,
+	clients ClientProvider,
+	feedService *PriceFeedService,
+	historyService *PriceHistoryService,
+	offerHistory *CompetitorHistoryService,
+	proposalRepo domain.PriceProposalRepository,
+	guards *PriceGuardService,
 ) *PriceDumpingService {
 	return &PriceDumpingService{
-		kaspiKeyRepo: kaspiKeyRepo,
-		productRepo:  productRepo,
-		encryptor:    encryptor,
+		userRepo:        userRepo,
+		connectionRepo:  connectionRepo,
+		productRepo:     productRepo,
+		tagStrategyRepo: tagStrategyRepo,
+		filterRepo:      filterRepo,
+		clients:         clients,
+		feedService:     feedService,
+		historyService:  historyService,
+		offerHistory:    offerHistory,
+		proposalRepo:    proposalRepo,
+		guards:          guards,
 	}
 }
 
-// ProcessAllUsers обрабатывает автодемпинг для всех пользователей с включенной опцией
-func (s *PriceDumpingService) ProcessAllUsers() error {
-	keys, err := s.kaspiKeyRepo.GetAllActive()
+// SetDryRunOnly puts every product in dry-run: prices are computed and stored as
+// proposals but never changed on the marketplace
+func (s *PriceDumpingService) SetDryRunOnly(dryRunOnly bool) {
+	s.dryRunOnly = dryRunOnly
+}
+
+// dumpingPlan is how a product is dumped
+type dumpingPlan struct {
+	cfg    domain.PricingStrategyConfig
+	filter domain.CompetitorFilter
+	guard  domain.PriceGuard // Price war guard
+	dryRun bool              // Only compute and store the price, never change it
+}
+
+// ProcessAllUsers runs price dumping for every active marketplace connection
+func (s *PriceDumpingService) ProcessAllUsers(ctx context.Context) error {
+	conns, err := s.connectionRepo.GetAllActive(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get active keys: %w", err)
+		return fmt.Errorf("failed to get active connections: %w", err)
 	}
 
-	logger.Log.Info("Starting price dumping cycle", zap.Int("users_count", len(keys)))
+	logger.Log.Info("Starting price dumping cycle", zap.Int("connections_count", len(conns)))
 
 	successCount := 0
 	errorCount := 0
 
-	for _, key := range keys {
-		if err := s.ProcessUserProducts(key.UserID, &key); err != nil {
-			logger.Log.Error("Failed to process user products",
-				zap.String("user_id", key.UserID),
+	for _, conn := range conns {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("price dumping cycle aborted: %w", err)
+		}
+
+		if err := s.ProcessConnectionProducts(ctx, &conn); err != nil {
+			logger.Log.Error("Failed to process connection products",
+				zap.String("user_id", conn.UserID),
+				zap.String("connection_id", conn.ID),
 				zap.Error(err),
 			)
 			errorCount++
@@ -66,52 +112,104 @@ func (s *PriceDumpingService) ProcessAllUsers() error {
 	return nil
 }
 
-// ProcessUserProducts обрабатывает автодемпинг для товаров конкретного пользователя
-func (s *PriceDumpingService) ProcessUserProducts(userID string, key *domain.KaspiKey) error {
-	// Получаем товары для демпинга
-	products, err := s.productRepo.GetProductsForDumping(userID)
+// ProcessConnectionProducts runs price dumping for the products of one connection
+func (s *PriceDumpingService) ProcessConnectionProducts(ctx context.Context, conn *domain.MarketplaceConnection) error {
+	// Products with dumping enabled
+	products, err := s.productRepo.GetProductsForDumping(ctx, conn.ID)
 	if err != nil {
 		return fmt.Errorf("failed to get products for dumping: %w", err)
 	}
 
 	if len(products) == 0 {
-		logger.Log.Debug("No products for dumping", zap.String("user_id", userID))
+		logger.Log.Debug("No products for dumping", zap.String("connection_id", conn.ID))
 		return nil
 	}
 
 	logger.Log.Info("Processing products for dumping",
-		zap.String("user_id", userID),
+		zap.String("connection_id", conn.ID),
+		zap.String("marketplace", conn.Marketplace),
 		zap.Int("products_count", len(products)),
 	)
 
-	// Создаем Kaspi клиент
-	client, err := s.getKaspiClient(key)
+	// Marketplace client
+	client, err := s.clients.ClientFor(conn)
+	if err != nil {
+		return fmt.Errorf("failed to create marketplace client: %w", err)
+	}
+
+	// User's tag strategies
+	tagStrategies, err := s.tagStrategyRepo.GetByUserID(ctx, conn.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get tag pricing strategies: %w", err)
+	}
+	strategiesByTag := make(map[string]domain.PricingStrategyConfig, len(tagStrategies))
+	for _, ts := range tagStrategies {
+		strategiesByTag[ts.Tag] = ts.Strategy
+	}
+
+	// User's competitor filter
+	userFilter, err := s.filterRepo.GetByUserID(ctx, conn.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get competitor filter: %w", err)
+	}
+	var userCompetitorFilter *domain.CompetitorFilter
+	if userFilter != nil {
+		userCompetitorFilter = &userFilter.Filter
+	}
+
+	// User's price war guard
+	guard, err := s.guards.guardFor(ctx, conn.UserID)
 	if err != nil {
-		return fmt.Errorf("failed to create Kaspi client: %w", err)
+		return err
 	}
 
+	// User's dry-run mode
+	user, err := s.userRepo.GetByID(ctx, conn.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	userDryRun := s.dryRunOnly || (user != nil && user.DumpingDryRun)
+
 	processedCount := 0
 	updatedCount := 0
 
 	for _, product := range products {
-		if err := s.processProduct(&product, client); err != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		cfg, _, _ := StrategyConfigFor(&product, strategiesByTag)
+		plan := dumpingPlan{
+			cfg:    cfg,
+			filter: CombineCompetitorFilters(userCompetitorFilter, product.CompetitorFilter),
+			guard:  guard,
+			dryRun: userDryRun || product.DumpingDryRun,
+		}
+
+		changed, err := s.processProduct(ctx, &product, plan, client)
+		if err != nil {
 			logger.Log.Error("Failed to process product",
 				zap.String("product_id", product.ID),
 				zap.String("product_name", product.Name),
 				zap.Error(err),
 			)
-			continue
+		} else {
+			processedCount++
 		}
-		processedCount++
 
-		// Если цена была обновлена
-		if product.LastPriceCheckAt.After(time.Now().Add(-10 * time.Second)) {
+		// A price may have changed in some cities even if others failed
+		if changed {
 			updatedCount++
 		}
 	}
 
+	// The Kaspi price feed must show the new prices
+	if updatedCount > 0 {
+		s.feedService.RegenerateQuietly(ctx, conn)
+	}
+
 	logger.Log.Info("User products processed",
-		zap.String("user_id", userID),
+		zap.String("connection_id", conn.ID),
 		zap.Int("processed", processedCount),
 		zap.Int("updated", updatedCount),
 	)
@@ -119,92 +217,417 @@ func (s *PriceDumpingService) ProcessUserProducts(userID string, key *domain.Kas
 	return nil
 }
 
-// processProduct обрабатывает один товар
-func (s *PriceDumpingService) processProduct(product *domain.Product, client *kaspi.Client) error {
-	// Получаем цены конкурентов
-	competitorPrices, err := client.GetCompetitorPrices(product.ExternalID)
+// processProduct dumps one product. A product with city prices is dumped in each city
+// separately with that city's floor. Offers excluded by the competitor filter are ignored.
+// changed reports whether the price changed on the marketplace, in at least one city.
+func (s *PriceDumpingService) processProduct(ctx context.Context, product *domain.Product, plan dumpingPlan, client marketplace.MarketplaceClient) (changed bool, err error) {
+	strategy, err := NewPricingStrategy(plan.cfg)
 	if err != nil {
-		return fmt.Errorf("failed to get competitor prices: %w", err)
+		return false, err
 	}
 
-	if len(competitorPrices) == 0 {
+	if len(product.CityPrices) > 0 {
+		var errs []error
+		for i := range product.CityPrices {
+			if err := ctx.Err(); err != nil {
+				return changed, err
+			}
+			cityChanged, err := s.processCity(ctx, product, &product.CityPrices[i], plan, strategy, client)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", product.CityPrices[i].City, err))
+			}
+			changed = changed || cityChanged
+			// The price war guard paused dumping of the product in all cities
+			if product.DumpingPaused(time.Now()) {
+				break
+			}
+		}
+		return changed, errors.Join(errs...)
+	}
+
+	// Competitor prices
+	competitorPrices, err := client.GetCompetitorPrices(ctx, product.ExternalID, "")
+	if errors.Is(err, marketplace.ErrNotFound) {
+		logger.Log.Warn("Product not found on marketplace, skipping",
+			zap.String("product_id", product.ID),
+			zap.String("external_id", product.ExternalID),
+		)
+		return false, nil
+	}
+	if errors.Is(err, marketplace.ErrNotSupported) {
+		logger.Log.Debug("Marketplace does not expose competitor offers, skipping", zap.String("product_id", product.ID))
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get competitor prices: %w", err)
+	}
+
+	// Keep competitor offers for history
+	s.offerHistory.Record(ctx, product, "", product.Price, competitorPrices)
+
+	// Only the competitors being watched
+	competitorPrices, excluded := FilterOffers(competitorPrices, plan.filter)
+
+	if len(competitorPrices) == 0 && product.MaxPrice == 0 {
 		logger.Log.Debug("No competitors found", zap.String("product_id", product.ID))
-		return nil
+		return false, nil
 	}
 
-	// Находим минимальную цену конкурента
-	minCompetitorPrice := kaspi.GetMinCompetitorPrice(competitorPrices)
+	// Lowest competitor price
+	minCompetitorPrice := marketplace.MinOfferPrice(competitorPrices)
+
+	// New price by the product's strategy between the floor and the ceiling.
+	// The floor is the min price, or the margin floor if that is higher
+	minPrice := product.FloorFor(nil)
+
+	newPrice, reason, ok := decidePrice(strategy, competitorPrices, minPrice, product.MaxPrice)
+
+	// In dry-run only the price dumping would set is stored
+	if plan.dryRun {
+		s.recordProposal(ctx, &domain.PriceProposal{
+			ProductID:          product.ID,
+			CurrentPrice:       product.Price,
+			ProposedPrice:      newPrice,
+			CompetitorMinPrice: minCompetitorPrice,
+			CompetitorCount:    len(competitorPrices),
+			Excluded:           excluded,
+			Floor:              minPrice,
+			Ceiling:            product.MaxPrice,
+			Strategy:           plan.cfg,
+			Reason:             reason,
+		}, product, ok, competitorPrices, plan.guard)
+
+		if err := s.productRepo.UpdatePrice(ctx, product.ID, product.Price, minCompetitorPrice); err != nil {
+			return false, fmt.Errorf("failed to update competitor price: %w", err)
+		}
 
-	// Вычисляем новую цену (на 1 тенге дешевле)
-	newPrice := minCompetitorPrice - PriceDumpMargin
+		return false, nil
+	}
 
-	// Проверяем минимальный порог
-	if product.MinPrice > 0 && newPrice < product.MinPrice {
+	if !ok {
 		logger.Log.Info("Price below minimum threshold, skipping",
 			zap.String("product_id", product.ID),
 			zap.String("product_name", product.Name),
-			zap.Float64("new_price", newPrice),
-			zap.Float64("min_price", product.MinPrice),
+			zap.Float64("min_price", minPrice),
 			zap.Float64("competitor_price", minCompetitorPrice),
 		)
 
-		// Обновляем только информацию о цене конкурента
-		if err := s.productRepo.UpdatePrice(product.ID, product.Price, minCompetitorPrice); err != nil {
-			return fmt.Errorf("failed to update competitor price: %w", err)
+		// Only the competitor price is updated
+		if err := s.productRepo.UpdatePrice(ctx, product.ID, product.Price, minCompetitorPrice); err != nil {
+			return false, fmt.Errorf("failed to update competitor price: %w", err)
 		}
 
-		return nil
+		return false, nil
 	}
 
-	// Проверяем, нужно ли менять цену
+	// Price war guard: cuts are capped, and a price war pauses dumping of the product
+	newPrice, reason, _ = guardPrice(product.Price, newPrice, reason, func(target float64) guardCheck {
+		return s.guards.Check(ctx, product, "", product.Price, target, competitorPrices, plan.guard)
+	})
+
+	// Is there anything to change
 	if product.Price == newPrice {
 		logger.Log.Debug("Price already optimal",
 			zap.String("product_id", product.ID),
 			zap.Float64("current_price", product.Price),
 		)
 
-		// Обновляем время проверки и цену конкурента
-		if err := s.productRepo.UpdatePrice(product.ID, product.Price, minCompetitorPrice); err != nil {
-			return fmt.Errorf("failed to update price check time: %w", err)
+		// Update the check time and the competitor price
+		if err := s.productRepo.UpdatePrice(ctx, product.ID, product.Price, minCompetitorPrice); err != nil {
+			return false, fmt.Errorf("failed to update price check time: %w", err)
 		}
 
-		return nil
+		return false, nil
 	}
 
-	// Обновляем цену на Kaspi
-	if err := client.UpdateProductPrice(product.ExternalID, newPrice); err != nil {
-		return fmt.Errorf("failed to update price on Kaspi: %w", err)
+	// Update the price on the marketplace
+	if err := client.UpdateProductPrice(ctx, product.ExternalID, newPrice); err != nil {
+		return false, fmt.Errorf("failed to update price on marketplace: %w", err)
 	}
 
-	// Обновляем цену в БД
-	if err := s.productRepo.UpdatePrice(product.ID, newPrice, minCompetitorPrice); err != nil {
-		return fmt.Errorf("failed to update price in database: %w", err)
+	// Update the price in the database
+	if err := s.productRepo.UpdatePrice(ctx, product.ID, newPrice, minCompetitorPrice); err != nil {
+		return true, fmt.Errorf("failed to update price in database: %w", err)
 	}
 
+	s.historyService.Record(ctx, &domain.PriceChange{
+		ProductID:          product.ID,
+		UserID:             product.UserID,
+		ConnectionID:       product.ConnectionID,
+		OldPrice:           product.Price,
+		NewPrice:           newPrice,
+		CompetitorMinPrice: minCompetitorPrice,
+		Competitors:        snapshotOffers(competitorPrices),
+		Excluded:           excluded,
+		Strategy:           &plan.cfg,
+		Reason:             reason,
+		Trigger:            domain.PriceTriggerAuto,
+		Actor:              domain.PriceActorSystem,
+	})
+
 	logger.Log.Info("Price updated successfully",
 		zap.String("product_id", product.ID),
 		zap.String("product_name", product.Name),
 		zap.Float64("old_price", product.Price),
 		zap.Float64("new_price", newPrice),
 		zap.Float64("min_competitor_price", minCompetitorPrice),
-		zap.Float64("min_threshold", product.MinPrice),
+		zap.Float64("min_threshold", minPrice),
+		zap.Float64("max_price", product.MaxPrice),
+		zap.String("reason", reason),
 	)
 
-	return nil
+	return true, nil
 }
 
-func (s *PriceDumpingService) getKaspiClient(key *domain.KaspiKey) (*kaspi.Client, error) {
-	apiKey, err := s.encryptor.Decrypt(key.APIKeyEncrypted)
+// processCity dumps the product's price in one city. changed reports whether the city price changed.
+func (s *PriceDumpingService) processCity(ctx context.Context, product *domain.Product, city *domain.CityPrice, plan dumpingPlan, strategy PricingStrategy, client marketplace.MarketplaceClient) (bool, error) {
+	// Competitor prices in this city
+	competitorPrices, err := client.GetCompetitorPrices(ctx, product.ExternalID, city.City)
+	if errors.Is(err, marketplace.ErrNotFound) {
+		logger.Log.Warn("Product not found on marketplace, skipping",
+			zap.String("product_id", product.ID),
+			zap.String("external_id", product.ExternalID),
+			zap.String("city", city.City),
+		)
+		return false, nil
+	}
+	if errors.Is(err, marketplace.ErrNotSupported) {
+		logger.Log.Debug("Marketplace does not expose competitor offers, skipping", zap.String("product_id", product.ID))
+		return false, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to decrypt API key: %w", err)
+		return false, fmt.Errorf("failed to get competitor prices: %w", err)
+	}
+
+	// Keep competitor offers in this city for history
+	s.offerHistory.Record(ctx, product, city.City, city.Price, competitorPrices)
+
+	// Only the competitors being watched
+	competitorPrices, excluded := FilterOffers(competitorPrices, plan.filter)
+
+	// The city's floor and ceiling, falling back to the product's (the floor is never below the margin floor)
+	minPrice := product.FloorFor(city)
+	maxPrice := product.CeilingFor(city)
+
+	if len(competitorPrices) == 0 && maxPrice == 0 {
+		logger.Log.Debug("No competitors found",
+			zap.String("product_id", product.ID),
+			zap.String("city", city.City),
+		)
+		return false, nil
+	}
+
+	minCompetitorPrice := marketplace.MinOfferPrice(competitorPrices)
+
+	newPrice, reason, ok := decidePrice(strategy, competitorPrices, minPrice, maxPrice)
+
+	// In dry-run only the price dumping would set in this city is stored
+	if plan.dryRun {
+		s.recordProposal(ctx, &domain.PriceProposal{
+			ProductID:          product.ID,
+			City:               city.City,
+			CurrentPrice:       city.Price,
+			ProposedPrice:      newPrice,
+			CompetitorMinPrice: minCompetitorPrice,
+			CompetitorCount:    len(competitorPrices),
+			Excluded:           excluded,
+			Floor:              minPrice,
+			Ceiling:            maxPrice,
+			Strategy:           plan.cfg,
+			Reason:             reason,
+		}, product, ok, competitorPrices, plan.guard)
+
+		if err := s.productRepo.UpdateCityPrice(ctx, product.ID, city.City, city.Price, minCompetitorPrice); err != nil {
+			return false, fmt.Errorf("failed to update competitor price: %w", err)
+		}
+		city.CompetitorMinPrice = minCompetitorPrice
+
+		return false, nil
+	}
+
+	if !ok {
+		logger.Log.Info("City price below minimum threshold, skipping",
+			zap.String("product_id", product.ID),
+			zap.String("city", city.City),
+			zap.Float64("min_price", minPrice),
+			zap.Float64("competitor_price", minCompetitorPrice),
+		)
+		newPrice = city.Price
+	}
+
+	// Price war guard: cuts are capped, and a price war pauses dumping of the product
+	newPrice, reason, _ = guardPrice(city.Price, newPrice, reason, func(target float64) guardCheck {
+		return s.guards.Check(ctx, product, city.City, city.Price, target, competitorPrices, plan.guard)
+	})
+
+	changed := city.Price != newPrice
+	if changed {
+		// Update the city price on the marketplace
+		if err := client.UpdateCityPrice(ctx, product.ExternalID, city.City, newPrice); err != nil {
+			return false, fmt.Errorf("failed to update city price on marketplace: %w", err)
+		}
+
+		logger.Log.Info("City price updated successfully",
+			zap.String("product_id", product.ID),
+			zap.String("product_name", product.Name),
+			zap.String("city", city.City),
+			zap.Float64("old_price", city.Price),
+			zap.Float64("new_price", newPrice),
+			zap.Float64("min_competitor_price", minCompetitorPrice),
+			zap.Float64("min_threshold", minPrice),
+			zap.Float64("max_price", maxPrice),
+			zap.String("reason", reason),
+		)
+
+		s.historyService.Record(ctx, &domain.PriceChange{
+			ProductID:          product.ID,
+			UserID:             product.UserID,
+			ConnectionID:       product.ConnectionID,
+			City:               city.City,
+			OldPrice:           city.Price,
+			NewPrice:           newPrice,
+			CompetitorMinPrice: minCompetitorPrice,
+			Competitors:        snapshotOffers(competitorPrices),
+			Excluded:           excluded,
+			Strategy:           &plan.cfg,
+			Reason:             reason,
+			Trigger:            domain.PriceTriggerAuto,
+			Actor:              domain.PriceActorSystem,
+		})
+	}
+
+	// Update the price, competitor price and check time in the database
+	if err := s.productRepo.UpdateCityPrice(ctx, product.ID, city.City, newPrice, minCompetitorPrice); err != nil {
+		return changed, fmt.Errorf("failed to update city price in database: %w", err)
 	}
 
-	return kaspi.NewClient(apiKey, key.MerchantID), nil
+	city.Price = newPrice
+	city.CompetitorMinPrice = minCompetitorPrice
+
+	return changed, nil
+}
+
+// Reasons a new price was chosen
+const (
+	PriceReasonStrategy      = "strategy"       // Price set by the strategy
+	PriceReasonAboveFloor    = "above_floor"    // Under the next competitor above the floor
+	PriceReasonCeiling       = "ceiling"        // Capped by the ceiling
+	PriceReasonNoCompetitors = "no_competitors" // No competitors, so the ceiling
+	PriceReasonHold          = "hold"           // Price kept: below the floor with no ceiling (dry-run only)
+	PriceReasonRateLimited   = "rate_limited"   // Cut capped by the price war guard
+	PriceReasonGuardPaused   = "guard_paused"   // Price kept: the price war guard would pause dumping (dry-run only)
+)
+
+// decidePrice applies the strategy to competitor offers between the floor and the ceiling
+// (0 for none). When the strategy would go below the floor, competitors under the floor
+// are not chased: the price goes under the next competitor above the floor, or up to the
+// ceiling if there is none. That way the price climbs back after a price war.
+// Without a floor, 1 is the floor: a target of 0 or less would be rejected by the marketplace.
+// ok = false means the price should not change.
+func decidePrice(strategy PricingStrategy, offers []marketplace.CompetitorOffer, floor, ceiling float64) (float64, string, bool) {
+	floor = math.Max(floor, 1)
+
+	price, ok := strategy.TargetPrice(offers)
+	if !ok {
+		if ceiling > 0 {
+			return ceiling, PriceReasonNoCompetitors, true
+		}
+		return 0, "", false
+	}
+
+	reason := PriceReasonStrategy
+	if price < floor {
+		above := make([]marketplace.CompetitorOffer, 0, len(offers))
+		for _, o := range offers {
+			if o.Price > floor {
+				above = append(above, o)
+			}
+		}
+
+		price, ok = strategy.TargetPrice(above)
+		switch {
+		case ok:
+			price = math.Max(price, floor)
+			reason = PriceReasonAboveFloor
+		case ceiling > 0:
+			price = ceiling
+			reason = PriceReasonCeiling
+		default:
+			return 0, "", false
+		}
+	}
+
+	if ceiling > 0 && price > ceiling {
+		price = ceiling
+		reason = PriceReasonCeiling
+	}
+
+	return price, reason, true
+}
+
+// guardPrice applies the price war guard to the price decidePrice chose. check runs the
+// guard on a cut from current to target; raises pass unchecked. A tripped guard keeps the
+// current price and is returned, a capped cut stops at the guard's limit.
+func guardPrice(current, price float64, reason string, check func(target float64) guardCheck) (float64, string, *guardTrip) {
+	if price >= current {
+		return price, reason, nil
+	}
+
+	result := check(price)
+	switch {
+	case result.trip != nil:
+		return current, PriceReasonGuardPaused, result.trip
+	case result.capped:
+		return result.price, PriceReasonRateLimited, nil
+	}
+	return price, reason, nil
+}
+
+// recordProposal stores the price dumping would set in dry-run, after the price war guard
+// the way it would apply to the cut, but without pausing the product or alerting.
+// ok = false means the price would stay as it is. Errors are only logged.
+func (s *PriceDumpingService) recordProposal(ctx context.Context, proposal *domain.PriceProposal, product *domain.Product, ok bool, offers []marketplace.CompetitorOffer, guard domain.PriceGuard) {
+	proposal.UserID = product.UserID
+	proposal.ConnectionID = product.ConnectionID
+	proposal.ProductName = product.Name
+	proposal.SKU = product.SKU
+	proposal.ProposedAt = time.Now()
+
+	switch {
+	case !ok:
+		proposal.ProposedPrice = proposal.CurrentPrice
+		proposal.Reason = PriceReasonHold
+	default:
+		var trip *guardTrip
+		proposal.ProposedPrice, proposal.Reason, trip = guardPrice(proposal.CurrentPrice, proposal.ProposedPrice, proposal.Reason, func(target float64) guardCheck {
+			return s.guards.Evaluate(ctx, product, proposal.City, proposal.CurrentPrice, target, offers, guard)
+		})
+		if trip != nil {
+			proposal.Guard = trip.guard
+		}
+	}
+
+	if err := s.proposalRepo.Save(ctx, proposal); err != nil {
+		logger.Log.Error("Failed to record price proposal",
+			zap.String("product_id", proposal.ProductID),
+			zap.String("city", proposal.City),
+			zap.Error(err),
+		)
+		return
+	}
+
+	logger.Log.Debug("Dry-run price proposed",
+		zap.String("product_id", proposal.ProductID),
+		zap.String("city", proposal.City),
+		zap.Float64("current_price", proposal.CurrentPrice),
+		zap.Float64("proposed_price", proposal.ProposedPrice),
+		zap.String("reason", proposal.Reason),
+	)
 }
 
-// EnableProductDumping включает автодемпинг для конкретного товара
-func (s *PriceDumpingService) EnableProductDumping(productID string, minPrice float64) error {
-	product, err := s.productRepo.GetByID(productID)
+// EnableProductDumping turns on price dumping for a product
+func (s *PriceDumpingService) EnableProductDumping(ctx context.Context, productID string, minPrice float64) error {
+	product, err := s.productRepo.GetByID(ctx, productID)
 	if err != nil {
 		return fmt.Errorf("failed to get product: %w", err)
 	}
@@ -216,7 +639,7 @@ func (s *PriceDumpingService) EnableProductDumping(productID string, minPrice fl
 	product.AutoDumpingEnabled = true
 	product.MinPrice = minPrice
 
-	if err := s.productRepo.Update(product); err != nil {
+	if err := s.productRepo.Update(ctx, product); err != nil {
 		return fmt.Errorf("failed to update product: %w", err)
 	}
 
@@ -229,9 +652,9 @@ func (s *PriceDumpingService) EnableProductDumping(productID string, minPrice fl
 	return nil
 }
 
-// DisableProductDumping выключает автодемпинг для конкретного товара
-func (s *PriceDumpingService) DisableProductDumping(productID string) error {
-	product, err := s.productRepo.GetByID(productID)
+// DisableProductDumping turns off price dumping for a product
+func (s *PriceDumpingService) DisableProductDumping(ctx context.Context, productID string) error {
+	product, err := s.productRepo.GetByID(ctx, productID)
 	if err != nil {
 		return fmt.Errorf("failed to get product: %w", err)
 	}
@@ -242,7 +665,7 @@ func (s *PriceDumpingService) DisableProductDumping(productID string) error {
 
 	product.AutoDumpingEnabled = false
 
-	if err := s.productRepo.Update(product); err != nil {
+	if err := s.productRepo.Update(ctx, product); err != nil {
 		return fmt.Errorf("failed to update product: %w", err)
 	}
 
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+func TestDecidePrice(t *testing.T) {
+	undercut := undercutAmount{amount: 1}
+
+	tests := []struct {
+		name       string
+		offers     []marketplace.CompetitorOffer
+		floor      float64
+		ceiling    float64
+		want       float64
+		wantReason string
+		wantOK     bool
+	}{
+		{"between floor and ceiling", offersAt(1000, 1200), 900, 1500, 999, PriceReasonStrategy, true},
+		{"no floor", offersAt(1000), 0, 0, 999, PriceReasonStrategy, true},
+		{"under the next competitor above the floor", offersAt(800, 1200), 900, 1500, 1199, PriceReasonAboveFloor, true},
+		{"never under the floor", offersAt(800, 900.5), 900, 0, 900, PriceReasonAboveFloor, true},
+		{"up to the ceiling when all are under the floor", offersAt(700, 800), 900, 1500, 1500, PriceReasonCeiling, true},
+		{"hold when all are under the floor without a ceiling", offersAt(700, 800), 900, 0, 0, "", false},
+		{"capped by the ceiling", offersAt(2000), 900, 1500, 1500, PriceReasonCeiling, true},
+		{"ceiling without competitors", nil, 900, 1500, 1500, PriceReasonNoCompetitors, true},
+		{"hold without competitors or a ceiling", nil, 900, 0, 0, "", false},
+		{"never zero without a floor", offersAt(1), 0, 0, 0, "", false},
+		{"under the next competitor above 1 without a floor", offersAt(1, 50), 0, 0, 49, PriceReasonAboveFloor, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason, ok := decidePrice(undercut, tt.offers, tt.floor, tt.ceiling)
+			if got != tt.want || reason != tt.wantReason || ok != tt.wantOK {
+				t.Errorf("decidePrice = %v, %q, %v, want %v, %q, %v", got, reason, ok, tt.want, tt.wantReason, tt.wantOK)
+			}
+		})
+	}
+}
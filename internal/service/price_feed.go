@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace/kaspi"
+	"github.com/yourusername/seller-assistant/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ErrFeedNotSupported is returned for connections whose marketplace has no XML price feed
+var ErrFeedNotSupported = errors.New("price feed is only available for Kaspi connections")
+
+// PriceFeedService renders Kaspi XML price lists from stored products
+type PriceFeedService struct {
+	productRepo domain.ProductRepository
+	feedRepo    domain.PriceFeedRepository
+}
+
+func NewPriceFeedService(productRepo domain.ProductRepository, feedRepo domain.PriceFeedRepository) *PriceFeedService {
+	return &PriceFeedService{
+		productRepo: productRepo,
+		feedRepo:    feedRepo,
+	}
+}
+
+// Regenerate renders the connection's current products into its feed and stores it.
+// Products that would break the schema are left out and counted in SkippedCount.
+func (s *PriceFeedService) Regenerate(ctx context.Context, conn *domain.MarketplaceConnection) (*domain.PriceFeed, error) {
+	if conn.Marketplace != domain.MarketplaceKaspi {
+		return nil, ErrFeedNotSupported
+	}
+
+	feed, err := s.getOrCreate(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := s.productRepo.GetByConnectionID(ctx, conn.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	now := time.Now()
+	catalog := kaspi.NewFeedCatalog(conn.Name, conn.MerchantID, now)
+	skipped := 0
+
+	for _, p := range products {
+		sku := p.SKU
+		if sku == "" {
+			sku = p.ExternalID
+		}
+
+		// Per-point stock, when synced, keeps a point that is sold out from showing as available
+		var offer kaspi.FeedOffer
+		if len(p.Stocks) > 0 {
+			stocks := make(map[string]int, len(p.Stocks))
+			points := conn.PickupPoints
+			for _, s := range p.Stocks {
+				stocks[s.WarehouseID] = s.Quantity
+				if len(conn.PickupPoints) == 0 {
+					points = append(points, s.WarehouseID)
+				}
+			}
+			offer = kaspi.NewFeedOfferByPoint(sku, p.Name, p.Price, stocks, points)
+		} else {
+			offer = kaspi.NewFeedOffer(sku, p.Name, p.Price, p.CurrentStock, conn.PickupPoints)
+		}
+
+		// Products repriced per city carry their city prices instead of the single price
+		if len(p.CityPrices) > 0 {
+			prices := make(map[string]float64, len(p.CityPrices))
+			for _, cp := range p.CityPrices {
+				prices[cp.City] = cp.Price
+			}
+			offer.SetCityPrices(prices)
+		}
+		if err := offer.Validate(); err != nil {
+			logger.Log.Warn("Product left out of price feed",
+				zap.String("connection_id", conn.ID),
+				zap.String("product_id", p.ID),
+				zap.Error(err),
+			)
+			skipped++
+			continue
+		}
+
+		catalog.Offers = append(catalog.Offers, offer)
+	}
+
+	content, err := catalog.Render()
+	if err != nil {
+		return nil, fmt.Errorf("failed to render price feed: %w", err)
+	}
+
+	feed.Content = string(content)
+	feed.OfferCount = len(catalog.Offers)
+	feed.SkippedCount = skipped
+	feed.GeneratedAt = now
+
+	if err := s.feedRepo.Save(ctx, feed); err != nil {
+		return nil, err
+	}
+
+	logger.Log.Info("Price feed regenerated",
+		zap.String("connection_id", conn.ID),
+		zap.Int("offers", feed.OfferCount),
+		zap.Int("skipped", skipped),
+	)
+
+	return feed, nil
+}
+
+// RegenerateQuietly regenerates the feed of a Kaspi connection and only logs failures.
+// Used after syncs and price changes, where a feed error must not fail the caller.
+func (s *PriceFeedService) RegenerateQuietly(ctx context.Context, conn *domain.MarketplaceConnection) {
+	if conn.Marketplace != domain.MarketplaceKaspi {
+		return
+	}
+
+	if _, err := s.Regenerate(ctx, conn); err != nil {
+		logger.Log.Error("Failed to regenerate price feed",
+			zap.String("connection_id", conn.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+// RotateToken replaces the feed's secret token, invalidating the old feed URL
+func (s *PriceFeedService) RotateToken(ctx context.Context, conn *domain.MarketplaceConnection) (*domain.PriceFeed, error) {
+	if conn.Marketplace != domain.MarketplaceKaspi {
+		return nil, ErrFeedNotSupported
+	}
+
+	feed, err := s.getOrCreate(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := newFeedToken()
+	if err != nil {
+		return nil, err
+	}
+	feed.Token = token
+
+	if err := s.feedRepo.UpdateToken(ctx, feed); err != nil {
+		return nil, err
+	}
+
+	return feed, nil
+}
+
+// getOrCreate returns the stored feed, or a new empty one with a fresh token
+func (s *PriceFeedService) getOrCreate(ctx context.Context, conn *domain.MarketplaceConnection) (*domain.PriceFeed, error) {
+	feed, err := s.feedRepo.GetByConnectionID(ctx, conn.ID)
+	if err != nil {
+		return nil, err
+	}
+	if feed != nil {
+		return feed, nil
+	}
+
+	token, err := newFeedToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PriceFeed{
+		UserID:       conn.UserID,
+		ConnectionID: conn.ID,
+		Token:        token,
+	}, nil
+}
+
+func newFeedToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
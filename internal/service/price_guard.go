@@ -0,0 +1,374 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidPriceGuard is returned for out-of-range limits and pausing guards without a cooldown
+var ErrInvalidPriceGuard = errors.New("invalid price guard")
+
+// MaxGuardCooldownMinutes caps how long a guard may pause a product (a week)
+const MaxGuardCooldownMinutes = 7 * 24 * 60
+
+// DefaultPriceGuard protects the products of users who have not set a guard of their own.
+// Two bots undercutting each other by 1 every five minutes pause within half an hour.
+var DefaultPriceGuard = domain.PriceGuard{
+	MaxDecreasePercentPerCycle: 5,
+	MaxDecreasePercentPerDay:   20,
+	MaxConsecutiveUndercuts:    12,
+	PingPongRounds:             4,
+	CooldownMinutes:            60,
+}
+
+// priceGuardWindow is the period the daily limit and the guards' price changes are taken from
+const priceGuardWindow = 24 * time.Hour
+
+// PriceGuardService keeps price dumping out of price wars: it limits cuts, pauses repricing
+// of products that keep undercutting and alerts their owners
+type PriceGuardService struct {
+	guardRepo      domain.PriceGuardRepository
+	alertRepo      domain.PriceGuardAlertRepository
+	productRepo    domain.ProductRepository
+	historyService *PriceHistoryService
+}
+
+func NewPriceGuardService(
+	guardRepo domain.PriceGuardRepository,
+	alertRepo domain.PriceGuardAlertRepository,
+	productRepo domain.ProductRepository,
+	historyService *PriceHistoryService,
+) *PriceGuardService {
+	return &PriceGuardService{
+		guardRepo:      guardRepo,
+		alertRepo:      alertRepo,
+		productRepo:    productRepo,
+		historyService: historyService,
+	}
+}
+
+// UserGuard is the price guard of a user's products and whether it is the default
+type UserGuard struct {
+	Guard   domain.PriceGuard `json:"guard"`
+	Default bool              `json:"default"`
+}
+
+// GetGuard returns the price guard of the user's products
+func (s *PriceGuardService) GetGuard(ctx context.Context, userID string) (*UserGuard, error) {
+	existing, err := s.guardRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return &UserGuard{Guard: DefaultPriceGuard, Default: true}, nil
+	}
+	return &UserGuard{Guard: existing.Guard}, nil
+}
+
+// SetGuard sets the price guard of all the user's products; zero limits are off
+func (s *PriceGuardService) SetGuard(ctx context.Context, userID string, guard domain.PriceGuard) (*UserGuard, error) {
+	if err := validatePriceGuard(guard); err != nil {
+		return nil, err
+	}
+
+	if err := s.guardRepo.Save(ctx, &domain.UserPriceGuard{UserID: userID, Guard: guard}); err != nil {
+		return nil, err
+	}
+
+	logger.Log.Info("Price guard updated",
+		zap.String("user_id", userID),
+		zap.Float64("max_decrease_percent_per_cycle", guard.MaxDecreasePercentPerCycle),
+		zap.Float64("max_decrease_percent_per_day", guard.MaxDecreasePercentPerDay),
+		zap.Int("max_consecutive_undercuts", guard.MaxConsecutiveUndercuts),
+		zap.Int("ping_pong_rounds", guard.PingPongRounds),
+	)
+
+	return &UserGuard{Guard: guard}, nil
+}
+
+// ResetGuard returns the user's products to DefaultPriceGuard
+func (s *PriceGuardService) ResetGuard(ctx context.Context, userID string) (*UserGuard, error) {
+	if err := s.guardRepo.Delete(ctx, userID); err != nil {
+		return nil, err
+	}
+	return &UserGuard{Guard: DefaultPriceGuard, Default: true}, nil
+}
+
+// GetAlerts returns the alerts of guards pausing the user's products over the last days, newest first
+func (s *PriceGuardService) GetAlerts(ctx context.Context, userID string, days int) ([]domain.PriceGuardAlert, error) {
+	return s.alertRepo.GetByUserID(ctx, userID, time.Now().AddDate(0, 0, -days))
+}
+
+// Resume lets dumping reprice a paused product again. Undercuts before now no longer count
+// towards the guards.
+func (s *PriceGuardService) Resume(ctx context.Context, userID, productID string) (*domain.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil || product == nil || product.UserID != userID {
+		return nil, ErrProductNotFound
+	}
+
+	if err := s.productRepo.SetDumpingPause(ctx, product.ID, time.Now(), ""); err != nil {
+		return nil, err
+	}
+
+	logger.Log.Info("Price dumping resumed",
+		zap.String("user_id", userID),
+		zap.String("product_id", product.ID),
+		zap.String("pause_reason", product.DumpingPauseReason),
+	)
+
+	return s.productRepo.GetByID(ctx, product.ID)
+}
+
+// guardFor returns the price guard dumping applies to the user's products
+func (s *PriceGuardService) guardFor(ctx context.Context, userID string) (domain.PriceGuard, error) {
+	existing, err := s.guardRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return domain.PriceGuard{}, fmt.Errorf("failed to get price guard: %w", err)
+	}
+	if existing == nil {
+		return DefaultPriceGuard, nil
+	}
+	return existing.Guard, nil
+}
+
+// guardCheck is what a price guard makes of a cut
+type guardCheck struct {
+	price  float64 // Price to set instead of the target
+	capped bool    // The cut was limited
+	trip   *guardTrip
+}
+
+// guardTrip is a guard pausing a product
+type guardTrip struct {
+	guard   string
+	until   time.Time
+	message string
+	rival   *domain.CompetitorSnapshot // The competitor of a ping-pong
+}
+
+// Check applies the guard to dumping cutting a product's price in a city ("" for the base
+// price) from current to target. When a guard trips it pauses the product and alerts the
+// user, and the price must not change.
+func (s *PriceGuardService) Check(ctx context.Context, product *domain.Product, city string, current, target float64, offers []marketplace.CompetitorOffer, guard domain.PriceGuard) guardCheck {
+	check := s.Evaluate(ctx, product, city, current, target, offers, guard)
+	if check.trip != nil {
+		s.pause(ctx, product, city, current, target, check.trip)
+	}
+
+	return check
+}
+
+// Evaluate is Check without side effects: a tripped guard neither pauses the product nor
+// alerts the user. Dry-run uses it to show what the guard would do. Failing to read the
+// price history only leaves the cut limited by MaxDecreasePercentPerCycle.
+func (s *PriceGuardService) Evaluate(ctx context.Context, product *domain.Product, city string, current, target float64, offers []marketplace.CompetitorOffer, guard domain.PriceGuard) guardCheck {
+	now := time.Now()
+
+	changes, err := s.historyService.recent(ctx, product.ID, city, now.Add(-priceGuardWindow))
+	if err != nil {
+		logger.Log.Warn("Failed to get price changes for the price guard",
+			zap.String("product_id", product.ID),
+			zap.String("city", city),
+			zap.Error(err),
+		)
+		guard = domain.PriceGuard{MaxDecreasePercentPerCycle: guard.MaxDecreasePercentPerCycle}
+	}
+
+	return checkPriceGuard(guard, current, target, offers, changes, product.DumpingPausedUntil, now)
+}
+
+// pause stops dumping of the product until the trip ends and alerts the user. Failures are
+// only logged; the price is left as it is either way.
+func (s *PriceGuardService) pause(ctx context.Context, product *domain.Product, city string, current, target float64, trip *guardTrip) {
+	if err := s.productRepo.SetDumpingPause(ctx, product.ID, trip.until, trip.guard); err != nil {
+		logger.Log.Error("Failed to pause price dumping",
+			zap.String("product_id", product.ID),
+			zap.String("guard", trip.guard),
+			zap.Error(err),
+		)
+	}
+	product.DumpingPausedUntil = trip.until
+	product.DumpingPauseReason = trip.guard
+
+	alert := &domain.PriceGuardAlert{
+		ProductID:    product.ID,
+		UserID:       product.UserID,
+		ConnectionID: product.ConnectionID,
+		ProductName:  product.Name,
+		SKU:          product.SKU,
+		City:         city,
+		Guard:        trip.guard,
+		Message:      trip.message,
+		Price:        current,
+		TargetPrice:  target,
+		PausedUntil:  trip.until,
+	}
+	if trip.rival != nil {
+		alert.SellerID = trip.rival.SellerID
+		alert.SellerName = trip.rival.SellerName
+	}
+
+	if err := s.alertRepo.Create(ctx, alert); err != nil {
+		logger.Log.Error("Failed to create price guard alert",
+			zap.String("product_id", product.ID),
+			zap.String("guard", trip.guard),
+			zap.Error(err),
+		)
+	}
+
+	logger.Log.Warn("Price guard paused price dumping",
+		zap.String("user_id", product.UserID),
+		zap.String("product_id", product.ID),
+		zap.String("product_name", product.Name),
+		zap.String("city", city),
+		zap.String("guard", trip.guard),
+		zap.String("message", trip.message),
+		zap.Time("paused_until", trip.until),
+	)
+}
+
+// checkPriceGuard decides what the guard makes of cutting the price from current to target.
+// changes are the price changes of the last priceGuardWindow, newest first; undercuts made
+// before resetAt (the end of the last pause) do not count towards the pausing guards.
+func checkPriceGuard(guard domain.PriceGuard, current, target float64, offers []marketplace.CompetitorOffer, changes []domain.PriceChange, resetAt, now time.Time) guardCheck {
+	check := guardCheck{price: target}
+	if target >= current {
+		return check
+	}
+
+	// Undercuts made since the last pause, newest first
+	var undercuts []domain.PriceChange
+	for _, c := range changes {
+		if !isUndercut(c) || !c.CreatedAt.After(resetAt) {
+			break
+		}
+		undercuts = append(undercuts, c)
+	}
+
+	if guard.PingPongRounds > 0 {
+		if rival, rounds := pingPongRounds(offers, undercuts); rounds >= guard.PingPongRounds {
+			check.trip = &guardTrip{
+				guard:   domain.GuardPingPong,
+				until:   now.Add(time.Duration(guard.CooldownMinutes) * time.Minute),
+				message: fmt.Sprintf("%s undercut the price back %d times in a row", sellerLabel(rival), rounds),
+				rival:   rival,
+			}
+			return check
+		}
+	}
+
+	if guard.MaxConsecutiveUndercuts > 0 && len(undercuts) >= guard.MaxConsecutiveUndercuts {
+		check.trip = &guardTrip{
+			guard:   domain.GuardConsecutiveUndercuts,
+			until:   now.Add(time.Duration(guard.CooldownMinutes) * time.Minute),
+			message: fmt.Sprintf("the price was cut %d times in a row", len(undercuts)),
+		}
+		return check
+	}
+
+	if guard.MaxDecreasePercentPerDay > 0 && len(changes) > 0 {
+		// The price a day ago is the old price of the oldest change since
+		oldest := changes[len(changes)-1]
+		limit := math.Ceil(oldest.OldPrice * (1 - guard.MaxDecreasePercentPerDay/100))
+		if limit >= current {
+			check.trip = &guardTrip{
+				guard:   domain.GuardDailyDecrease,
+				until:   oldest.CreatedAt.Add(priceGuardWindow), // The limit frees up as the oldest change leaves the window
+				message: fmt.Sprintf("the price fell %g%% within 24 hours", guard.MaxDecreasePercentPerDay),
+			}
+			return check
+		}
+		if check.price < limit {
+			check.price = limit
+			check.capped = true
+		}
+	}
+
+	if guard.MaxDecreasePercentPerCycle > 0 {
+		if limit := math.Ceil(current * (1 - guard.MaxDecreasePercentPerCycle/100)); check.price < limit {
+			check.price = limit
+			check.capped = true
+		}
+	}
+
+	return check
+}
+
+// pingPongRounds counts the rounds, newest first, of the same competitor undercutting the
+// price that dumping had just set under it. The current round is that competitor being the
+// cheapest of offers, under the price of the last undercut.
+func pingPongRounds(offers []marketplace.CompetitorOffer, undercuts []domain.PriceChange) (*domain.CompetitorSnapshot, int) {
+	if len(offers) == 0 {
+		return nil, 0
+	}
+
+	cheapest := offers[0]
+	for _, o := range offers[1:] {
+		if o.Price < cheapest.Price {
+			cheapest = o
+		}
+	}
+	rival := &domain.CompetitorSnapshot{SellerID: cheapest.SellerID, SellerName: cheapest.SellerName, Price: cheapest.Price}
+
+	rounds := 0
+	rivalPrice := rival.Price
+	for _, c := range undercuts {
+		followed := cheapestSnapshot(c.Competitors)
+		// The undercut followed the rival, and the rival then went under it
+		if followed == nil || followed.SellerID != rival.SellerID || rivalPrice >= c.NewPrice {
+			break
+		}
+		rounds++
+		rivalPrice = followed.Price
+	}
+
+	return rival, rounds
+}
+
+// isUndercut reports whether a price change was dumping cutting the price
+func isUndercut(c domain.PriceChange) bool {
+	return c.Trigger == domain.PriceTriggerAuto && c.NewPrice < c.OldPrice
+}
+
+func cheapestSnapshot(offers []domain.CompetitorSnapshot) *domain.CompetitorSnapshot {
+	var cheapest *domain.CompetitorSnapshot
+	for i := range offers {
+		if cheapest == nil || offers[i].Price < cheapest.Price {
+			cheapest = &offers[i]
+		}
+	}
+	return cheapest
+}
+
+func sellerLabel(seller *domain.CompetitorSnapshot) string {
+	if seller.SellerName != "" {
+		return seller.SellerName
+	}
+	return seller.SellerID
+}
+
+func validatePriceGuard(guard domain.PriceGuard) error {
+	if guard.MaxDecreasePercentPerCycle < 0 || guard.MaxDecreasePercentPerCycle >= 100 ||
+		guard.MaxDecreasePercentPerDay < 0 || guard.MaxDecreasePercentPerDay >= 100 {
+		return fmt.Errorf("%w: decrease percents must be between 0 and 100", ErrInvalidPriceGuard)
+	}
+	if guard.MaxConsecutiveUndercuts < 0 || guard.PingPongRounds < 0 {
+		return fmt.Errorf("%w: max_consecutive_undercuts and ping_pong_rounds must not be negative", ErrInvalidPriceGuard)
+	}
+	if guard.CooldownMinutes < 0 || guard.CooldownMinutes > MaxGuardCooldownMinutes {
+		return fmt.Errorf("%w: cooldown_minutes must be between 0 and %d", ErrInvalidPriceGuard, MaxGuardCooldownMinutes)
+	}
+	if (guard.MaxConsecutiveUndercuts > 0 || guard.PingPongRounds > 0) && guard.CooldownMinutes == 0 {
+		return fmt.Errorf("%w: cooldown_minutes is required to pause after undercuts or a ping-pong", ErrInvalidPriceGuard)
+	}
+	return nil
+}
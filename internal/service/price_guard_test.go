@@ -0,0 +1,185 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+// undercutsOf returns n undercuts by 1 that brought the product's price down to 1000,
+// five minutes apart before now, newest first
+func undercutsOf(product *domain.Product, n int, now time.Time) []domain.PriceChange {
+	changes := make([]domain.PriceChange, n)
+	for i := range changes {
+		changes[i] = domain.PriceChange{
+			ProductID: product.ID,
+			OldPrice:  float64(1001 + i),
+			NewPrice:  float64(1000 + i),
+			Trigger:   domain.PriceTriggerAuto,
+			CreatedAt: now.Add(-time.Duration(i+1) * 5 * time.Minute),
+		}
+	}
+	return changes
+}
+
+// pingPong returns n rounds of undercutting the rival, who undercut back each time,
+// bringing the price down to 995, newest first
+func pingPong(rival string, n int, now time.Time) []domain.PriceChange {
+	changes := make([]domain.PriceChange, n)
+	for i := range changes {
+		price := float64(995 + 2*i)
+		changes[i] = domain.PriceChange{
+			OldPrice:    price + 2,
+			NewPrice:    price,
+			Competitors: []domain.CompetitorSnapshot{{SellerID: rival, Price: price + 1}, {SellerID: "calm", Price: 1500}},
+			Trigger:     domain.PriceTriggerAuto,
+			CreatedAt:   now.Add(-time.Duration(i+1) * 5 * time.Minute),
+		}
+	}
+	return changes
+}
+
+func TestCheckPriceGuard(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	product := &domain.Product{ID: "p1"}
+	cooldown := now.Add(time.Duration(DefaultPriceGuard.CooldownMinutes) * time.Minute)
+
+	manualBreak := undercutsOf(product, 15, now)
+	manualBreak[5].Trigger = domain.PriceTriggerManual
+
+	dayAgo := []domain.PriceChange{{OldPrice: 1250, NewPrice: 1000, Trigger: domain.PriceTriggerManual, CreatedAt: now.Add(-2 * time.Hour)}}
+	dayAgoLower := []domain.PriceChange{{OldPrice: 1200, NewPrice: 1000, Trigger: domain.PriceTriggerManual, CreatedAt: now.Add(-2 * time.Hour)}}
+
+	tests := []struct {
+		name      string
+		guard     domain.PriceGuard
+		current   float64
+		target    float64
+		offers    []marketplace.CompetitorOffer
+		changes   []domain.PriceChange
+		resetAt   time.Time
+		wantPrice float64
+		capped    bool
+		wantTrip  string
+		wantUntil time.Time
+	}{
+		{name: "raise", guard: DefaultPriceGuard, current: 1000, target: 1100, wantPrice: 1100},
+		{name: "cut within the cycle limit", guard: DefaultPriceGuard, current: 1000, target: 980, wantPrice: 980},
+		{name: "cut capped per cycle", guard: DefaultPriceGuard, current: 1000, target: 900, wantPrice: 950, capped: true},
+		{name: "guard off", current: 1000, target: 900, changes: undercutsOf(product, 20, now), wantPrice: 900},
+		{
+			name: "consecutive undercuts pause", guard: DefaultPriceGuard, current: 1000, target: 999,
+			changes: undercutsOf(product, 12, now), wantTrip: domain.GuardConsecutiveUndercuts, wantUntil: cooldown,
+		},
+		{name: "one undercut short", guard: DefaultPriceGuard, current: 1000, target: 999, changes: undercutsOf(product, 11, now), wantPrice: 999},
+		{
+			name: "undercuts before the last pause do not count", guard: DefaultPriceGuard, current: 1000, target: 999,
+			changes: undercutsOf(product, 12, now), resetAt: now.Add(-30 * time.Minute), wantPrice: 999,
+		},
+		{name: "a manual change ends the run", guard: DefaultPriceGuard, current: 1000, target: 999, changes: manualBreak, wantPrice: 999},
+		{
+			name: "daily decrease reached", guard: DefaultPriceGuard, current: 1000, target: 999,
+			changes: dayAgo, wantTrip: domain.GuardDailyDecrease, wantUntil: now.Add(22 * time.Hour),
+		},
+		{name: "cut capped per day", guard: DefaultPriceGuard, current: 1000, target: 900, changes: dayAgoLower, wantPrice: 960, capped: true},
+		{
+			name: "ping-pong pauses", guard: DefaultPriceGuard, current: 995, target: 989,
+			offers:  []marketplace.CompetitorOffer{{SellerID: "rival", Price: 990}, {SellerID: "calm", Price: 1500}},
+			changes: pingPong("rival", 4, now), wantTrip: domain.GuardPingPong, wantUntil: cooldown,
+		},
+		{
+			name: "ping-pong one round short", guard: DefaultPriceGuard, current: 995, target: 989,
+			offers:  []marketplace.CompetitorOffer{{SellerID: "rival", Price: 990}, {SellerID: "calm", Price: 1500}},
+			changes: pingPong("rival", 3, now), wantPrice: 989,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := checkPriceGuard(tt.guard, tt.current, tt.target, tt.offers, tt.changes, tt.resetAt, now)
+
+			if tt.wantTrip != "" {
+				if check.trip == nil {
+					t.Fatalf("check = %+v, want %s to trip", check, tt.wantTrip)
+				}
+				if check.trip.guard != tt.wantTrip || !check.trip.until.Equal(tt.wantUntil) {
+					t.Errorf("trip = %s until %v, want %s until %v", check.trip.guard, check.trip.until, tt.wantTrip, tt.wantUntil)
+				}
+				return
+			}
+
+			if check.trip != nil {
+				t.Fatalf("%s tripped: %s", check.trip.guard, check.trip.message)
+			}
+			if check.price != tt.wantPrice || check.capped != tt.capped {
+				t.Errorf("check = %v capped %v, want %v capped %v", check.price, check.capped, tt.wantPrice, tt.capped)
+			}
+		})
+	}
+}
+
+func TestPingPongRounds(t *testing.T) {
+	now := time.Now()
+	rivalFirst := []marketplace.CompetitorOffer{{SellerID: "rival", SellerName: "Rival", Price: 990}, {SellerID: "calm", Price: 1500}}
+
+	tests := []struct {
+		name      string
+		offers    []marketplace.CompetitorOffer
+		undercuts []domain.PriceChange
+		wantRival string
+		want      int
+	}{
+		{"no offers", nil, pingPong("rival", 3, now), "", 0},
+		{"no undercuts", rivalFirst, nil, "rival", 0},
+		{"rounds with the rival", rivalFirst, pingPong("rival", 3, now), "rival", 3},
+		{"another seller is the cheapest", []marketplace.CompetitorOffer{{SellerID: "new", Price: 980}, {SellerID: "rival", Price: 990}}, pingPong("rival", 3, now), "new", 0},
+		{"rival has not undercut back yet", []marketplace.CompetitorOffer{{SellerID: "rival", Price: 996}}, pingPong("rival", 3, now), "rival", 0},
+		{"rounds followed another seller before", rivalFirst, append(pingPong("rival", 2, now), pingPong("other", 2, now)...), "rival", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rival, rounds := pingPongRounds(tt.offers, tt.undercuts)
+
+			var rivalID string
+			if rival != nil {
+				rivalID = rival.SellerID
+			}
+			if rivalID != tt.wantRival || rounds != tt.want {
+				t.Errorf("pingPongRounds = %q, %d, want %q, %d", rivalID, rounds, tt.wantRival, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePriceGuard(t *testing.T) {
+	tests := []struct {
+		name  string
+		guard domain.PriceGuard
+		ok    bool
+	}{
+		{"default", DefaultPriceGuard, true},
+		{"all off", domain.PriceGuard{}, true},
+		{"limits only", domain.PriceGuard{MaxDecreasePercentPerCycle: 3, MaxDecreasePercentPerDay: 10}, true},
+		{"cycle percent of 100", domain.PriceGuard{MaxDecreasePercentPerCycle: 100}, false},
+		{"negative day percent", domain.PriceGuard{MaxDecreasePercentPerDay: -1}, false},
+		{"negative undercuts", domain.PriceGuard{MaxConsecutiveUndercuts: -1, CooldownMinutes: 60}, false},
+		{"pause without a cooldown", domain.PriceGuard{PingPongRounds: 3}, false},
+		{"cooldown over a week", domain.PriceGuard{MaxConsecutiveUndercuts: 5, CooldownMinutes: MaxGuardCooldownMinutes + 1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePriceGuard(tt.guard)
+			if tt.ok && err != nil {
+				t.Errorf("validatePriceGuard: %v", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrInvalidPriceGuard) {
+				t.Errorf("validatePriceGuard err = %v, want ErrInvalidPriceGuard", err)
+			}
+		})
+	}
+}
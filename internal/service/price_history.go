@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// PriceHistoryService keeps the audit trail of price changes
+type PriceHistoryService struct {
+	priceChangeRepo domain.PriceChangeRepository
+	productRepo     domain.ProductRepository
+	retentionDays   int
+}
+
+func NewPriceHistoryService(
+	priceChangeRepo domain.PriceChangeRepository,
+	productRepo domain.ProductRepository,
+	retentionDays int,
+) *PriceHistoryService {
+	return &PriceHistoryService{
+		priceChangeRepo: priceChangeRepo,
+		productRepo:     productRepo,
+		retentionDays:   retentionDays,
+	}
+}
+
+// Record stores a price change. Failures are only logged: the price has already changed
+// on the marketplace and must not be reported as failed because of the audit trail.
+func (s *PriceHistoryService) Record(ctx context.Context, change *domain.PriceChange) {
+	if err := s.priceChangeRepo.Create(ctx, change); err != nil {
+		logger.Log.Error("Failed to record price change",
+			zap.String("product_id", change.ProductID),
+			zap.String("trigger", change.Trigger),
+			zap.Float64("old_price", change.OldPrice),
+			zap.Float64("new_price", change.NewPrice),
+			zap.Error(err),
+		)
+	}
+}
+
+// GetHistory returns the price changes of the user's product over the last days, newest first
+func (s *PriceHistoryService) GetHistory(ctx context.Context, userID, productID string, days, limit int) ([]domain.PriceChange, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil || product == nil || product.UserID != userID {
+		return nil, ErrProductNotFound
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	return s.priceChangeRepo.GetByProductID(ctx, product.ID, since, limit)
+}
+
+// recent returns the price changes of a product in a city ("" for the base price) since a
+// time, newest first
+func (s *PriceHistoryService) recent(ctx context.Context, productID, city string, since time.Time) ([]domain.PriceChange, error) {
+	changes, err := s.priceChangeRepo.GetByProductID(ctx, productID, since, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	inCity := changes[:0]
+	for _, c := range changes {
+		if c.City == city {
+			inCity = append(inCity, c)
+		}
+	}
+	return inCity, nil
+}
+
+// Prune removes price changes older than the retention period
+func (s *PriceHistoryService) Prune(ctx context.Context) error {
+	if s.retentionDays <= 0 {
+		return nil
+	}
+
+	before := time.Now().AddDate(0, 0, -s.retentionDays)
+	deleted, err := s.priceChangeRepo.DeleteOlderThan(ctx, before)
+	if err != nil {
+		return fmt.Errorf("failed to prune price history: %w", err)
+	}
+
+	logger.Log.Info("Price history pruned",
+		zap.Int("retention_days", s.retentionDays),
+		zap.Int64("deleted", deleted),
+	)
+
+	return nil
+}
+
+// snapshotOffers keeps the part of competitor offers the price history needs
+func snapshotOffers(offers []marketplace.CompetitorOffer) []domain.CompetitorSnapshot {
+	snapshot := make([]domain.CompetitorSnapshot, 0, len(offers))
+	for _, o := range offers {
+		snapshot = append(snapshot, domain.CompetitorSnapshot{
+			SellerID:   o.SellerID,
+			SellerName: o.SellerName,
+			Price:      o.Price,
+		})
+	}
+	return snapshot
+}
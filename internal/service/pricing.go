@@ -0,0 +1,708 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/pkg/logger"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrInvalidPrice is returned for negative prices and for a min price above the max price
+	ErrInvalidPrice = errors.New("invalid price")
+
+	// ErrCityRequired is returned when a city price is changed without naming the city
+	ErrCityRequired = errors.New("city is required")
+
+	// ErrInvalidTag is returned for empty tags and for more tags than MaxProductTags
+	ErrInvalidTag = errors.New("invalid tag")
+
+	// ErrTagStrategyNotFound is returned when a tag has no pricing strategy
+	ErrTagStrategyNotFound = errors.New("tag has no pricing strategy")
+
+	// ErrInvalidCosts is returned for negative costs and for a commission and margin taking the whole price
+	ErrInvalidCosts = errors.New("invalid product costs")
+)
+
+// MaxProductTags caps how many tags one product may carry
+const MaxProductTags = 20
+
+// PricingService changes product prices and price settings on the user's request
+type PricingService struct {
+	connectionRepo  domain.MarketplaceConnectionRepository
+	productRepo     domain.ProductRepository
+	tagStrategyRepo domain.TagPricingStrategyRepository
+	filterRepo      domain.CompetitorFilterRepository
+	clients         ClientProvider
+	feedService     *PriceFeedService
+	historyService  *PriceHistoryService
+}
+
+func NewPricingService(
+	connectionRepo domain.MarketplaceConnectionRepository,
+	productRepo domain.ProductRepository,
+	tagStrategyRepo domain.TagPricingStrategyRepository,
+	filterRepo domain.CompetitorFilterRepository,
+	clients ClientProvider,
+	feedService *PriceFeedService,
+	historyService *PriceHistoryService,
+) *PricingService {
+	return &PricingService{
+		connectionRepo:  connectionRepo,
+		productRepo:     productRepo,
+		tagStrategyRepo: tagStrategyRepo,
+		filterRepo:      filterRepo,
+		clients:         clients,
+		feedService:     feedService,
+		historyService:  historyService,
+	}
+}
+
+// PriceUpdate is the new price of one product in a bulk price update
+type PriceUpdate struct {
+	ProductID string
+	Price     float64
+}
+
+// PriceUpdateResult is the outcome of one product of a bulk price update
+type PriceUpdateResult struct {
+	ProductID string  `json:"product_id"`
+	Price     float64 `json:"price,omitempty"` // New price on success
+	Error     string  `json:"error,omitempty"`
+}
+
+// ProductStrategy is the strategy a product is repriced with and where it comes from
+type ProductStrategy struct {
+	Strategy domain.PricingStrategyConfig `json:"strategy"`
+	Source   string                       `json:"source"`        // product, tag or default
+	Tag      string                       `json:"tag,omitempty"` // Set when Source is tag
+}
+
+// ProductCompetitorFilter is the competitor filter a product is repriced with and what it is made of
+type ProductCompetitorFilter struct {
+	Product   *domain.CompetitorFilter `json:"product,omitempty"` // The product's own filter
+	User      *domain.CompetitorFilter `json:"user,omitempty"`    // The filter of all the user's products
+	Effective domain.CompetitorFilter  `json:"effective"`         // The two combined, as dumping applies them
+}
+
+// ProductMargin is what a product earns per unit at its current price and, if given, a
+// proposed one, with the floor dumping keeps to. Margins are only known once costs are set.
+type ProductMargin struct {
+	Costs     *domain.ProductCosts `json:"costs,omitempty"`
+	MinPrice  float64              `json:"min_price"`  // Set by hand
+	CostFloor float64              `json:"cost_floor"` // Lowest price keeping the min margin; 0 without costs
+	Floor     float64              `json:"floor"`      // What dumping keeps to: the higher of the two
+	Current   *domain.Margin       `json:"current,omitempty"`
+	Proposed  *domain.Margin       `json:"proposed,omitempty"`
+	Cities    []CityMargin         `json:"cities,omitempty"`
+}
+
+// CityMargin is the margin of a product at its price in one city
+type CityMargin struct {
+	City    string         `json:"city"`
+	Floor   float64        `json:"floor"`
+	Current *domain.Margin `json:"current,omitempty"`
+}
+
+// PriceLimits is a change to the range dumping keeps a price in. Nil fields are left as they are.
+type PriceLimits struct {
+	MinPrice *float64 // Dumping floor; 0 for none
+	MaxPrice *float64 // Ceiling dumping raises the price to when there is room; 0 for none
+}
+
+// CityPriceUpdate is a change to a product's price settings in one city. Nil fields are left as they are.
+type CityPriceUpdate struct {
+	PriceLimits          // 0 falls back to the product's limits
+	Price       *float64 // Pushed to the marketplace right away
+}
+
+// SetCityPrice changes the user's product price settings in one city and returns the product.
+// A city the product has no price in yet is added, starting from the product's price.
+func (s *PricingService) SetCityPrice(ctx context.Context, userID, productID, city string, update CityPriceUpdate) (*domain.Product, error) {
+	city = strings.TrimSpace(city)
+	if city == "" {
+		return nil, ErrCityRequired
+	}
+	if err := update.PriceLimits.validate(); err != nil {
+		return nil, err
+	}
+	if update.Price != nil && *update.Price <= 0 {
+		return nil, fmt.Errorf("%w: price must be positive", ErrInvalidPrice)
+	}
+
+	product, err := s.ownedProduct(ctx, userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	cityPrice := product.CityPrice(city)
+	added := cityPrice == nil
+	if added {
+		product.CityPrices = append(product.CityPrices, domain.CityPrice{City: city, Price: product.Price})
+		cityPrice = &product.CityPrices[len(product.CityPrices)-1]
+	}
+
+	if update.MinPrice != nil {
+		cityPrice.MinPrice = *update.MinPrice
+	}
+	if update.MaxPrice != nil {
+		cityPrice.MaxPrice = *update.MaxPrice
+	}
+	if err := checkPriceRange(product.FloorFor(cityPrice), product.CeilingFor(cityPrice)); err != nil {
+		return nil, err
+	}
+
+	var conn *domain.MarketplaceConnection
+	priceChanged := update.Price != nil && *update.Price != cityPrice.Price
+	if priceChanged {
+		var client marketplace.MarketplaceClient
+		conn, client, err = s.clientFor(ctx, product)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := client.UpdateCityPrice(ctx, product.ExternalID, cityPrice.City, *update.Price); err != nil {
+			return nil, fmt.Errorf("failed to update city price on %s: %w", conn.Marketplace, err)
+		}
+
+		s.historyService.Record(ctx, &domain.PriceChange{
+			ProductID:          product.ID,
+			UserID:             product.UserID,
+			ConnectionID:       product.ConnectionID,
+			City:               cityPrice.City,
+			OldPrice:           cityPrice.Price,
+			NewPrice:           *update.Price,
+			CompetitorMinPrice: cityPrice.CompetitorMinPrice,
+			Trigger:            domain.PriceTriggerManual,
+			Actor:              userID,
+		})
+		cityPrice.Price = *update.Price
+	}
+
+	if err := s.productRepo.SetCityPrice(ctx, product.ID, *cityPrice); err != nil {
+		return nil, fmt.Errorf("failed to save city price: %w", err)
+	}
+
+	// The Kaspi price feed lists city prices, so a new city or city price changes it
+	if added || priceChanged {
+		if conn == nil {
+			conn, err = s.connectionRepo.GetByID(ctx, product.ConnectionID)
+		}
+		if err == nil && conn != nil {
+			s.feedService.RegenerateQuietly(ctx, conn)
+		}
+	}
+
+	logger.Log.Info("City price updated",
+		zap.String("user_id", userID),
+		zap.String("product_id", product.ID),
+		zap.String("city", cityPrice.City),
+		zap.Float64("price", cityPrice.Price),
+		zap.Float64("min_price", cityPrice.MinPrice),
+		zap.Float64("max_price", cityPrice.MaxPrice),
+	)
+
+	return product, nil
+}
+
+// SetPrice sets the price of the user's product on the marketplace
+func (s *PricingService) SetPrice(ctx context.Context, userID, productID string, price float64) (*domain.Product, error) {
+	product, conn, err := s.setPrice(ctx, userID, productID, price, domain.PriceTriggerManual)
+	if err != nil {
+		return nil, err
+	}
+
+	s.feedService.RegenerateQuietly(ctx, conn)
+	return product, nil
+}
+
+// SetPrices sets the prices of several of the user's products one by one; a failure on one
+// product does not stop the others
+func (s *PricingService) SetPrices(ctx context.Context, userID string, updates []PriceUpdate) []PriceUpdateResult {
+	results := make([]PriceUpdateResult, 0, len(updates))
+	touched := make(map[string]*domain.MarketplaceConnection)
+
+	for _, u := range updates {
+		if err := ctx.Err(); err != nil {
+			results = append(results, PriceUpdateResult{ProductID: u.ProductID, Error: err.Error()})
+			continue
+		}
+
+		product, conn, err := s.setPrice(ctx, userID, u.ProductID, u.Price, domain.PriceTriggerBulk)
+		if err != nil {
+			results = append(results, PriceUpdateResult{ProductID: u.ProductID, Error: err.Error()})
+			continue
+		}
+
+		touched[conn.ID] = conn
+		results = append(results, PriceUpdateResult{ProductID: u.ProductID, Price: product.Price})
+	}
+
+	// One feed regeneration per connection rather than one per product
+	for _, conn := range touched {
+		s.feedService.RegenerateQuietly(ctx, conn)
+	}
+
+	return results
+}
+
+func (s *PricingService) setPrice(ctx context.Context, userID, productID string, price float64, trigger string) (*domain.Product, *domain.MarketplaceConnection, error) {
+	if price <= 0 {
+		return nil, nil, fmt.Errorf("%w: price must be positive", ErrInvalidPrice)
+	}
+
+	product, err := s.ownedProduct(ctx, userID, productID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conn, client, err := s.clientFor(ctx, product)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := client.UpdateProductPrice(ctx, product.ExternalID, price); err != nil {
+		return nil, nil, fmt.Errorf("failed to update price on %s: %w", conn.Marketplace, err)
+	}
+
+	if err := s.productRepo.UpdatePrice(ctx, product.ID, price, product.CompetitorMinPrice); err != nil {
+		return nil, nil, fmt.Errorf("failed to save price: %w", err)
+	}
+
+	s.historyService.Record(ctx, &domain.PriceChange{
+		ProductID:          product.ID,
+		UserID:             product.UserID,
+		ConnectionID:       product.ConnectionID,
+		OldPrice:           product.Price,
+		NewPrice:           price,
+		CompetitorMinPrice: product.CompetitorMinPrice,
+		Trigger:            trigger,
+		Actor:              userID,
+	})
+
+	logger.Log.Info("Price updated",
+		zap.String("user_id", userID),
+		zap.String("product_id", product.ID),
+		zap.String("trigger", trigger),
+		zap.Float64("old_price", product.Price),
+		zap.Float64("new_price", price),
+	)
+
+	product.Price = price
+	return product, conn, nil
+}
+
+// SetPriceLimits changes the min and max price of the user's product
+func (s *PricingService) SetPriceLimits(ctx context.Context, userID, productID string, limits PriceLimits) (*domain.Product, error) {
+	if err := limits.validate(); err != nil {
+		return nil, err
+	}
+
+	product, err := s.ownedProduct(ctx, userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if limits.MinPrice != nil {
+		product.MinPrice = *limits.MinPrice
+	}
+	if limits.MaxPrice != nil {
+		product.MaxPrice = *limits.MaxPrice
+	}
+	if err := checkPriceRange(product.FloorFor(nil), product.MaxPrice); err != nil {
+		return nil, err
+	}
+
+	if err := s.productRepo.UpdatePriceLimits(ctx, product.ID, product.MinPrice, product.MaxPrice); err != nil {
+		return nil, fmt.Errorf("failed to save price limits: %w", err)
+	}
+
+	logger.Log.Info("Price limits updated",
+		zap.String("user_id", userID),
+		zap.String("product_id", product.ID),
+		zap.Float64("min_price", product.MinPrice),
+		zap.Float64("max_price", product.MaxPrice),
+	)
+
+	return product, nil
+}
+
+// SetProductCosts sets the costs of the user's product, from which dumping computes its floor;
+// nil removes them so only the min price applies
+func (s *PricingService) SetProductCosts(ctx context.Context, userID, productID string, costs *domain.ProductCosts) (*ProductMargin, error) {
+	if costs != nil {
+		if err := validateCosts(*costs); err != nil {
+			return nil, err
+		}
+	}
+
+	product, err := s.ownedProduct(ctx, userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	product.Costs = costs
+	if err := checkPriceRange(product.FloorFor(nil), product.MaxPrice); err != nil {
+		return nil, err
+	}
+	for i := range product.CityPrices {
+		city := &product.CityPrices[i]
+		if err := checkPriceRange(product.FloorFor(city), product.CeilingFor(city)); err != nil {
+			return nil, fmt.Errorf("%s: %w", city.City, err)
+		}
+	}
+
+	if err := s.productRepo.UpdateCosts(ctx, product.ID, costs); err != nil {
+		return nil, fmt.Errorf("failed to save product costs: %w", err)
+	}
+
+	logger.Log.Info("Product costs updated",
+		zap.String("user_id", userID),
+		zap.String("product_id", product.ID),
+		zap.Float64("floor", product.FloorFor(nil)),
+	)
+
+	return marginOf(product, nil), nil
+}
+
+// GetMargin returns the margin of the user's product at its current prices and, if proposed
+// is not nil, at that price
+func (s *PricingService) GetMargin(ctx context.Context, userID, productID string, proposed *float64) (*ProductMargin, error) {
+	if proposed != nil && *proposed <= 0 {
+		return nil, fmt.Errorf("%w: proposed price must be positive", ErrInvalidPrice)
+	}
+
+	product, err := s.ownedProduct(ctx, userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	return marginOf(product, proposed), nil
+}
+
+// SetProductDryRun turns dry-run mode of the user's product on or off. In dry-run mode dumping
+// works out and records the price it would set but leaves the price on the marketplace alone;
+// the product is processed even while auto dumping is off.
+func (s *PricingService) SetProductDryRun(ctx context.Context, userID, productID string, enabled bool) (*domain.Product, error) {
+	product, err := s.ownedProduct(ctx, userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	product.DumpingDryRun = enabled
+	if err := s.productRepo.SetDumpingDryRun(ctx, product.ID, enabled); err != nil {
+		return nil, fmt.Errorf("failed to save product dry-run mode: %w", err)
+	}
+
+	logger.Log.Info("Product dry-run mode updated",
+		zap.String("user_id", userID),
+		zap.String("product_id", product.ID),
+		zap.Bool("dry_run", enabled),
+	)
+
+	return product, nil
+}
+
+// GetProductStrategy returns the strategy the user's product is repriced with
+func (s *PricingService) GetProductStrategy(ctx context.Context, userID, productID string) (*ProductStrategy, error) {
+	product, err := s.ownedProduct(ctx, userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	tagStrategies, err := s.tagStrategyRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byTag := make(map[string]domain.PricingStrategyConfig, len(tagStrategies))
+	for _, ts := range tagStrategies {
+		byTag[ts.Tag] = ts.Strategy
+	}
+
+	cfg, source, tag := StrategyConfigFor(product, byTag)
+	return &ProductStrategy{Strategy: cfg, Source: source, Tag: tag}, nil
+}
+
+// SetProductStrategy gives the user's product its own strategy; nil makes it use its tags' strategy again
+func (s *PricingService) SetProductStrategy(ctx context.Context, userID, productID string, cfg *domain.PricingStrategyConfig) (*domain.Product, error) {
+	if cfg != nil {
+		if _, err := NewPricingStrategy(*cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	product, err := s.ownedProduct(ctx, userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	product.Strategy = cfg
+	if err := s.productRepo.UpdateStrategy(ctx, product.ID, cfg); err != nil {
+		return nil, fmt.Errorf("failed to save product strategy: %w", err)
+	}
+
+	strategyType := ""
+	if cfg != nil {
+		strategyType = cfg.Type
+	}
+	logger.Log.Info("Product pricing strategy updated",
+		zap.String("user_id", userID),
+		zap.String("product_id", product.ID),
+		zap.String("strategy", strategyType),
+	)
+
+	return product, nil
+}
+
+// SetProductTags replaces the tags of the user's product. Tags are trimmed, lower-cased and deduplicated.
+func (s *PricingService) SetProductTags(ctx context.Context, userID, productID string, tags []string) (*domain.Product, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag == "" {
+			return nil, fmt.Errorf("%w: tags must not be empty", ErrInvalidTag)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	if len(normalized) > MaxProductTags {
+		return nil, fmt.Errorf("%w: at most %d tags per product", ErrInvalidTag, MaxProductTags)
+	}
+
+	product, err := s.ownedProduct(ctx, userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	product.Tags = normalized
+	if err := s.productRepo.UpdateTags(ctx, product.ID, normalized); err != nil {
+		return nil, fmt.Errorf("failed to save product tags: %w", err)
+	}
+
+	return product, nil
+}
+
+// ListTagStrategies returns the user's tag strategies, sorted by tag
+func (s *PricingService) ListTagStrategies(ctx context.Context, userID string) ([]domain.TagPricingStrategy, error) {
+	return s.tagStrategyRepo.GetByUserID(ctx, userID)
+}
+
+// SetTagStrategy sets the strategy of the user's products carrying a tag
+func (s *PricingService) SetTagStrategy(ctx context.Context, userID, tag string, cfg domain.PricingStrategyConfig) (*domain.TagPricingStrategy, error) {
+	tag = normalizeTag(tag)
+	if tag == "" {
+		return nil, fmt.Errorf("%w: tag must not be empty", ErrInvalidTag)
+	}
+	if _, err := NewPricingStrategy(cfg); err != nil {
+		return nil, err
+	}
+
+	strategy := &domain.TagPricingStrategy{
+		UserID:   userID,
+		Tag:      tag,
+		Strategy: cfg,
+	}
+	if err := s.tagStrategyRepo.Save(ctx, strategy); err != nil {
+		return nil, err
+	}
+
+	logger.Log.Info("Tag pricing strategy updated",
+		zap.String("user_id", userID),
+		zap.String("tag", tag),
+		zap.String("strategy", cfg.Type),
+	)
+
+	// Re-read for the ID and creation time of an existing strategy
+	return s.tagStrategyRepo.GetByTag(ctx, userID, tag)
+}
+
+// DeleteTagStrategy removes the strategy of a tag; its products fall back to their other
+// tags' strategies or the default
+func (s *PricingService) DeleteTagStrategy(ctx context.Context, userID, tag string) error {
+	tag = normalizeTag(tag)
+
+	existing, err := s.tagStrategyRepo.GetByTag(ctx, userID, tag)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrTagStrategyNotFound
+	}
+
+	return s.tagStrategyRepo.Delete(ctx, userID, tag)
+}
+
+// GetCompetitorFilter returns the competitor filter of all the user's products; empty if none is set
+func (s *PricingService) GetCompetitorFilter(ctx context.Context, userID string) (domain.CompetitorFilter, error) {
+	existing, err := s.filterRepo.GetByUserID(ctx, userID)
+	if err != nil || existing == nil {
+		return domain.CompetitorFilter{}, err
+	}
+	return existing.Filter, nil
+}
+
+// SetCompetitorFilter sets the competitor filter of all the user's products; an empty filter removes it
+func (s *PricingService) SetCompetitorFilter(ctx context.Context, userID string, filter domain.CompetitorFilter) (domain.CompetitorFilter, error) {
+	filter, err := normalizeCompetitorFilter(filter)
+	if err != nil {
+		return filter, err
+	}
+
+	if filter.IsZero() {
+		return filter, s.filterRepo.Delete(ctx, userID)
+	}
+
+	if err := s.filterRepo.Save(ctx, &domain.UserCompetitorFilter{UserID: userID, Filter: filter}); err != nil {
+		return filter, err
+	}
+
+	logger.Log.Info("Competitor filter updated",
+		zap.String("user_id", userID),
+		zap.Int("ignored_sellers", len(filter.IgnoreSellers)),
+		zap.Int("followed_sellers", len(filter.OnlySellers)),
+	)
+
+	return filter, nil
+}
+
+// GetProductCompetitorFilter returns the competitor filter the user's product is repriced with
+func (s *PricingService) GetProductCompetitorFilter(ctx context.Context, userID, productID string) (*ProductCompetitorFilter, error) {
+	product, err := s.ownedProduct(ctx, userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.filterRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ProductCompetitorFilter{Product: product.CompetitorFilter}
+	if existing != nil {
+		result.User = &existing.Filter
+	}
+	result.Effective = CombineCompetitorFilters(result.User, result.Product)
+
+	return result, nil
+}
+
+// SetProductCompetitorFilter gives the user's product its own competitor filter, combined with
+// the user's; nil or an empty filter removes it
+func (s *PricingService) SetProductCompetitorFilter(ctx context.Context, userID, productID string, filter *domain.CompetitorFilter) (*domain.Product, error) {
+	if filter != nil {
+		normalized, err := normalizeCompetitorFilter(*filter)
+		if err != nil {
+			return nil, err
+		}
+		filter = &normalized
+		if filter.IsZero() {
+			filter = nil
+		}
+	}
+
+	product, err := s.ownedProduct(ctx, userID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	product.CompetitorFilter = filter
+	if err := s.productRepo.UpdateCompetitorFilter(ctx, product.ID, filter); err != nil {
+		return nil, fmt.Errorf("failed to save product competitor filter: %w", err)
+	}
+
+	return product, nil
+}
+
+// clientFor returns the connection of a product and its marketplace client
+func (s *PricingService) clientFor(ctx context.Context, product *domain.Product) (*domain.MarketplaceConnection, marketplace.MarketplaceClient, error) {
+	conn, err := s.connectionRepo.GetByID(ctx, product.ConnectionID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	if conn == nil {
+		return nil, nil, fmt.Errorf("connection %s of product %s no longer exists", product.ConnectionID, product.ID)
+	}
+
+	client, err := s.clients.ClientFor(conn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return conn, client, nil
+}
+
+// ownedProduct loads a product of the user; products of other users are reported as not found
+func (s *PricingService) ownedProduct(ctx context.Context, userID, productID string) (*domain.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil || product == nil || product.UserID != userID {
+		return nil, ErrProductNotFound
+	}
+	return product, nil
+}
+
+func (l PriceLimits) validate() error {
+	if (l.MinPrice != nil && *l.MinPrice < 0) || (l.MaxPrice != nil && *l.MaxPrice < 0) {
+		return fmt.Errorf("%w: min_price and max_price must not be negative", ErrInvalidPrice)
+	}
+	return nil
+}
+
+// checkPriceRange rejects a floor above the ceiling; 0 means no limit
+func checkPriceRange(minPrice, maxPrice float64) error {
+	if minPrice > 0 && maxPrice > 0 && minPrice > maxPrice {
+		return fmt.Errorf("%w: floor %.2f is above max_price %.2f", ErrInvalidPrice, minPrice, maxPrice)
+	}
+	return nil
+}
+
+func marginOf(product *domain.Product, proposed *float64) *ProductMargin {
+	m := &ProductMargin{
+		Costs:    product.Costs,
+		MinPrice: product.MinPrice,
+		Floor:    product.FloorFor(nil),
+	}
+	if product.Costs == nil {
+		return m
+	}
+
+	m.CostFloor = product.Costs.Floor()
+	current := product.Costs.MarginAt(product.Price)
+	m.Current = &current
+	if proposed != nil {
+		p := product.Costs.MarginAt(*proposed)
+		m.Proposed = &p
+	}
+	for i := range product.CityPrices {
+		city := &product.CityPrices[i]
+		cityMargin := product.Costs.MarginAt(city.Price)
+		m.Cities = append(m.Cities, CityMargin{
+			City:    city.City,
+			Floor:   product.FloorFor(city),
+			Current: &cityMargin,
+		})
+	}
+
+	return m
+}
+
+func validateCosts(c domain.ProductCosts) error {
+	if c.CostPrice < 0 || c.CommissionPercent < 0 || c.Fee < 0 || c.MinMarginPercent < 0 {
+		return fmt.Errorf("%w: costs must not be negative", ErrInvalidCosts)
+	}
+	if c.CommissionPercent+c.MinMarginPercent >= 100 {
+		return fmt.Errorf("%w: commission_percent and min_margin_percent must add up to less than 100", ErrInvalidCosts)
+	}
+	return nil
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+// ErrInvalidStrategy is returned for unknown strategy types and out-of-range parameters
+var ErrInvalidStrategy = errors.New("invalid pricing strategy")
+
+// DefaultPricingStrategy is used by products that have no strategy of their own or from a tag
+var DefaultPricingStrategy = domain.PricingStrategyConfig{
+	Type:   domain.StrategyUndercutAmount,
+	Amount: PriceDumpMargin,
+}
+
+// Where a product's strategy comes from
+const (
+	StrategySourceProduct = "product"
+	StrategySourceTag     = "tag"
+	StrategySourceDefault = "default"
+)
+
+// PricingStrategy decides the price to set from the competitor offers.
+// Min prices are applied by the caller.
+type PricingStrategy interface {
+	// TargetPrice returns the price to set, or false when the offers give no target
+	TargetPrice(offers []marketplace.CompetitorOffer) (float64, bool)
+}
+
+// NewPricingStrategy builds the strategy a config selects, checking its parameters
+func NewPricingStrategy(cfg domain.PricingStrategyConfig) (PricingStrategy, error) {
+	switch cfg.Type {
+	case domain.StrategyUndercutAmount:
+		if cfg.Amount <= 0 {
+			return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidStrategy)
+		}
+		return undercutAmount{amount: cfg.Amount}, nil
+
+	case domain.StrategyUndercutPercent:
+		if cfg.Percent <= 0 || cfg.Percent >= 100 {
+			return nil, fmt.Errorf("%w: percent must be between 0 and 100", ErrInvalidStrategy)
+		}
+		return undercutPercent{percent: cfg.Percent}, nil
+
+	case domain.StrategyMatchLowest:
+		return matchLowest{}, nil
+
+	case domain.StrategyTargetRank:
+		if cfg.Rank < 1 {
+			return nil, fmt.Errorf("%w: rank must be 1 or more", ErrInvalidStrategy)
+		}
+		if cfg.Amount < 0 {
+			return nil, fmt.Errorf("%w: amount must not be negative", ErrInvalidStrategy)
+		}
+		return targetRank{rank: cfg.Rank, amount: amountOrDefault(cfg.Amount)}, nil
+
+	case domain.StrategyMedianBand:
+		if cfg.Percent <= 0 || cfg.Percent >= 100 {
+			return nil, fmt.Errorf("%w: percent must be between 0 and 100", ErrInvalidStrategy)
+		}
+		if cfg.Amount < 0 {
+			return nil, fmt.Errorf("%w: amount must not be negative", ErrInvalidStrategy)
+		}
+		return medianBand{percent: cfg.Percent, amount: amountOrDefault(cfg.Amount)}, nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidStrategy, cfg.Type)
+}
+
+// StrategyConfigFor returns the strategy config a product is repriced with and where it
+// comes from: the product's own, else the first of its tags that has one, else the default.
+// tagStrategies maps the user's tags to their strategies.
+func StrategyConfigFor(product *domain.Product, tagStrategies map[string]domain.PricingStrategyConfig) (domain.PricingStrategyConfig, string, string) {
+	if product.Strategy != nil {
+		return *product.Strategy, StrategySourceProduct, ""
+	}
+	for _, tag := range product.Tags {
+		if cfg, ok := tagStrategies[tag]; ok {
+			return cfg, StrategySourceTag, tag
+		}
+	}
+	return DefaultPricingStrategy, StrategySourceDefault, ""
+}
+
+// amountOrDefault falls back to the default undercut when no amount is given
+func amountOrDefault(amount float64) float64 {
+	if amount == 0 {
+		return PriceDumpMargin
+	}
+	return amount
+}
+
+// sortedPrices returns the offer prices, cheapest first
+func sortedPrices(offers []marketplace.CompetitorOffer) []float64 {
+	prices := make([]float64, len(offers))
+	for i, o := range offers {
+		prices[i] = o.Price
+	}
+	sort.Float64s(prices)
+	return prices
+}
+
+// medianOf returns the median of sorted prices (0 if there are none)
+func medianOf(prices []float64) float64 {
+	if len(prices) == 0 {
+		return 0
+	}
+	if len(prices)%2 == 0 {
+		return (prices[len(prices)/2-1] + prices[len(prices)/2]) / 2
+	}
+	return prices[len(prices)/2]
+}
+
+// undercutAmount sets the price a fixed amount under the cheapest competitor
+type undercutAmount struct {
+	amount float64
+}
+
+func (s undercutAmount) TargetPrice(offers []marketplace.CompetitorOffer) (float64, bool) {
+	if len(offers) == 0 {
+		return 0, false
+	}
+	return marketplace.MinOfferPrice(offers) - s.amount, true
+}
+
+// undercutPercent sets the price a percentage under the cheapest competitor, rounded down to whole units
+type undercutPercent struct {
+	percent float64
+}
+
+func (s undercutPercent) TargetPrice(offers []marketplace.CompetitorOffer) (float64, bool) {
+	if len(offers) == 0 {
+		return 0, false
+	}
+	return math.Floor(marketplace.MinOfferPrice(offers) * (1 - s.percent/100)), true
+}
+
+// matchLowest sets the price of the cheapest competitor
+type matchLowest struct{}
+
+func (matchLowest) TargetPrice(offers []marketplace.CompetitorOffer) (float64, bool) {
+	if len(offers) == 0 {
+		return 0, false
+	}
+	return marketplace.MinOfferPrice(offers), true
+}
+
+// targetRank aims for a position among the offers: with rank 2 the price is set just under
+// the second cheapest competitor. With fewer competitors it matches the most expensive one.
+type targetRank struct {
+	rank   int
+	amount float64
+}
+
+func (s targetRank) TargetPrice(offers []marketplace.CompetitorOffer) (float64, bool) {
+	if len(offers) == 0 {
+		return 0, false
+	}
+	prices := sortedPrices(offers)
+	if s.rank > len(prices) {
+		return prices[len(prices)-1], true
+	}
+	return prices[s.rank-1] - s.amount, true
+}
+
+// medianBand undercuts the cheapest competitor but stays within a percentage of the
+// median offer, so one seller dumping far below the market is not followed all the way down
+type medianBand struct {
+	percent float64
+	amount  float64
+}
+
+func (s medianBand) TargetPrice(offers []marketplace.CompetitorOffer) (float64, bool) {
+	if len(offers) == 0 {
+		return 0, false
+	}
+	prices := sortedPrices(offers)
+
+	lowest := math.Ceil(medianOf(prices) * (1 - s.percent/100))
+	return math.Max(prices[0]-s.amount, lowest), true
+}
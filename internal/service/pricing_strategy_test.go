@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+)
+
+// offersAt builds competitor offers at the given prices, one seller each
+func offersAt(prices ...float64) []marketplace.CompetitorOffer {
+	offers := make([]marketplace.CompetitorOffer, len(prices))
+	for i, p := range prices {
+		offers[i] = marketplace.CompetitorOffer{SellerID: string(rune('a' + i)), Price: p}
+	}
+	return offers
+}
+
+func TestNewPricingStrategyValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  domain.PricingStrategyConfig
+		ok   bool
+	}{
+		{"undercut amount", domain.PricingStrategyConfig{Type: domain.StrategyUndercutAmount, Amount: 5}, true},
+		{"undercut zero amount", domain.PricingStrategyConfig{Type: domain.StrategyUndercutAmount}, false},
+		{"undercut percent", domain.PricingStrategyConfig{Type: domain.StrategyUndercutPercent, Percent: 3}, true},
+		{"undercut zero percent", domain.PricingStrategyConfig{Type: domain.StrategyUndercutPercent}, false},
+		{"undercut whole price", domain.PricingStrategyConfig{Type: domain.StrategyUndercutPercent, Percent: 100}, false},
+		{"match lowest", domain.PricingStrategyConfig{Type: domain.StrategyMatchLowest}, true},
+		{"target rank", domain.PricingStrategyConfig{Type: domain.StrategyTargetRank, Rank: 2}, true},
+		{"target rank zero", domain.PricingStrategyConfig{Type: domain.StrategyTargetRank}, false},
+		{"target rank negative amount", domain.PricingStrategyConfig{Type: domain.StrategyTargetRank, Rank: 2, Amount: -1}, false},
+		{"median band", domain.PricingStrategyConfig{Type: domain.StrategyMedianBand, Percent: 10}, true},
+		{"median band zero percent", domain.PricingStrategyConfig{Type: domain.StrategyMedianBand}, false},
+		{"median band negative amount", domain.PricingStrategyConfig{Type: domain.StrategyMedianBand, Percent: 10, Amount: -1}, false},
+		{"unknown type", domain.PricingStrategyConfig{Type: "cheapest_ever"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewPricingStrategy(tt.cfg)
+			if tt.ok {
+				if err != nil || strategy == nil {
+					t.Fatalf("NewPricingStrategy(%+v) = %v, %v, want a strategy", tt.cfg, strategy, err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidStrategy) {
+				t.Fatalf("NewPricingStrategy(%+v) err = %v, want ErrInvalidStrategy", tt.cfg, err)
+			}
+		})
+	}
+}
+
+func TestStrategyTargetPrice(t *testing.T) {
+	// Cheapest 1000, median 1350
+	market := offersAt(1500, 1000, 2000, 1200)
+
+	tests := []struct {
+		name   string
+		cfg    domain.PricingStrategyConfig
+		offers []marketplace.CompetitorOffer
+		want   float64
+	}{
+		{"undercut amount", domain.PricingStrategyConfig{Type: domain.StrategyUndercutAmount, Amount: 5}, market, 995},
+		{"undercut percent", domain.PricingStrategyConfig{Type: domain.StrategyUndercutPercent, Percent: 10}, market, 900},
+		{"undercut percent rounds down", domain.PricingStrategyConfig{Type: domain.StrategyUndercutPercent, Percent: 10}, offersAt(1001), 900},
+		{"match lowest", domain.PricingStrategyConfig{Type: domain.StrategyMatchLowest}, market, 1000},
+		{"target rank default amount", domain.PricingStrategyConfig{Type: domain.StrategyTargetRank, Rank: 2}, market, 1199},
+		{"target rank", domain.PricingStrategyConfig{Type: domain.StrategyTargetRank, Rank: 3, Amount: 50}, market, 1450},
+		{"target rank past the last", domain.PricingStrategyConfig{Type: domain.StrategyTargetRank, Rank: 9}, market, 2000},
+		{"median band holds at the band", domain.PricingStrategyConfig{Type: domain.StrategyMedianBand, Percent: 10}, market, 1215},
+		{"median band follows the cheapest", domain.PricingStrategyConfig{Type: domain.StrategyMedianBand, Percent: 50}, market, 999},
+		{"median band odd count", domain.PricingStrategyConfig{Type: domain.StrategyMedianBand, Percent: 10, Amount: 10}, offersAt(1000, 1200, 1500), 1080},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewPricingStrategy(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewPricingStrategy: %v", err)
+			}
+
+			got, ok := strategy.TargetPrice(tt.offers)
+			if !ok || got != tt.want {
+				t.Errorf("TargetPrice = %v, %v, want %v", got, ok, tt.want)
+			}
+
+			// No offers give no target
+			if _, ok := strategy.TargetPrice(nil); ok {
+				t.Error("TargetPrice(nil) gave a target")
+			}
+		})
+	}
+}
+
+func TestStrategyConfigFor(t *testing.T) {
+	own := domain.PricingStrategyConfig{Type: domain.StrategyMatchLowest}
+	clearance := domain.PricingStrategyConfig{Type: domain.StrategyUndercutPercent, Percent: 5}
+	tagStrategies := map[string]domain.PricingStrategyConfig{"clearance": clearance}
+
+	tests := []struct {
+		name       string
+		product    domain.Product
+		want       domain.PricingStrategyConfig
+		wantSource string
+		wantTag    string
+	}{
+		{"own strategy wins", domain.Product{Strategy: &own, Tags: []string{"clearance"}}, own, StrategySourceProduct, ""},
+		{"first tag with a strategy", domain.Product{Tags: []string{"summer", "clearance"}}, clearance, StrategySourceTag, "clearance"},
+		{"default", domain.Product{Tags: []string{"summer"}}, DefaultPricingStrategy, StrategySourceDefault, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, source, tag := StrategyConfigFor(&tt.product, tagStrategies)
+			if cfg != tt.want || source != tt.wantSource || tag != tt.wantTag {
+				t.Errorf("StrategyConfigFor = %+v, %q, %q, want %+v, %q, %q", cfg, source, tag, tt.want, tt.wantSource, tt.wantTag)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSetCityPriceRejectsInvalidPrice(t *testing.T) {
+	// Rejected before the product is looked up, so the service needs no repositories
+	var s PricingService
+
+	for _, price := range []float64{0, -100} {
+		_, err := s.SetCityPrice(context.Background(), "user", "product", "Алматы", CityPriceUpdate{Price: &price})
+		if !errors.Is(err, ErrInvalidPrice) {
+			t.Errorf("price %v: err = %v, want ErrInvalidPrice", price, err)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/internal/marketplace/kaspi"
+	"github.com/yourusername/seller-assistant/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// MaxStockCSVRows caps how many data rows one stock upload may contain
+const MaxStockCSVRows = 5000
+
+var (
+	// ErrProductNotFound is returned for unknown products and products of another user
+	ErrProductNotFound = errors.New("product not found")
+
+	// ErrInvalidStock is returned for negative quantities
+	ErrInvalidStock = errors.New("stock quantity must not be negative")
+
+	// ErrWarehouseRequired is returned when no warehouse is given and the marketplace has no default
+	ErrWarehouseRequired = errors.New("warehouse_id is required for this marketplace")
+
+	// ErrInvalidStockCSV is returned when an upload cannot be read as a stock CSV at all
+	ErrInvalidStockCSV = errors.New("invalid stock CSV")
+)
+
+// StockService pushes stock levels from our side to the marketplaces
+type StockService struct {
+	connectionRepo domain.MarketplaceConnectionRepository
+	productRepo    domain.ProductRepository
+	clients        ClientProvider
+	feedService    *PriceFeedService
+}
+
+func NewStockService(
+	connectionRepo domain.MarketplaceConnectionRepository,
+	productRepo domain.ProductRepository,
+	clients ClientProvider,
+	feedService *PriceFeedService,
+) *StockService {
+	return &StockService{
+		connectionRepo: connectionRepo,
+		productRepo:    productRepo,
+		clients:        clients,
+		feedService:    feedService,
+	}
+}
+
+// StockRowResult is the outcome of one row of a stock upload
+type StockRowResult struct {
+	Row         int    `json:"row"` // 1-based line number in the file, header included
+	SKU         string `json:"sku"`
+	WarehouseID string `json:"warehouse_id,omitempty"`
+	Quantity    int    `json:"quantity"`
+	Updated     bool   `json:"updated"`
+	Error       string `json:"error,omitempty"`
+}
+
+// SetProductStock sets the stock of the user's product at one warehouse on the marketplace
+// and returns the product with its stock re-read from the marketplace
+func (s *StockService) SetProductStock(ctx context.Context, userID, productID, warehouseID string, quantity int) (*domain.Product, error) {
+	if quantity < 0 {
+		return nil, ErrInvalidStock
+	}
+
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil || product == nil || product.UserID != userID {
+		return nil, ErrProductNotFound
+	}
+
+	conn, err := s.connectionRepo.GetByID(ctx, product.ConnectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	if conn == nil {
+		return nil, fmt.Errorf("connection %s of product %s no longer exists", product.ConnectionID, product.ID)
+	}
+
+	client, err := s.clients.ClientFor(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	if warehouseID == "" {
+		warehouseID = defaultWarehouse(conn)
+	}
+	if warehouseID == "" {
+		return nil, ErrWarehouseRequired
+	}
+
+	if err := client.UpdateStock(ctx, product.ExternalID, warehouseID, quantity); err != nil {
+		return nil, fmt.Errorf("failed to update stock on %s: %w", conn.Marketplace, err)
+	}
+
+	logger.Log.Info("Stock updated",
+		zap.String("user_id", userID),
+		zap.String("product_id", product.ID),
+		zap.String("warehouse_id", warehouseID),
+		zap.Int("quantity", quantity),
+	)
+
+	setLocationStock(product, warehouseID, quantity)
+	s.refreshStock(ctx, client, product)
+	s.feedService.RegenerateQuietly(ctx, conn)
+
+	return product, nil
+}
+
+// ImportCSV applies a stock CSV to the connection's products and reports the result per row.
+// The file needs a header with "sku" and "quantity" columns and an optional "warehouse_id";
+// comma and semicolon separators are accepted. A sku matches the product SKU or its
+// marketplace ID. Rows are applied one by one, so a bad row does not stop the others.
+func (s *StockService) ImportCSV(ctx context.Context, conn *domain.MarketplaceConnection, file io.Reader) ([]StockRowResult, error) {
+	reader, err := newStockCSVReader(file)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot read header: %v", ErrInvalidStockCSV, err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	skuCol, hasSKU := columns["sku"]
+	qtyCol, hasQty := columns["quantity"]
+	warehouseCol, hasWarehouse := columns["warehouse_id"]
+	if !hasSKU || !hasQty {
+		return nil, fmt.Errorf("%w: header must contain sku and quantity columns", ErrInvalidStockCSV)
+	}
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidStockCSV, err)
+	}
+	if len(rows) > MaxStockCSVRows {
+		return nil, fmt.Errorf("%w: %d rows, at most %d allowed", ErrInvalidStockCSV, len(rows), MaxStockCSVRows)
+	}
+
+	products, err := s.productRepo.GetByConnectionID(ctx, conn.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	// Rows may name a product by SKU or by marketplace ID
+	productsBySKU := make(map[string]*domain.Product, len(products)*2)
+	for i := range products {
+		p := &products[i]
+		productsBySKU[p.ExternalID] = p
+		if p.SKU != "" {
+			productsBySKU[p.SKU] = p
+		}
+	}
+
+	client, err := s.clients.ClientFor(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]StockRowResult, len(rows))
+	touched := make(map[string]*domain.Product)
+
+	for i, row := range rows {
+		current := &results[i]
+		current.Row = i + 2
+
+		if err := ctx.Err(); err != nil {
+			current.Error = err.Error()
+			continue
+		}
+
+		current.SKU = strings.TrimSpace(cell(row, skuCol))
+		if hasWarehouse {
+			current.WarehouseID = strings.TrimSpace(cell(row, warehouseCol))
+		}
+		if current.WarehouseID == "" {
+			current.WarehouseID = defaultWarehouse(conn)
+		}
+
+		quantity, err := strconv.Atoi(strings.TrimSpace(cell(row, qtyCol)))
+		switch {
+		case err != nil:
+			current.Error = "quantity is not a whole number"
+			continue
+		case quantity < 0:
+			current.Error = ErrInvalidStock.Error()
+			continue
+		}
+		current.Quantity = quantity
+
+		product, ok := productsBySKU[current.SKU]
+		if !ok {
+			current.Error = ErrProductNotFound.Error()
+			continue
+		}
+		if current.WarehouseID == "" {
+			current.Error = ErrWarehouseRequired.Error()
+			continue
+		}
+
+		if err := client.UpdateStock(ctx, product.ExternalID, current.WarehouseID, quantity); err != nil {
+			current.Error = err.Error()
+			continue
+		}
+
+		current.Updated = true
+		setLocationStock(product, current.WarehouseID, quantity)
+		touched[product.ID] = product
+	}
+
+	// Re-read the totals once per product rather than once per row
+	for _, product := range touched {
+		s.refreshStock(ctx, client, product)
+	}
+
+	logger.Log.Info("Stock CSV applied",
+		zap.String("connection_id", conn.ID),
+		zap.Int("rows", len(rows)),
+		zap.Int("products_updated", len(touched)),
+	)
+
+	if len(touched) > 0 {
+		s.feedService.RegenerateQuietly(ctx, conn)
+	}
+
+	return results, nil
+}
+
+// setLocationStock records a pushed quantity on the product's warehouse entry and recalculates
+// its days of stock from the known sales velocity. The next sync replaces it with what the
+// marketplace reports.
+func setLocationStock(product *domain.Product, warehouseID string, quantity int) {
+	stock := product.Stock(warehouseID)
+	if stock == nil {
+		product.Stocks = append(product.Stocks, domain.WarehouseStock{WarehouseID: warehouseID, Name: warehouseID})
+		stock = &product.Stocks[len(product.Stocks)-1]
+	}
+
+	stock.Quantity = quantity
+	stock.DaysOfStock = daysOfStockFor(quantity, stock.SalesVelocity)
+}
+
+// refreshStock stores the product's stock, with the total as the marketplace now reports it.
+// Failures are only logged: the stock was already pushed and the next sync corrects it.
+func (s *StockService) refreshStock(ctx context.Context, client marketplace.MarketplaceClient, product *domain.Product) {
+	stock, err := client.GetProductStock(ctx, product.ExternalID)
+	if err != nil {
+		logger.Log.Warn("Failed to re-read stock after update",
+			zap.String("product_id", product.ID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	product.CurrentStock = stock
+	if err := s.productRepo.UpdateStock(ctx, product.ID, product.Stocks, product.CurrentStock); err != nil {
+		logger.Log.Error("Failed to save product stock",
+			zap.String("product_id", product.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+// defaultWarehouse is the warehouse used when none is given: a Kaspi connection's
+// first pickup point. Other marketplaces need an explicit warehouse.
+func defaultWarehouse(conn *domain.MarketplaceConnection) string {
+	if conn.Marketplace != domain.MarketplaceKaspi {
+		return ""
+	}
+	if len(conn.PickupPoints) > 0 {
+		return conn.PickupPoints[0]
+	}
+	return kaspi.DefaultPickupPoint
+}
+
+// newStockCSVReader picks the separator from the header line: spreadsheets in
+// Russian and Kazakh locales export CSV with semicolons
+func newStockCSVReader(file io.Reader) (*csv.Reader, error) {
+	buffered := bufio.NewReader(file)
+
+	firstLine, err := buffered.Peek(4096)
+	if err != nil && err != io.EOF && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidStockCSV, err)
+	}
+	if i := bytes.IndexByte(firstLine, '\n'); i >= 0 {
+		firstLine = firstLine[:i]
+	}
+
+	reader := csv.NewReader(buffered)
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	return reader, nil
+}
+
+// cell returns the row's value at index i, or "" for short rows
+func cell(row []string, i int) string {
+	if i < len(row) {
+		return row[i]
+	}
+	return ""
+}
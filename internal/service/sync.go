@@ -0,0 +1,501 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/seller-assistant/internal/domain"
+	"github.com/yourusername/seller-assistant/internal/marketplace"
+	"github.com/yourusername/seller-assistant/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// maxAuthFailures is how many consecutive unauthorized syncs deactivate a connection
+const maxAuthFailures = 3
+
+const (
+	// orderSyncLookback is how far back the first order sync of a connection reaches
+	orderSyncLookback = 30 * 24 * time.Hour
+	// orderSyncOverlap re-reads orders changed shortly before the last sync, so late status updates are not lost
+	orderSyncOverlap = 24 * time.Hour
+	// salesHistoryDays is how many days of sales history are rebuilt from orders on every sync
+	salesHistoryDays = 7
+)
+
+// SyncService pulls products, orders and reviews from every marketplace connection
+// and derives the daily sales history from the stored orders
+type SyncService struct {
+	connectionRepo   domain.MarketplaceConnectionRepository
+	productRepo      domain.ProductRepository
+	orderRepo        domain.OrderRepository
+	salesHistoryRepo domain.SalesHistoryRepository
+	reviewRepo       domain.ReviewRepository
+	clients          ClientProvider
+	inventoryService *InventoryService
+	feedService      *PriceFeedService
+}
+
+func NewSyncService(
+	connectionRepo domain.MarketplaceConnectionRepository,
+	productRepo domain.ProductRepository,
+	orderRepo domain.OrderRepository,
+	salesHistoryRepo domain.SalesHistoryRepository,
+	reviewRepo domain.ReviewRepository,
+	clients ClientProvider,
+	inventoryService *InventoryService,
+	feedService *PriceFeedService,
+) *SyncService {
+	return &SyncService{
+		connectionRepo:   connectionRepo,
+		productRepo:      productRepo,
+		orderRepo:        orderRepo,
+		salesHistoryRepo: salesHistoryRepo,
+		reviewRepo:       reviewRepo,
+		clients:          clients,
+		inventoryService: inventoryService,
+		feedService:      feedService,
+	}
+}
+
+// SyncAll syncs data for all active marketplace connections
+func (s *SyncService) SyncAll(ctx context.Context) error {
+	conns, err := s.connectionRepo.GetAllActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get active connections: %w", err)
+	}
+
+	logger.Log.Info("Starting marketplace sync", zap.Int("connections_count", len(conns)))
+
+	for _, conn := range conns {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("marketplace sync aborted: %w", err)
+		}
+
+		if err := s.SyncConnection(ctx, &conn); err != nil {
+			logger.Log.Error("Failed to sync connection",
+				zap.String("user_id", conn.UserID),
+				zap.String("connection_id", conn.ID),
+				zap.String("marketplace", conn.Marketplace),
+				zap.Error(err),
+			)
+			// Continue with other connections
+		}
+	}
+
+	logger.Log.Info("Marketplace sync completed")
+	return nil
+}
+
+// SyncConnection syncs data for a single marketplace connection
+func (s *SyncService) SyncConnection(ctx context.Context, conn *domain.MarketplaceConnection) error {
+	client, err := s.clients.ClientFor(conn)
+	if err != nil {
+		return err
+	}
+
+	statsBefore := clientStats(client)
+
+	// Sync products
+	if err := s.syncProducts(ctx, conn, client); err != nil {
+		// A rejected token fails every other step too, so stop here
+		if marketplace.IsAuthError(err) {
+			s.recordAuthFailure(ctx, conn, err)
+			return fmt.Errorf("%s credentials rejected: %w", conn.Marketplace, err)
+		}
+		logger.Log.Error("Failed to sync products", zap.Error(err))
+	} else if err := s.connectionRepo.ResetAuthFailures(ctx, conn.ID); err != nil {
+		logger.Log.Error("Failed to reset auth failures",
+			zap.String("connection_id", conn.ID),
+			zap.Error(err),
+		)
+	} else {
+		conn.AuthFailures = 0
+	}
+
+	// Stop early if the caller went away (HTTP request cancelled, worker shutting down)
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("sync aborted: %w", err)
+	}
+
+	// Sync orders changed since the last run
+	if err := s.syncOrders(ctx, conn, client); err != nil {
+		logger.Log.Error("Failed to sync orders", zap.Error(err))
+	}
+
+	// Rebuild sales history (last 7 days) from the stored orders
+	if err := s.syncSalesHistory(ctx, conn); err != nil {
+		logger.Log.Error("Failed to rebuild sales history", zap.Error(err))
+	}
+
+	// Sync reviews
+	if err := s.syncReviews(ctx, conn, client); err != nil {
+		logger.Log.Error("Failed to sync reviews", zap.Error(err))
+	}
+
+	// Recalculate inventory metrics
+	if err := s.inventoryService.RecalculateAllProducts(ctx, conn.UserID); err != nil {
+		logger.Log.Error("Failed to recalculate inventory", zap.Error(err))
+	}
+
+	// Keep the Kaspi price list in step with the synced stock and prices
+	s.feedService.RegenerateQuietly(ctx, conn)
+
+	conn.LastSyncAt = time.Now()
+	if err := s.connectionRepo.UpdateSyncState(ctx, conn.ID, conn.LastSyncAt); err != nil {
+		logger.Log.Error("Failed to record connection sync time",
+			zap.String("connection_id", conn.ID),
+			zap.Error(err),
+		)
+	}
+
+	stats := clientStats(client)
+	stats.Calls -= statsBefore.Calls
+	stats.Retries -= statsBefore.Retries
+	stats.Throttles -= statsBefore.Throttles
+	stats.Failures -= statsBefore.Failures
+
+	logger.Log.Info("Connection synced successfully",
+		zap.String("user_id", conn.UserID),
+		zap.String("connection_id", conn.ID),
+		zap.String("marketplace", conn.Marketplace),
+		zap.Int64("api_calls", stats.Calls),
+		zap.Int64("api_retries", stats.Retries),
+		zap.Int64("api_throttles", stats.Throttles),
+		zap.Int64("api_failures", stats.Failures),
+	)
+
+	return nil
+}
+
+func (s *SyncService) syncProducts(ctx context.Context, conn *domain.MarketplaceConnection, client marketplace.MarketplaceClient) error {
+	total := 0
+
+	// Upsert page by page so large catalogs are never held in memory at once
+	err := client.ForEachProductPage(ctx, func(products []marketplace.ProductData) error {
+		for _, p := range products {
+			stocks := make([]domain.WarehouseStock, 0, len(p.Stocks))
+			for _, s := range p.Stocks {
+				stocks = append(stocks, domain.WarehouseStock{
+					WarehouseID: s.WarehouseID,
+					Name:        s.Name,
+					City:        s.City,
+					Quantity:    s.Quantity,
+				})
+			}
+
+			product := &domain.Product{
+				UserID:       conn.UserID,
+				ConnectionID: conn.ID,
+				ExternalID:   p.ExternalID,
+				SKU:          p.SKU,
+				Name:         p.Name,
+				CurrentStock: p.CurrentStock,
+				Stocks:       stocks,
+				Price:        p.Price,
+				Currency:     p.Currency,
+				LastSyncAt:   time.Now(),
+			}
+
+			if err := s.productRepo.UpsertProduct(ctx, product); err != nil {
+				logger.Log.Error("Failed to upsert product",
+					zap.String("external_id", p.ExternalID),
+					zap.Error(err),
+				)
+				continue
+			}
+
+			// City prices are merged one by one to keep the min prices set per city
+			for _, cp := range p.CityPrices {
+				if err := s.productRepo.UpsertCityPrice(ctx, conn.ID, p.ExternalID, cp.City, cp.Price); err != nil {
+					logger.Log.Error("Failed to upsert city price",
+						zap.String("external_id", p.ExternalID),
+						zap.String("city", cp.City),
+						zap.Error(err),
+					)
+				}
+			}
+		}
+
+		total += len(products)
+		logger.Log.Debug("Synced products page",
+			zap.String("connection_id", conn.ID),
+			zap.Int("page_count", len(products)),
+			zap.Int("total", total),
+		)
+		return nil
+	})
+
+	logger.Log.Info("Synced products",
+		zap.String("connection_id", conn.ID),
+		zap.Int("count", total),
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to fetch products: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SyncService) syncOrders(ctx context.Context, conn *domain.MarketplaceConnection, client marketplace.MarketplaceClient) error {
+	since := time.Now().Add(-orderSyncLookback)
+	if !conn.LastSyncAt.IsZero() {
+		since = conn.LastSyncAt.Add(-orderSyncOverlap)
+	}
+
+	products, err := s.productRepo.GetByConnectionID(ctx, conn.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get products: %w", err)
+	}
+
+	// Create a map of external ID to product ID
+	productIDMap := make(map[string]string)
+	for _, p := range products {
+		productIDMap[p.ExternalID] = p.ID
+	}
+
+	total := 0
+
+	err = client.ForEachOrderPage(ctx, since, func(orders []marketplace.OrderData) error {
+		for _, o := range orders {
+			lines := make([]domain.OrderLine, 0, len(o.Lines))
+			for _, l := range o.Lines {
+				lines = append(lines, domain.OrderLine{
+					ProductID:         productIDMap[l.ProductExternalID],
+					ProductExternalID: l.ProductExternalID,
+					Name:              l.Name,
+					Quantity:          l.Quantity,
+					Price:             l.Price,
+				})
+			}
+
+			order := &domain.Order{
+				UserID:       conn.UserID,
+				ConnectionID: conn.ID,
+				Marketplace:  conn.Marketplace,
+				ExternalID:   o.ExternalID,
+				Status:       o.Status,
+				CustomerCity: o.CustomerCity,
+				DeliveryType: o.DeliveryType,
+				WarehouseID:  o.WarehouseID,
+				TotalPrice:   o.TotalPrice,
+				Lines:        lines,
+				OrderedAt:    o.CreatedAt,
+				ChangedAt:    o.UpdatedAt,
+				LastSyncAt:   time.Now(),
+			}
+
+			if err := s.orderRepo.UpsertOrder(ctx, order); err != nil {
+				logger.Log.Error("Failed to upsert order",
+					zap.String("external_id", o.ExternalID),
+					zap.Error(err),
+				)
+			}
+		}
+
+		total += len(orders)
+		return nil
+	})
+
+	logger.Log.Info("Synced orders",
+		zap.String("connection_id", conn.ID),
+		zap.Int("count", total),
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to fetch orders: %w", err)
+	}
+
+	return nil
+}
+
+// syncSalesHistory aggregates the connection's stored orders into per-product daily sales.
+// Cancelled and returned orders count as zero, so a day whose only order was cancelled
+// is overwritten instead of keeping the sale it had before.
+func (s *SyncService) syncSalesHistory(ctx context.Context, conn *domain.MarketplaceConnection) error {
+	startDate := time.Now().AddDate(0, 0, -salesHistoryDays).Truncate(24 * time.Hour)
+
+	products, err := s.productRepo.GetByConnectionID(ctx, conn.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get products: %w", err)
+	}
+
+	// Create a map of external ID to product ID
+	productIDMap := make(map[string]string)
+	for _, p := range products {
+		productIDMap[p.ExternalID] = p.ID
+	}
+
+	orders, err := s.orderRepo.GetByConnectionID(ctx, conn.ID, startDate)
+	if err != nil {
+		return fmt.Errorf("failed to get orders: %w", err)
+	}
+
+	// Group sales by product and date
+	salesMap := make(map[string]map[string]*domain.SalesHistory)
+	for _, order := range orders {
+		date := order.OrderedAt.UTC().Truncate(24 * time.Hour)
+		dateKey := date.Format("2006-01-02")
+
+		for _, line := range order.Lines {
+			if _, ok := salesMap[line.ProductExternalID]; !ok {
+				salesMap[line.ProductExternalID] = make(map[string]*domain.SalesHistory)
+			}
+
+			history, ok := salesMap[line.ProductExternalID][dateKey]
+			if !ok {
+				history = &domain.SalesHistory{Date: date}
+				salesMap[line.ProductExternalID][dateKey] = history
+			}
+
+			if order.CountsAsSale() {
+				history.QuantitySold += line.Quantity
+				history.Revenue += line.Price * float64(line.Quantity)
+			}
+		}
+	}
+
+	logger.Log.Info("Rebuilding sales history from orders",
+		zap.String("connection_id", conn.ID),
+		zap.Int("orders", len(orders)),
+	)
+
+	// Save sales history
+	for externalID, dateMap := range salesMap {
+		productID, ok := productIDMap[externalID]
+		if !ok {
+			continue
+		}
+
+		for _, history := range dateMap {
+			history.ProductID = productID
+			history.ConnectionID = conn.ID
+
+			if err := s.salesHistoryRepo.UpsertSalesHistory(ctx, history); err != nil {
+				logger.Log.Error("Failed to upsert sales history",
+					zap.String("product_id", productID),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *SyncService) syncReviews(ctx context.Context, conn *domain.MarketplaceConnection, client marketplace.MarketplaceClient) error {
+	// Get all products of this connection
+	products, err := s.productRepo.GetByConnectionID(ctx, conn.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get products: %w", err)
+	}
+
+	// Create a map of external ID to product ID
+	productIDMap := make(map[string]string)
+	for _, p := range products {
+		productIDMap[p.ExternalID] = p.ID
+	}
+
+	total := 0
+
+	err = client.ForEachReviewPage(ctx, func(reviews []marketplace.ReviewData) error {
+		for _, r := range reviews {
+			productID := ""
+			if pid, ok := productIDMap[r.ProductID]; ok {
+				productID = pid
+			}
+
+			review := &domain.Review{
+				UserID:         conn.UserID,
+				ConnectionID:   conn.ID,
+				ProductID:      productID,
+				ExternalID:     r.ExternalID,
+				AuthorName:     r.AuthorName,
+				Rating:         r.Rating,
+				Comment:        r.Comment,
+				Language:       r.Language,
+				AIResponseSent: false,
+			}
+
+			if err := s.reviewRepo.UpsertReview(ctx, review); err != nil {
+				logger.Log.Error("Failed to upsert review",
+					zap.String("external_id", r.ExternalID),
+					zap.Error(err),
+				)
+			}
+		}
+
+		total += len(reviews)
+		return nil
+	})
+
+	logger.Log.Info("Synced reviews",
+		zap.String("connection_id", conn.ID),
+		zap.Int("count", total),
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+
+	return nil
+}
+
+// recordAuthFailure counts an unauthorized response and deactivates the connection
+// after maxAuthFailures in a row, so GetAllActive stops returning dead credentials
+func (s *SyncService) recordAuthFailure(ctx context.Context, conn *domain.MarketplaceConnection, cause error) {
+	failures, err := s.connectionRepo.IncrementAuthFailures(ctx, conn.ID)
+	if err != nil {
+		logger.Log.Error("Failed to record auth failure",
+			zap.String("connection_id", conn.ID),
+			zap.Error(err),
+		)
+		return
+	}
+	conn.AuthFailures = failures
+
+	if failures < maxAuthFailures {
+		logger.Log.Warn("Marketplace rejected credentials",
+			zap.String("connection_id", conn.ID),
+			zap.String("marketplace", conn.Marketplace),
+			zap.Int("auth_failures", failures),
+			zap.Error(cause),
+		)
+		return
+	}
+
+	reason := fmt.Sprintf("%s rejected the credentials %d times in a row: %v", conn.Marketplace, failures, cause)
+	deactivated, err := s.connectionRepo.Deactivate(ctx, conn.ID, reason)
+	if err != nil {
+		logger.Log.Error("Failed to deactivate connection",
+			zap.String("connection_id", conn.ID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	conn.IsActive = false
+
+	// A concurrent sync already switched it off and logged it
+	if !deactivated {
+		return
+	}
+	conn.DeactivatedReason = reason
+	conn.DeactivatedAt = time.Now()
+
+	logger.Log.Warn("Connection deactivated after repeated auth failures",
+		zap.String("user_id", conn.UserID),
+		zap.String("connection_id", conn.ID),
+		zap.String("marketplace", conn.Marketplace),
+		zap.Int("auth_failures", failures),
+	)
+}
+
+// clientStats returns the client's call counters if it keeps any
+func clientStats(client marketplace.MarketplaceClient) marketplace.ClientStats {
+	if r, ok := client.(marketplace.StatsReporter); ok {
+		return r.Stats()
+	}
+	return marketplace.ClientStats{}
+}